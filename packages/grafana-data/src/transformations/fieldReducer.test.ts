@@ -97,6 +97,17 @@ describe('Stats Calculators', () => {
     expect(stats.delta).toEqual(300);
   });
 
+  it('should calculate median, standard deviation and count of non-null values', () => {
+    const stats = reduceField({
+      field: createField('x', [2, 4, null, 4, 4, 5, 5, 7, 9]),
+      reducers: [ReducerID.median, ReducerID.stdDev, ReducerID.nonNullCount],
+    });
+
+    expect(stats.median).toEqual(4.5);
+    expect(stats.stdDev).toEqual(2);
+    expect(stats.nonNullCount).toEqual(8);
+  });
+
   it('consistently check allIsNull/allIsZero', () => {
     const empty = createField('x');
     const allNull = createField('x', [null, null, null, null]);
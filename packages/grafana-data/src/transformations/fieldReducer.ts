@@ -13,6 +13,9 @@ export enum ReducerID {
   last = 'last',
   first = 'first',
   count = 'count',
+  nonNullCount = 'nonNullCount',
+  median = 'median',
+  stdDev = 'stdDev',
   range = 'range',
   diff = 'diff',
   diffperc = 'diffperc',
@@ -166,6 +169,27 @@ export const fieldReducers = new Registry<FieldReducerInfo>(() => [
     emptyInputResult: 0,
     standard: true,
   },
+  {
+    id: ReducerID.nonNullCount,
+    name: 'Count non-null',
+    description: 'Number of values that are not null',
+    emptyInputResult: 0,
+    standard: true,
+  },
+  {
+    id: ReducerID.median,
+    name: 'Median',
+    description: 'Middle value of the numbers',
+    standard: false,
+    reduce: calculateMedian,
+  },
+  {
+    id: ReducerID.stdDev,
+    name: 'StdDev',
+    description: 'Standard deviation of the numbers',
+    standard: false,
+    reduce: calculateStdDev,
+  },
   {
     id: ReducerID.range,
     name: 'Range',
@@ -451,3 +475,45 @@ function calculateDistinctCount(field: Field, ignoreNulls: boolean, nullAsZero:
   }
   return { distinctCount: distinct.size };
 }
+
+function numericValues(field: Field, ignoreNulls: boolean, nullAsZero: boolean): number[] {
+  const data = field.values;
+  const values: number[] = [];
+  for (let i = 0; i < data.length; i++) {
+    let currentValue = data.get(i);
+    if (currentValue === null) {
+      if (ignoreNulls) {
+        continue;
+      }
+      if (nullAsZero) {
+        currentValue = 0;
+      }
+    }
+    if (isNumber(currentValue)) {
+      values.push(currentValue);
+    }
+  }
+  return values;
+}
+
+function calculateMedian(field: Field, ignoreNulls: boolean, nullAsZero: boolean): FieldCalcs {
+  const values = numericValues(field, ignoreNulls, nullAsZero).sort((a, b) => a - b);
+  if (values.length < 1) {
+    return { median: null };
+  }
+  const middle = Math.floor(values.length / 2);
+  if (values.length % 2 === 0) {
+    return { median: (values[middle - 1] + values[middle]) / 2 };
+  }
+  return { median: values[middle] };
+}
+
+function calculateStdDev(field: Field, ignoreNulls: boolean, nullAsZero: boolean): FieldCalcs {
+  const values = numericValues(field, ignoreNulls, nullAsZero);
+  if (values.length < 1) {
+    return { stdDev: null };
+  }
+  const mean = values.reduce((sum, v) => sum + v, 0) / values.length;
+  const variance = values.reduce((sum, v) => sum + (v - mean) * (v - mean), 0) / values.length;
+  return { stdDev: Math.sqrt(variance) };
+}
@@ -64,6 +64,22 @@ package definitions
 // 403: forbiddenError
 // 500: internalServerError
 
+// swagger:route POST /admin/provisioning/alerting/reload admin_provisioning reloadProvisionedAlerting
+//
+// Reload alerting provisioning configurations.
+//
+// Reloads the provisioning config files for alert rules, contact points, notification policies, mute timings and templates again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:alerting`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+
 // swagger:route POST /admin/provisioning/accesscontrol/reload admin_provisioning reloadProvisionedAccessControl
 //
 // Reload access control provisioning configurations.
@@ -46,6 +46,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/ngalert"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/provisioning"
 	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
@@ -131,6 +132,7 @@ type HTTPServer struct {
 	TeamPermissionsService       *resourcepermissions.Service
 	NotificationService          *notifications.NotificationService
 	DatasourcePermissionsService DatasourcePermissionsService
+	oAuthTokenService            oauthtoken.OAuthTokenService
 }
 
 type ServerOptions struct {
@@ -158,7 +160,8 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	dataSourcesService datasources.DataSourceService, secretsService secrets.Service, queryDataService *query.Service,
 	ldapGroups ldap.Groups, teamGuardian teamguardian.TeamGuardian, serviceaccountsService serviceaccounts.Service,
 	authInfoService login.AuthInfoService, resourcePermissionServices *resourceservices.ResourceServices,
-	notificationService *notifications.NotificationService, datasourcePermissionsService DatasourcePermissionsService) (*HTTPServer, error) {
+	notificationService *notifications.NotificationService, datasourcePermissionsService DatasourcePermissionsService,
+	oAuthTokenService oauthtoken.OAuthTokenService) (*HTTPServer, error) {
 	web.Env = cfg.Env
 	m := web.New()
 
@@ -220,6 +223,7 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		TeamPermissionsService:       resourcePermissionServices.GetTeamService(),
 		NotificationService:          notificationService,
 		DatasourcePermissionsService: datasourcePermissionsService,
+		oAuthTokenService:            oAuthTokenService,
 	}
 	if hs.Listener != nil {
 		hs.log.Debug("Using provided listener")
@@ -353,7 +353,7 @@ func (hs *HTTPServer) CheckHealth(c *models.ReqContext) response.Response {
 //
 // /api/plugins/:pluginId/resources/*
 func (hs *HTTPServer) CallResource(c *models.ReqContext) {
-	hs.callPluginResource(c, web.Params(c.Req)[":pluginId"], "")
+	hs.callPluginResource(c, web.Params(c.Req)[":pluginId"], nil)
 }
 
 func (hs *HTTPServer) GetPluginErrorsList(_ *models.ReqContext) response.Response {
@@ -475,7 +475,12 @@ func mdFilepath(mdFilename string) string {
 	return filepath.Clean(filepath.Join("/", fmt.Sprintf("%s.md", mdFilename)))
 }
 
-func (hs *HTTPServer) callPluginResource(c *models.ReqContext, pluginID, dsUID string) {
+func (hs *HTTPServer) callPluginResource(c *models.ReqContext, pluginID string, ds *models.DataSource) {
+	var dsUID string
+	if ds != nil {
+		dsUID = ds.Uid
+	}
+
 	pCtx, found, err := hs.PluginContextProvider.Get(c.Req.Context(), pluginID, dsUID, c.SignedInUser, false)
 	if err != nil {
 		c.JsonApiErr(500, "Failed to get plugin settings", err)
@@ -509,11 +514,32 @@ func (hs *HTTPServer) callPluginResource(c *models.ReqContext, pluginID, dsUID s
 	}
 	clonedReq.URL = urlPath
 
+	if ds != nil {
+		hs.forwardOAuthIdentity(c, ds, clonedReq)
+	}
+
 	if err = hs.makePluginResourceRequest(c.Resp, clonedReq, pCtx); err != nil {
 		handleCallResourceError(err, c)
 	}
 }
 
+// forwardOAuthIdentity sets the OAuth token of the user in the headers of a resource call to a data source with
+// Forward OAuth Identity, as it is done for the queries and the requests of the data source proxy.
+func (hs *HTTPServer) forwardOAuthIdentity(c *models.ReqContext, ds *models.DataSource, req *http.Request) {
+	if hs.oAuthTokenService == nil || !hs.oAuthTokenService.IsOAuthPassThruEnabled(ds) {
+		return
+	}
+
+	if token := hs.oAuthTokenService.GetCurrentOAuthToken(c.Req.Context(), c.SignedInUser); token != nil {
+		req.Header.Set("Authorization", fmt.Sprintf("%s %s", token.Type(), token.AccessToken))
+
+		idToken, ok := token.Extra("id_token").(string)
+		if ok && idToken != "" {
+			req.Header.Set("X-ID-Token", idToken)
+		}
+	}
+}
+
 func (hs *HTTPServer) makePluginResourceRequest(w http.ResponseWriter, req *http.Request, pCtx backend.PluginContext) error {
 	keepCookieModel := struct {
 		KeepCookies []string `json:"keepCookies"`
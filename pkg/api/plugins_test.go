@@ -10,16 +10,24 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/plugincontext"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func Test_GetPluginAssets(t *testing.T) {
@@ -269,3 +277,88 @@ func (c *fakePluginClient) CallResource(ctx context.Context, req *backend.CallRe
 		Body:    bytes,
 	})
 }
+
+func TestCallPluginResourceOAuthIdentity(t *testing.T) {
+	token := (&oauth2.Token{AccessToken: "access-token", TokenType: "Bearer"}).WithExtra(map[string]interface{}{"id_token": "id-token"})
+
+	callResource := func(t *testing.T, oauthPassThru bool, ds *models.DataSource) *backend.CallResourceRequest {
+		t.Helper()
+		pluginBus := bus.New()
+		pluginBus.AddHandler(func(ctx context.Context, query *models.GetPluginSettingByIdQuery) error {
+			return models.ErrPluginSettingNotFound
+		})
+		pluginClient := &fakePluginClient{}
+		hs := HTTPServer{
+			Cfg:          setting.NewCfg(),
+			log:          log.New(),
+			pluginClient: pluginClient,
+			PluginContextProvider: plugincontext.ProvideService(pluginBus, localcache.New(time.Minute, time.Minute),
+				fakePluginStore{plugins: map[string]plugins.PluginDTO{"prometheus": {JSONData: plugins.JSONData{ID: "prometheus"}}}},
+				&fakeDatasourceCache{ds: ds}, fakes.NewFakeSecretsService(), nil),
+			PluginRequestValidator: &testPluginRequestValidator{},
+			oAuthTokenService:      &fakeOAuthTokenService{passThruEnabled: oauthPassThru, token: token},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/labels?match[]=up", nil)
+		req = web.SetURLParams(req, map[string]string{"*": "labels"})
+		c := &models.ReqContext{
+			Context:      &web.Context{Req: req, Resp: web.NewResponseWriter(http.MethodGet, httptest.NewRecorder())},
+			SignedInUser: &models.SignedInUser{OrgId: 1, UserId: 1},
+		}
+		hs.callPluginResource(c, "prometheus", ds)
+		require.NotNil(t, pluginClient.req)
+		return pluginClient.req
+	}
+	ds := &models.DataSource{Id: 1, Uid: "prometheus", OrgId: 1, Type: "prometheus", JsonData: simplejson.New()}
+
+	t.Run("forwards the OAuth identity of the user to data sources that forward it", func(t *testing.T) {
+		req := callResource(t, true, ds)
+		require.Equal(t, []string{"Bearer access-token"}, req.Headers["Authorization"])
+		require.Equal(t, []string{"id-token"}, req.Headers["X-Id-Token"])
+	})
+
+	t.Run("does not forward the OAuth identity of the user to other data sources", func(t *testing.T) {
+		req := callResource(t, false, ds)
+		require.Empty(t, req.Headers["Authorization"])
+		require.Empty(t, req.Headers["X-Id-Token"])
+	})
+
+	t.Run("does not forward the OAuth identity of the user to plugins that are not data sources", func(t *testing.T) {
+		req := callResource(t, true, nil)
+		require.Nil(t, req.PluginContext.DataSourceInstanceSettings)
+		require.Empty(t, req.Headers["Authorization"])
+		require.Empty(t, req.Headers["X-Id-Token"])
+	})
+}
+
+type testPluginRequestValidator struct{}
+
+func (v *testPluginRequestValidator) Validate(string, *http.Request) error {
+	return nil
+}
+
+type fakeDatasourceCache struct {
+	datasources.CacheService
+
+	ds *models.DataSource
+}
+
+func (c *fakeDatasourceCache) GetDatasourceByUID(_ context.Context, uid string, _ *models.SignedInUser, _ bool) (*models.DataSource, error) {
+	if c.ds == nil || c.ds.Uid != uid {
+		return nil, models.ErrDataSourceNotFound
+	}
+	return c.ds, nil
+}
+
+type fakeOAuthTokenService struct {
+	passThruEnabled bool
+	token           *oauth2.Token
+}
+
+func (s *fakeOAuthTokenService) GetCurrentOAuthToken(context.Context, *models.SignedInUser) *oauth2.Token {
+	return s.token
+}
+
+func (s *fakeOAuthTokenService) IsOAuthPassThruEnabled(*models.DataSource) bool {
+	return s.passThruEnabled
+}
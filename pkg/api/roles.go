@@ -34,6 +34,7 @@ var (
 	ScopeProvisionersPlugins       = accesscontrol.Scope("provisioners", "plugins")
 	ScopeProvisionersDatasources   = accesscontrol.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = accesscontrol.Scope("provisioners", "notifications")
+	ScopeProvisionersAlerting      = accesscontrol.Scope("provisioners", "alerting")
 
 	ScopeDatasourcesAll = accesscontrol.Scope("datasources", "*")
 	ScopeDatasourceID   = accesscontrol.Scope("datasources", "id", accesscontrol.Parameter(":id"))
@@ -172,6 +172,11 @@ var adminCommands = []*cli.Command{
 				Usage:  "Migrates passwords from unsecured fields to secure_json_data field. Return ok unless there is an error. Safe to execute multiple times.",
 				Action: runDbCommand(datamigrations.EncryptDatasourcePasswords),
 			},
+			{
+				Name:   "migrate-dashboard-thumbnails",
+				Usage:  "Moves dashboard thumbnail images from the database to the storage configured in [dashboard_previews]. Returns ok unless there is an error. Safe to execute multiple times.",
+				Action: runDbCommand(datamigrations.MigrateDashboardThumbnails),
+			},
 		},
 	},
 	{
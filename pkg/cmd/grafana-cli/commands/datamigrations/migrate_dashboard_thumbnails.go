@@ -0,0 +1,30 @@
+package datamigrations
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/thumbs"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// MigrateDashboardThumbnails moves the dashboard thumbnail images from the database
+// to the storage configured in [dashboard_previews].
+func MigrateDashboardThumbnails(c utils.CommandLine, sqlStore *sqlstore.SQLStore) error {
+	moved, err := thumbs.MigrateThumbnailsToStorage(context.Background(), sqlStore.Cfg, sqlStore)
+	if moved > 0 {
+		logger.Infof("%s Moved %d dashboard thumbnails to the %s storage\n", color.GreenString("✔"), moved, sqlStore.Cfg.DashboardPreviews.StorageProvider)
+	}
+	if err != nil {
+		return errutil.Wrap("failed to move dashboard thumbnails", err)
+	}
+
+	if moved == 0 {
+		logger.Infof("%s All dashboard thumbnails are already in the %s storage\n", color.GreenString("✔"), sqlStore.Cfg.DashboardPreviews.StorageProvider)
+	}
+	return nil
+}
@@ -48,26 +48,10 @@ func NewS3Uploader(endpoint, region, bucket, path, acl, accessKey, secretKey str
 }
 
 func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string, error) {
-	sess, err := session.NewSession()
+	cfg, err := NewS3Config(u.endpoint, u.region, u.accessKey, u.secretKey, u.pathStyleAccess)
 	if err != nil {
 		return "", err
 	}
-	creds := credentials.NewChainCredentials(
-		[]credentials.Provider{
-			&credentials.StaticProvider{Value: credentials.Value{
-				AccessKeyID:     u.accessKey,
-				SecretAccessKey: u.secretKey,
-			}},
-			&credentials.EnvProvider{},
-			webIdentityProvider(sess),
-			remoteCredProvider(sess),
-		})
-	cfg := &aws.Config{
-		Region:           aws.String(u.region),
-		Endpoint:         aws.String(u.endpoint),
-		S3ForcePathStyle: aws.Bool(u.pathStyleAccess),
-		Credentials:      creds,
-	}
 
 	rand, err := util.GetRandomString(20)
 	if err != nil {
@@ -89,7 +73,7 @@ func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string,
 		}
 	}()
 
-	sess, err = session.NewSession(cfg)
+	sess, err := session.NewSession(cfg)
 	if err != nil {
 		return "", err
 	}
@@ -108,6 +92,32 @@ func (u *S3Uploader) Upload(ctx context.Context, imageDiskPath string) (string,
 	return result.Location, nil
 }
 
+// NewS3Config returns the configuration for accessing an S3 compatible bucket. The static keys
+// are used if set, otherwise the credentials are read from the environment, a web identity
+// token or the ECS/EC2 instance role.
+func NewS3Config(endpoint, region, accessKey, secretKey string, pathStyleAccess bool) (*aws.Config, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	creds := credentials.NewChainCredentials(
+		[]credentials.Provider{
+			&credentials.StaticProvider{Value: credentials.Value{
+				AccessKeyID:     accessKey,
+				SecretAccessKey: secretKey,
+			}},
+			&credentials.EnvProvider{},
+			webIdentityProvider(sess),
+			remoteCredProvider(sess),
+		})
+	return &aws.Config{
+		Region:           aws.String(region),
+		Endpoint:         aws.String(endpoint),
+		S3ForcePathStyle: aws.Bool(pathStyleAccess),
+		Credentials:      creds,
+	}, nil
+}
+
 func webIdentityProvider(sess client.ConfigProvider) credentials.Provider {
 	svc := sts.New(sess)
 
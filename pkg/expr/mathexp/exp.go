@@ -238,6 +238,106 @@ func union(aResults, bResults Results) []*Union {
 	return unions
 }
 
+// matchingUnion creates Union objects for the values of two results that have
+// the same matching labels, as defined by the on or ignoring modifier of a binary
+// operation. Unlike union, the cardinality of the match is enforced: values that
+// are matched more than once on a "one" side, or matches that would produce
+// duplicate label sets, result in an error.
+func matchingUnion(aResults, bResults Results, matching *parse.VectorMatching) ([]*Union, error) {
+	unions := []*Union{}
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return unions, nil
+	}
+
+	// The "one" side of the operation is indexed by the signature of its labels.
+	one, many := bResults, aResults
+	if matching.Card == parse.CardOneToMany {
+		one, many = aResults, bResults
+	}
+	oneBySignature := make(map[string]Value, len(one.Values))
+	for _, v := range one.Values {
+		sig := matchingSignature(v.GetLabels(), matching)
+		if _, ok := oneBySignature[sig]; ok {
+			side := "right"
+			if matching.Card == parse.CardOneToMany {
+				side = "left"
+			}
+			return nil, fmt.Errorf("found duplicate series for the match group {%s} on the %s hand-side of the operation, many-to-many matching is not allowed", sig, side)
+		}
+		oneBySignature[sig] = v
+	}
+
+	matchedSignatures := make(map[string]struct{}, len(many.Values))
+	resultLabels := make(map[string]struct{}, len(many.Values))
+	for _, m := range many.Values {
+		sig := matchingSignature(m.GetLabels(), matching)
+		o, ok := oneBySignature[sig]
+		if !ok {
+			continue
+		}
+		if matching.Card == parse.CardOneToOne {
+			if _, ok := matchedSignatures[sig]; ok {
+				return nil, fmt.Errorf("found duplicate series for the match group {%s} on the left hand-side of the operation, use group_left or group_right for many-to-one matching", sig)
+			}
+			matchedSignatures[sig] = struct{}{}
+		}
+
+		labels := matchingResultLabels(m.GetLabels(), o.GetLabels(), matching)
+		key := labels.String()
+		if _, ok := resultLabels[key]; ok {
+			return nil, fmt.Errorf("multiple matches for labels {%s}, grouping labels must ensure unique matches", key)
+		}
+		resultLabels[key] = struct{}{}
+
+		u := &Union{Labels: labels, A: m, B: o}
+		if matching.Card == parse.CardOneToMany {
+			u.A, u.B = o, m
+		}
+		unions = append(unions, u)
+	}
+	return unions, nil
+}
+
+// matchingSignature returns a key for the labels that are used for matching.
+func matchingSignature(labels data.Labels, matching *parse.VectorMatching) string {
+	return matchingLabels(labels, matching).String()
+}
+
+// matchingLabels returns the subset of labels that are used for matching: only the
+// labels listed in on, or all labels except the ones listed in ignoring.
+func matchingLabels(labels data.Labels, matching *parse.VectorMatching) data.Labels {
+	names := make(map[string]struct{}, len(matching.MatchingLabels))
+	for _, name := range matching.MatchingLabels {
+		names[name] = struct{}{}
+	}
+	result := data.Labels{}
+	for k, v := range labels {
+		if _, ok := names[k]; ok == matching.On {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// matchingResultLabels returns the labels of the result of a matched binary operation.
+// For one-to-one matching these are the matching labels. For many-to-one and one-to-many
+// matching these are the labels of the "many" side, with the included labels copied
+// from the "one" side.
+func matchingResultLabels(many, one data.Labels, matching *parse.VectorMatching) data.Labels {
+	if matching.Card == parse.CardOneToOne {
+		return matchingLabels(many, matching)
+	}
+	result := many.Copy()
+	for _, name := range matching.Include {
+		if v, ok := one[name]; ok {
+			result[name] = v
+		} else {
+			delete(result, name)
+		}
+	}
+	return result
+}
+
 func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	res := Results{Values{}}
 	ar, err := e.walk(node.Args[0])
@@ -248,7 +348,15 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := union(ar, br)
+	var unions []*Union
+	if node.VectorMatching != nil {
+		unions, err = matchingUnion(ar, br, node.VectorMatching)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		unions = union(ar, br)
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
@@ -0,0 +1,132 @@
+package mathexp
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryVectorMatching(t *testing.T) {
+	errors := Results{Values: Values{
+		makeNumber("errors", data.Labels{"host": "a", "code": "500"}, float64Pointer(5)),
+		makeNumber("errors", data.Labels{"host": "a", "code": "503"}, float64Pointer(2)),
+		makeNumber("errors", data.Labels{"host": "b", "code": "500"}, float64Pointer(1)),
+	}}
+	requests := Results{Values: Values{
+		makeNumber("requests", data.Labels{"host": "a", "team": "x"}, float64Pointer(100)),
+		makeNumber("requests", data.Labels{"host": "b", "team": "y"}, float64Pointer(50)),
+	}}
+
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+		errIs   string
+	}{
+		{
+			name: "one-to-one on matching labels",
+			expr: "$A / on(host) $B",
+			vars: Vars{
+				"A": Results{Values: Values{
+					makeNumber("", data.Labels{"host": "a", "job": "api"}, float64Pointer(10)),
+					makeNumber("", data.Labels{"host": "b", "job": "api"}, float64Pointer(20)),
+				}},
+				"B": requests,
+			},
+			results: Results{Values: Values{
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(0.1)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(0.4)),
+			}},
+		},
+		{
+			name: "one-to-one ignoring labels",
+			expr: "$A - ignoring(job) $B",
+			vars: Vars{
+				"A": Results{Values: Values{
+					makeNumber("", data.Labels{"host": "a", "job": "api"}, float64Pointer(10)),
+				}},
+				"B": Results{Values: Values{
+					makeNumber("", data.Labels{"host": "a", "job": "db"}, float64Pointer(4)),
+					makeNumber("", data.Labels{"host": "b", "job": "db"}, float64Pointer(1)),
+				}},
+			},
+			results: Results{Values: Values{
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(6)),
+			}},
+		},
+		{
+			name: "many-to-one with group_left keeps labels of the left side",
+			expr: "$A / on(host) group_left $B",
+			vars: Vars{"A": errors, "B": requests},
+			results: Results{Values: Values{
+				makeNumber("", data.Labels{"host": "a", "code": "500"}, float64Pointer(0.05)),
+				makeNumber("", data.Labels{"host": "a", "code": "503"}, float64Pointer(0.02)),
+				makeNumber("", data.Labels{"host": "b", "code": "500"}, float64Pointer(0.02)),
+			}},
+		},
+		{
+			name: "many-to-one with group_left copies included labels",
+			expr: "$A / on(host) group_left(team) $B",
+			vars: Vars{"A": errors, "B": requests},
+			results: Results{Values: Values{
+				makeNumber("", data.Labels{"host": "a", "code": "500", "team": "x"}, float64Pointer(0.05)),
+				makeNumber("", data.Labels{"host": "a", "code": "503", "team": "x"}, float64Pointer(0.02)),
+				makeNumber("", data.Labels{"host": "b", "code": "500", "team": "y"}, float64Pointer(0.02)),
+			}},
+		},
+		{
+			name: "one-to-many with group_right keeps the order of the operands",
+			expr: "$A / on(host) group_right $B",
+			vars: Vars{"A": requests, "B": errors},
+			results: Results{Values: Values{
+				makeNumber("", data.Labels{"host": "a", "code": "500"}, float64Pointer(20)),
+				makeNumber("", data.Labels{"host": "a", "code": "503"}, float64Pointer(50)),
+				makeNumber("", data.Labels{"host": "b", "code": "500"}, float64Pointer(50)),
+			}},
+		},
+		{
+			name:  "many-to-one without group modifier fails",
+			expr:  "$A / on(host) $B",
+			vars:  Vars{"A": errors, "B": requests},
+			errIs: "use group_left or group_right",
+		},
+		{
+			name:  "many-to-many fails",
+			expr:  "$A / on(host) group_left $B",
+			vars:  Vars{"A": errors, "B": errors},
+			errIs: "many-to-many matching is not allowed",
+		},
+		{
+			name: "series are matched by labels",
+			expr: "$A + ignoring(job) $B",
+			vars: Vars{
+				"A": Results{Values: Values{
+					makeSeries("", data.Labels{"host": "a", "job": "api"}, tp{unixTimePointer(5, 0).UTC(), float64Pointer(1)}),
+				}},
+				"B": Results{Values: Values{
+					makeSeries("", data.Labels{"host": "a", "job": "db"}, tp{unixTimePointer(5, 0).UTC(), float64Pointer(2)}),
+				}},
+			},
+			results: Results{Values: Values{
+				makeSeries("", data.Labels{"host": "a"}, tp{unixTimePointer(5, 0).UTC(), float64Pointer(3)}),
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New(test.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", test.vars)
+			if test.errIs != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.errIs)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, test.results.Values, res.Values)
+		})
+	}
+}
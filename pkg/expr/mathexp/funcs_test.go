@@ -159,3 +159,105 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestMathFuncs(t *testing.T) {
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+	}{
+		{
+			name:    "sqrt on scalar",
+			expr:    "sqrt(16)",
+			vars:    Vars{},
+			results: Results{[]Value{NewScalar("", float64Pointer(4))}},
+		},
+		{
+			name:    "exp on scalar",
+			expr:    "exp(0)",
+			vars:    Vars{},
+			results: Results{[]Value{NewScalar("", float64Pointer(1))}},
+		},
+		{
+			name: "log10 on number",
+			expr: "log10($A)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeNumber("", nil, float64Pointer(1000)),
+					},
+				},
+			},
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(3))}},
+		},
+		{
+			name: "clamp_min on series",
+			expr: "clamp_min($A, 2)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeSeries("", nil, tp{
+							time.Unix(5, 0), float64Pointer(1),
+						}, tp{
+							time.Unix(10, 0), float64Pointer(3),
+						}),
+					},
+				},
+			},
+			results: Results{
+				[]Value{
+					makeSeries("", nil, tp{
+						time.Unix(5, 0), float64Pointer(2),
+					}, tp{
+						time.Unix(10, 0), float64Pointer(3),
+					}),
+				},
+			},
+		},
+		{
+			name: "clamp_max on number with negative bound",
+			expr: "clamp_max($A, -1)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeNumber("", nil, float64Pointer(5)),
+					},
+				},
+			},
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(-1))}},
+		},
+		{
+			name: "clamp_max on number lower than the bound",
+			expr: "clamp_max($A, 10)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeNumber("", nil, float64Pointer(5)),
+					},
+				},
+			},
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(5))}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars)
+				require.NoError(t, err)
+				require.Equal(t, tt.results, res)
+			}
+		})
+	}
+}
+
+func TestClampFuncsRequireScalarBound(t *testing.T) {
+	_, err := New("clamp_min($A, $B)")
+	require.Error(t, err)
+
+	_, err = New("clamp_max($A)")
+	require.Error(t, err)
+}
@@ -113,6 +113,18 @@ var lexTests = []lexTest{
 		{itemVar, 0, "$A"},
 		tEOF,
 	}},
+	{"func with digits and multiple arguments", "clamp_min(log10($A), 2)", []item{
+		{itemFunc, 0, "clamp_min"},
+		{itemLeftParen, 0, "("},
+		{itemFunc, 0, "log10"},
+		{itemLeftParen, 0, "("},
+		{itemVar, 0, "$A"},
+		{itemRightParen, 0, ")"},
+		{itemComma, 0, ","},
+		{itemNumber, 0, "2"},
+		{itemRightParen, 0, ")"},
+		tEOF,
+	}},
 	// errors
 	{"unclosed quote", "\"", []item{
 		{itemError, 0, "unterminated string"},
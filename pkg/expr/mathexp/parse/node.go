@@ -9,6 +9,7 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -291,6 +292,55 @@ func (s *StringNode) Return() ReturnType {
 	return TypeString
 }
 
+// MatchCardinality describes how many values on each side of a binary
+// operation may share the same set of matching labels.
+type MatchCardinality int
+
+const (
+	// CardOneToOne requires a single value on each side for every set of matching labels.
+	CardOneToOne MatchCardinality = iota
+	// CardManyToOne allows many values on the left side for a value on the right side (group_left).
+	CardManyToOne
+	// CardOneToMany allows many values on the right side for a value on the left side (group_right).
+	CardOneToMany
+)
+
+// VectorMatching describes how the values of the two sides of a binary
+// operation are matched by their labels, e.g. $A / on(host) group_left $B.
+type VectorMatching struct {
+	// Card is the cardinality of the match.
+	Card MatchCardinality
+	// On is true if MatchingLabels are the only labels to match on (on),
+	// false if they are the labels to ignore (ignoring).
+	On bool
+	// MatchingLabels are the label names of the on or ignoring modifier.
+	MatchingLabels []string
+	// Include are the label names to copy from the "one" side to the result
+	// in many-to-one and one-to-many matches.
+	Include []string
+}
+
+// String returns the string representation of the VectorMatching.
+func (m *VectorMatching) String() string {
+	modifier := "ignoring"
+	if m.On {
+		modifier = "on"
+	}
+	s := fmt.Sprintf("%s(%s)", modifier, strings.Join(m.MatchingLabels, ", "))
+	switch m.Card {
+	case CardManyToOne:
+		s += " group_left"
+	case CardOneToMany:
+		s += " group_right"
+	default:
+		return s
+	}
+	if len(m.Include) > 0 {
+		s += fmt.Sprintf("(%s)", strings.Join(m.Include, ", "))
+	}
+	return s
+}
+
 // BinaryNode holds two arguments and an operator.
 type BinaryNode struct {
 	NodeType
@@ -298,6 +348,9 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	// VectorMatching is nil if the expression has no on or ignoring modifier,
+	// in which case values are matched by the subset of their labels.
+	VectorMatching *VectorMatching
 }
 
 func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
@@ -306,16 +359,35 @@ func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.VectorMatching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.VectorMatching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
 // StringAST returns the string representation of abstract syntax tree of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) StringAST() string {
+	if b.VectorMatching != nil {
+		return fmt.Sprintf("%s %s(%s, %s)", b.Operator.val, b.VectorMatching, b.Args[0], b.Args[1])
+	}
 	return fmt.Sprintf("%s(%s, %s)", b.Operator.val, b.Args[0], b.Args[1])
 }
 
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
+	if b.VectorMatching == nil {
+		return nil
+	}
+	for _, arg := range b.Args {
+		if rt := arg.Return(); rt != TypeNumberSet && rt != TypeSeriesSet && rt != TypeVariantSet {
+			return fmt.Errorf("parse: label matching in %s is only allowed between %v or %v, got %v", b, TypeNumberSet, TypeSeriesSet, rt)
+		}
+	}
+	for _, arg := range b.Args {
+		if err := arg.Check(t); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
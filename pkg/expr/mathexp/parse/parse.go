@@ -175,8 +175,101 @@ F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
 param -> number | "string" | queryVar
+
+Every binary operator may be followed by a label matching modifier:
+matching -> ("on" | "ignoring") labels [("group_left" | "group_right") [labels]]
+labels -> "(" [name {"," name}] ")"
 */
 
+// binary creates a BinaryNode for the operator with the left operand and the
+// right operand parsed by next, reading the label matching modifier in between if any.
+func (t *Tree) binary(operator item, left Node, next func() Node) Node {
+	matching := t.vectorMatching()
+	n := newBinary(operator, left, next())
+	n.VectorMatching = matching
+	return n
+}
+
+// vectorMatching parses the optional on, ignoring, group_left and group_right
+// modifiers of a binary operation.
+func (t *Tree) vectorMatching() *VectorMatching {
+	token := t.peek()
+	if token.typ != itemFunc {
+		return nil
+	}
+	var m *VectorMatching
+	switch token.val {
+	case "on":
+		m = &VectorMatching{On: true}
+	case "ignoring":
+		m = &VectorMatching{}
+	case "group_left", "group_right":
+		t.errorf("%s must follow on or ignoring", token.val)
+	default:
+		return nil
+	}
+	t.next()
+	m.MatchingLabels = t.labelList(token.val)
+
+	token = t.peek()
+	if token.typ != itemFunc {
+		return m
+	}
+	switch token.val {
+	case "group_left":
+		m.Card = CardManyToOne
+	case "group_right":
+		m.Card = CardOneToMany
+	default:
+		return m
+	}
+	t.next()
+	if t.peek().typ == itemLeftParen {
+		m.Include = t.labelList(token.val)
+	}
+	for _, l := range m.Include {
+		for _, ml := range m.MatchingLabels {
+			if m.On && l == ml {
+				t.errorf("label %q must not occur in on and %s at the same time", l, token.val)
+			}
+		}
+	}
+	return m
+}
+
+// labelList parses a parenthesized list of label names for the modifier.
+func (t *Tree) labelList(modifier string) []string {
+	t.expect(itemLeftParen, modifier)
+	labels := []string{}
+	for {
+		switch token := t.next(); token.typ {
+		case itemFunc:
+			labels = append(labels, token.val)
+		case itemString:
+			l, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			labels = append(labels, l)
+		case itemRightParen:
+			if len(labels) == 0 {
+				return labels
+			}
+			t.unexpected(token, modifier)
+		default:
+			t.unexpected(token, modifier)
+		}
+		switch token := t.next(); token.typ {
+		case itemComma:
+			// continue with the next label
+		case itemRightParen:
+			return labels
+		default:
+			t.errorf("unexpected %s in %s, expected , or )", token, modifier)
+		}
+	}
+}
+
 // expr:
 
 // O is A {"||" A} in the grammar.
@@ -185,7 +278,7 @@ func (t *Tree) O() Node {
 	for {
 		switch t.peek().typ {
 		case itemOr:
-			n = newBinary(t.next(), n, t.A())
+			n = t.binary(t.next(), n, t.A)
 		default:
 			return n
 		}
@@ -198,7 +291,7 @@ func (t *Tree) A() Node {
 	for {
 		switch t.peek().typ {
 		case itemAnd:
-			n = newBinary(t.next(), n, t.C())
+			n = t.binary(t.next(), n, t.C)
 		default:
 			return n
 		}
@@ -211,7 +304,7 @@ func (t *Tree) C() Node {
 	for {
 		switch t.peek().typ {
 		case itemEq, itemNotEq, itemGreater, itemGreaterEq, itemLess, itemLessEq:
-			n = newBinary(t.next(), n, t.P())
+			n = t.binary(t.next(), n, t.P)
 		default:
 			return n
 		}
@@ -224,7 +317,7 @@ func (t *Tree) P() Node {
 	for {
 		switch t.peek().typ {
 		case itemPlus, itemMinus:
-			n = newBinary(t.next(), n, t.M())
+			n = t.binary(t.next(), n, t.M)
 		default:
 			return n
 		}
@@ -237,7 +330,7 @@ func (t *Tree) M() Node {
 	for {
 		switch t.peek().typ {
 		case itemMult, itemDiv, itemMod:
-			n = newBinary(t.next(), n, t.E())
+			n = t.binary(t.next(), n, t.E)
 		default:
 			return n
 		}
@@ -250,7 +343,7 @@ func (t *Tree) E() Node {
 	for {
 		switch t.peek().typ {
 		case itemPow:
-			n = newBinary(t.next(), n, t.F())
+			n = t.binary(t.next(), n, t.F)
 		default:
 			return n
 		}
@@ -332,6 +425,14 @@ func (t *Tree) Func() (f *FuncNode) {
 		case itemRightParen:
 			return
 		}
+		switch token = t.next(); token.typ {
+		case itemComma:
+			// continue with the next argument
+		case itemRightParen:
+			return
+		default:
+			t.errorf("unexpected %s in func, expected , or )", token)
+		}
 	}
 }
 
@@ -0,0 +1,83 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVectorMatching(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		matching *VectorMatching
+		output   string
+		errIs    string
+	}{
+		{
+			name:   "no modifier",
+			input:  "$A / $B",
+			output: "$A / $B",
+		},
+		{
+			name:     "on",
+			input:    "$A / on(host, job) $B",
+			matching: &VectorMatching{On: true, MatchingLabels: []string{"host", "job"}},
+			output:   "$A / on(host, job) $B",
+		},
+		{
+			name:     "ignoring with empty list",
+			input:    "$A > ignoring() $B",
+			matching: &VectorMatching{MatchingLabels: []string{}},
+			output:   "$A > ignoring() $B",
+		},
+		{
+			name:     "ignoring with group_left and quoted label",
+			input:    `$A * ignoring("status_code") group_left $B`,
+			matching: &VectorMatching{Card: CardManyToOne, MatchingLabels: []string{"status_code"}},
+			output:   "$A * ignoring(status_code) group_left $B",
+		},
+		{
+			name:     "on with group_right and included labels",
+			input:    "${A} - on(host) group_right(team, _owner) ${B}",
+			matching: &VectorMatching{Card: CardOneToMany, On: true, MatchingLabels: []string{"host"}, Include: []string{"team", "_owner"}},
+			output:   "${A} - on(host) group_right(team, _owner) ${B}",
+		},
+		{
+			name:  "group modifier without on or ignoring",
+			input: "$A / group_left $B",
+			errIs: "group_left must follow on or ignoring",
+		},
+		{
+			name:  "label in on and group_left",
+			input: "$A / on(host) group_left(host) $B",
+			errIs: `label "host" must not occur in on and group_left at the same time`,
+		},
+		{
+			name:  "missing label list",
+			input: "$A / on $B",
+			errIs: "unexpected",
+		},
+		{
+			name:  "matching with a scalar",
+			input: "$A / on(host) 2",
+			errIs: "label matching in $A / on(host) 2 is only allowed between",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree, err := Parse(test.input)
+			if test.errIs != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.errIs)
+				return
+			}
+			require.NoError(t, err)
+			node, ok := tree.Root.(*BinaryNode)
+			require.True(t, ok)
+			require.Equal(t, test.matching, node.VectorMatching)
+			require.Equal(t, test.output, tree.String())
+		})
+	}
+}
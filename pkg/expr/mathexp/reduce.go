@@ -3,6 +3,9 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,7 +84,134 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// CountNonNull returns the number of points that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a reducer for the p-th percentile (0 <= p <= 100) of the points,
+// linearly interpolated between the two closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values, ok := sortedValues(fv)
+		if !ok {
+			nan := math.NaN()
+			return &nan
+		}
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// StdDev returns the population standard deviation of the points.
+func StdDev(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	avg := *Avg(fv)
+	if math.IsNaN(avg) {
+		return &avg
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - avg
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+// Diff returns the difference between the last and the first point.
+func Diff(fv *Float64Field) *float64 {
+	first, last := First(fv), Last(fv)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Delta returns the total increase of a counter, a decrease is considered a counter reset
+// so the value after the reset is added.
+func Delta(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			nan := math.NaN()
+			return &nan
+		}
+		if i == 0 {
+			continue
+		}
+		prev := *fv.GetValue(i - 1)
+		if *v >= prev {
+			f += *v - prev
+		} else {
+			f += *v
+		}
+	}
+	return &f
+}
+
+// sortedValues returns the values in ascending order, or false if the field is empty
+// or has a null or NaN value.
+func sortedValues(fv *Float64Field) ([]float64, bool) {
+	if fv.Len() == 0 {
+		return nil, false
+	}
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return nil, false
+		}
+		values = append(values, *v)
+	}
+	sort.Float64s(values)
+	return values, true
+}
+
+var percentileRe = regexp.MustCompile(`^percentile\(\s*([0-9]*\.?[0-9]+)\s*\)$`)
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
+	if m := percentileRe.FindStringSubmatch(strings.ToLower(rFunc)); m != nil {
+		p, err := strconv.ParseFloat(m[1], 64)
+		if err != nil || p > 100 {
+			return nil, fmt.Errorf("invalid percentile %v, must be between 0 and 100", m[1])
+		}
+		return Percentile(p), nil
+	}
+
 	switch strings.ToLower(rFunc) {
 	case "sum":
 		return Sum, nil
@@ -95,6 +225,19 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	// nonNullCount, like stdDev, is the ID of the reducer in the frontend
+	case "count_non_null", "nonnullcount":
+		return CountNonNull, nil
+	case "median":
+		return Median, nil
+	case "stddev":
+		return StdDev, nil
+	case "diff":
+		return Diff, nil
+	case "delta":
+		return Delta, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
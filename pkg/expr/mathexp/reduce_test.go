@@ -514,3 +514,77 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesReduceFuncs(t *testing.T) {
+	series := makeSeries("temp", nil, tp{
+		time.Unix(5, 0), float64Pointer(3),
+	}, tp{
+		time.Unix(10, 0), float64Pointer(1),
+	}, tp{
+		time.Unix(15, 0), float64Pointer(4),
+	}, tp{
+		time.Unix(20, 0), float64Pointer(2),
+	}, tp{
+		time.Unix(25, 0), float64Pointer(5),
+	})
+	seriesWithNaN := makeSeries("temp", nil, tp{
+		time.Unix(5, 0), float64Pointer(3),
+	}, tp{
+		time.Unix(10, 0), NaN,
+	})
+
+	var tests = []struct {
+		name   string
+		red    string
+		series Series
+		result *float64
+	}{
+		{name: "first", red: "first", series: series, result: float64Pointer(3)},
+		{name: "first of empty series", red: "first", series: makeSeries("temp", nil), result: NaN},
+		{name: "median", red: "median", series: series, result: float64Pointer(3)},
+		{name: "median of even number of points", red: "median", series: makeSeries("temp", nil, tp{time.Unix(5, 0), float64Pointer(1)}, tp{time.Unix(10, 0), float64Pointer(2)}), result: float64Pointer(1.5)},
+		{name: "median with NaN", red: "median", series: seriesWithNaN, result: NaN},
+		{name: "percentile", red: "percentile(75)", series: series, result: float64Pointer(4)},
+		{name: "percentile interpolates between points", red: "percentile(90)", series: series, result: float64Pointer(4.6)},
+		{name: "percentile 0 is min", red: "percentile(0)", series: series, result: float64Pointer(1)},
+		{name: "percentile of empty series", red: "percentile(50)", series: makeSeries("temp", nil), result: NaN},
+		{name: "stddev", red: "stddev", series: series, result: float64Pointer(math.Sqrt(2))},
+		{name: "stddev with NaN", red: "stddev", series: seriesWithNaN, result: NaN},
+		{name: "count_non_null", red: "count_non_null", series: seriesWithNaN, result: float64Pointer(1)},
+		{name: "diff", red: "diff", series: series, result: float64Pointer(2)},
+		{name: "diff of empty series", red: "diff", series: makeSeries("temp", nil), result: NaN},
+		{name: "delta counts resets as increase", red: "delta", series: series, result: float64Pointer(1 + 3 + 2 + 3)},
+		{name: "delta with NaN", red: "delta", series: seriesWithNaN, result: NaN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num, err := tt.series.Reduce("", tt.red, nil)
+			require.NoError(t, err)
+			opt := cmp.Comparer(func(x, y float64) bool {
+				return (math.IsNaN(x) && math.IsNaN(y)) || math.Abs(x-y) < 1e-9
+			})
+			if diff := cmp.Diff(tt.result, num.GetFloat64Value(), opt); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetReduceFuncPercentile(t *testing.T) {
+	_, err := GetReduceFunc("percentile(99.9)")
+	require.NoError(t, err)
+
+	_, err = GetReduceFunc("percentile(101)")
+	require.Error(t, err)
+
+	_, err = GetReduceFunc("percentile()")
+	require.Error(t, err)
+}
+
+func TestGetReduceFuncFrontendIDs(t *testing.T) {
+	for _, id := range []string{"median", "stdDev", "nonNullCount"} {
+		_, err := GetReduceFunc(id)
+		require.NoError(t, err, id)
+	}
+}
@@ -0,0 +1,138 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// ThresholdCommand is an expression command that compares each value of its input
+// to a threshold or a range. The result is 1 if the condition is met and 0 otherwise,
+// the labels of the input series and numbers are kept.
+type ThresholdCommand struct {
+	ReferenceVar  string
+	ThresholdFunc string
+	Conditions    []float64
+	refID         string
+	mathCommand   *MathCommand
+}
+
+const (
+	ThresholdIsAbove        = "gt"
+	ThresholdIsBelow        = "lt"
+	ThresholdIsWithinRange  = "within_range"
+	ThresholdIsOutsideRange = "outside_range"
+)
+
+// NewThresholdCommand creates a new ThresholdCommand. It returns an error if the threshold
+// function is unknown or the number of conditions does not match it.
+func NewThresholdCommand(refID, referenceVar, thresholdFunc string, conditions []float64) (*ThresholdCommand, error) {
+	var expected int
+	switch thresholdFunc {
+	case ThresholdIsAbove, ThresholdIsBelow:
+		expected = 1
+	case ThresholdIsWithinRange, ThresholdIsOutsideRange:
+		expected = 2
+	default:
+		return nil, fmt.Errorf("expected threshold function to be one of [%s, %s, %s, %s], got %q", ThresholdIsAbove, ThresholdIsBelow, ThresholdIsWithinRange, ThresholdIsOutsideRange, thresholdFunc)
+	}
+	if len(conditions) != expected {
+		return nil, fmt.Errorf("threshold function %s expects %d values, got %d", thresholdFunc, expected, len(conditions))
+	}
+	if expected == 2 && conditions[0] > conditions[1] {
+		return nil, fmt.Errorf("invalid range for threshold function %s, %v is greater than %v", thresholdFunc, conditions[0], conditions[1])
+	}
+
+	mathCommand, err := NewMathCommand(refID, thresholdExpression(referenceVar, thresholdFunc, conditions))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThresholdCommand{
+		ReferenceVar:  referenceVar,
+		ThresholdFunc: thresholdFunc,
+		Conditions:    conditions,
+		refID:         refID,
+		mathCommand:   mathCommand,
+	}, nil
+}
+
+// thresholdExpression builds the math expression that evaluates the threshold function.
+// The bounds of ranges are excluded.
+func thresholdExpression(referenceVar, thresholdFunc string, conditions []float64) string {
+	v := "${" + referenceVar + "}"
+	f := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	switch thresholdFunc {
+	case ThresholdIsAbove:
+		return fmt.Sprintf("%s > %s", v, f(conditions[0]))
+	case ThresholdIsBelow:
+		return fmt.Sprintf("%s < %s", v, f(conditions[0]))
+	case ThresholdIsWithinRange:
+		return fmt.Sprintf("%s > %s && %s < %s", v, f(conditions[0]), v, f(conditions[1]))
+	case ThresholdIsOutsideRange:
+		return fmt.Sprintf("%s < %s || %s > %s", v, f(conditions[0]), v, f(conditions[1]))
+	default:
+		return ""
+	}
+}
+
+type thresholdConditionJSON struct {
+	Evaluator struct {
+		Type   string    `json:"type"`
+		Params []float64 `json:"params"`
+	} `json:"evaluator"`
+}
+
+// UnmarshalThresholdCommand creates a ThresholdCommand from Grafana's frontend query.
+func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
+	rawVar, ok := rn.Query["expression"]
+	if !ok {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", rn.RefID)
+	}
+	referenceVar, ok := rawVar.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected threshold variable to be a string, got %T for refId %v", rawVar, rn.RefID)
+	}
+	referenceVar = strings.TrimPrefix(referenceVar, "$")
+
+	rawConditions, ok := rn.Query["conditions"]
+	if !ok {
+		return nil, fmt.Errorf("no conditions specified for threshold in refId %v", rn.RefID)
+	}
+	b, err := json.Marshal(rawConditions)
+	if err != nil {
+		return nil, err
+	}
+	var conditions []thresholdConditionJSON
+	if err := json.Unmarshal(b, &conditions); err != nil {
+		return nil, fmt.Errorf("failed to parse threshold conditions for refId %v: %w", rn.RefID, err)
+	}
+	if len(conditions) != 1 {
+		return nil, fmt.Errorf("threshold expression requires exactly one condition, got %d for refId %v", len(conditions), rn.RefID)
+	}
+
+	evaluator := conditions[0].Evaluator
+	cmd, err := NewThresholdCommand(rn.RefID, referenceVar, evaluator.Type, evaluator.Params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid threshold command in refId %v: %w", rn.RefID, err)
+	}
+	return cmd, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (tc *ThresholdCommand) NeedsVars() []string {
+	return []string{tc.ReferenceVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (tc *ThresholdCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	return tc.mathCommand.Execute(ctx, vars)
+}
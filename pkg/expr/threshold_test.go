@@ -0,0 +1,141 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func TestNewThresholdCommand(t *testing.T) {
+	var tests = []struct {
+		name          string
+		thresholdFunc string
+		conditions    []float64
+		expression    string
+		isError       bool
+	}{
+		{
+			name:          "above",
+			thresholdFunc: ThresholdIsAbove,
+			conditions:    []float64{80},
+			expression:    "${A} > 80",
+		},
+		{
+			name:          "below",
+			thresholdFunc: ThresholdIsBelow,
+			conditions:    []float64{-0.5},
+			expression:    "${A} < -0.5",
+		},
+		{
+			name:          "within range",
+			thresholdFunc: ThresholdIsWithinRange,
+			conditions:    []float64{80, 100},
+			expression:    "${A} > 80 && ${A} < 100",
+		},
+		{
+			name:          "outside range",
+			thresholdFunc: ThresholdIsOutsideRange,
+			conditions:    []float64{80, 100},
+			expression:    "${A} < 80 || ${A} > 100",
+		},
+		{
+			name:          "error when function is unknown",
+			thresholdFunc: "eq",
+			conditions:    []float64{80},
+			isError:       true,
+		},
+		{
+			name:          "error when range has one value",
+			thresholdFunc: ThresholdIsWithinRange,
+			conditions:    []float64{80},
+			isError:       true,
+		},
+		{
+			name:          "error when range is reversed",
+			thresholdFunc: ThresholdIsOutsideRange,
+			conditions:    []float64{100, 80},
+			isError:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := NewThresholdCommand("B", "A", test.thresholdFunc, test.conditions)
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+			require.Equal(t, test.expression, cmd.mathCommand.RawExpression)
+		})
+	}
+}
+
+func TestUnmarshalThresholdCommand(t *testing.T) {
+	q := `{ "expression" : "$A", "conditions": [ { "evaluator": { "type": "within_range", "params": [80, 100] } } ] }`
+	var qmap = make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+
+	cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: qmap})
+	require.NoError(t, err)
+	require.Equal(t, "A", cmd.ReferenceVar)
+	require.Equal(t, ThresholdIsWithinRange, cmd.ThresholdFunc)
+	require.Equal(t, []float64{80, 100}, cmd.Conditions)
+
+	q = `{ "expression" : "$A" }`
+	qmap = make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+	_, err = UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: qmap})
+	require.Error(t, err)
+}
+
+func TestThresholdCommandExecute(t *testing.T) {
+	number := func(labels data.Labels, v float64) mathexp.Number {
+		n := mathexp.NewNumber("", labels)
+		n.SetValue(&v)
+		return n
+	}
+
+	cmd, err := NewThresholdCommand("B", "A", ThresholdIsWithinRange, []float64{80, 100})
+	require.NoError(t, err)
+
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{
+			number(data.Labels{"host": "a"}, 90),
+			number(data.Labels{"host": "b"}, 120),
+		}},
+	}
+	res, err := cmd.Execute(context.Background(), vars)
+	require.NoError(t, err)
+	require.Len(t, res.Values, 2)
+
+	got := map[string]float64{}
+	for _, v := range res.Values {
+		n, ok := v.(mathexp.Number)
+		require.True(t, ok)
+		got[n.GetLabels()["host"]] = *n.GetFloat64Value()
+	}
+	require.Equal(t, map[string]float64{"a": 1, "b": 0}, got)
+
+	series := mathexp.NewSeries("", data.Labels{"host": "a"}, 2)
+	v1, v2 := 50.0, 90.0
+	series.SetPoint(0, time.Unix(0, 0), &v1)
+	series.SetPoint(1, time.Unix(60, 0), &v2)
+	res, err = cmd.Execute(context.Background(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{series}}})
+	require.NoError(t, err)
+	require.Len(t, res.Values, 1)
+	s, ok := res.Values[0].(mathexp.Series)
+	require.True(t, ok)
+	require.Equal(t, data.Labels{"host": "a"}, s.GetLabels())
+	_, p0 := s.GetPoint(0)
+	_, p1 := s.GetPoint(1)
+	require.Equal(t, 0.0, *p0)
+	require.Equal(t, 1.0, *p1)
+}
@@ -86,6 +86,8 @@ type DashboardThumbnail struct {
 	Image            []byte         `json:"image"`
 	MimeType         string         `json:"mimeType"`
 	Updated          time.Time      `json:"updated"`
+	// StorageKey is the key of the image in the external thumbnail storage. Image is empty when it is set.
+	StorageKey string `json:"storageKey,omitempty"`
 }
 
 //
@@ -129,10 +131,34 @@ type SaveDashboardThumbnailCommand struct {
 	DashboardVersion int
 	Image            []byte
 	MimeType         string
+	StorageKey       string
 
 	Result *DashboardThumbnail
 }
 
+// DashboardThumbnailInDatabase is a thumbnail whose image is still stored in the database
+type DashboardThumbnailInDatabase struct {
+	Id           int64
+	DashboardUID string `xorm:"dashboard_uid"`
+	OrgId        int64
+	PanelId      int64
+	Kind         ThumbnailKind
+	Theme        Theme
+	MimeType     string
+	Image        []byte
+}
+
+type FindThumbnailsInDatabaseQuery struct {
+	Limit int
+
+	Result []*DashboardThumbnailInDatabase
+}
+
+type MoveThumbnailToStorageCommand struct {
+	Id         int64
+	StorageKey string
+}
+
 type UpdateThumbnailStateCommand struct {
 	State ThumbnailState
 	DashboardThumbnailMeta
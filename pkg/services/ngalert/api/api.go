@@ -35,6 +35,7 @@ type Alertmanager interface {
 	SaveAndApplyConfig(ctx context.Context, config *apimodels.PostableUserConfig) error
 	SaveAndApplyDefaultConfig(ctx context.Context) error
 	GetStatus() apimodels.GettableStatus
+	GetReceiversStatus() apimodels.GettableReceiversStatus
 
 	// Silences
 	CreateSilence(ps *apimodels.PostableSilence) (string, error)
@@ -48,6 +49,7 @@ type Alertmanager interface {
 
 	// Testing
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
+	TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*apimodels.TestTemplatesResults, error)
 }
 
 type AlertingStore interface {
@@ -66,9 +68,11 @@ type API struct {
 	InstanceStore        store.InstanceStore
 	AlertingStore        AlertingStore
 	AdminConfigStore     store.AdminConfigurationStore
+	ProvenanceStore      store.ProvisioningStore
 	DataProxy            *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	StateManager         *state.Manager
+	StateHistoryStore    store.StateHistoryStore
 	SecretsService       secrets.Service
 }
 
@@ -83,7 +87,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterAlertmanagerApiEndpoints(NewForkedAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{store: api.AlertingStore, mam: api.MultiOrgAlertmanager, secrets: api.SecretsService, log: logger},
+		&AlertmanagerSrv{store: api.AlertingStore, provenanceStore: api.ProvenanceStore, mam: api.MultiOrgAlertmanager, secrets: api.SecretsService, log: logger},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkedProm(
@@ -95,7 +99,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterRulerApiEndpoints(NewForkedRuler(
 		api.DatasourceCache,
 		NewLotexRuler(proxy, logger),
-		&RulerSrv{DatasourceCache: api.DatasourceCache, QuotaService: api.QuotaService, scheduleService: api.Schedule, store: api.RuleStore, log: logger},
+		&RulerSrv{cfg: &api.Cfg.UnifiedAlerting, DatasourceCache: api.DatasourceCache, QuotaService: api.QuotaService, scheduleService: api.Schedule, store: api.RuleStore, provenanceStore: api.ProvenanceStore, historyStore: api.StateHistoryStore, log: logger},
 	), m)
 	api.RegisterTestingApiEndpoints(NewForkedTestingApi(
 		&TestingApiSrv{
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -28,10 +29,11 @@ const (
 )
 
 type AlertmanagerSrv struct {
-	mam     *notifier.MultiOrgAlertmanager
-	secrets secrets.Service
-	store   AlertingStore
-	log     log.Logger
+	mam             *notifier.MultiOrgAlertmanager
+	secrets         secrets.Service
+	store           AlertingStore
+	provenanceStore store.ProvisioningStore
+	log             log.Logger
 }
 
 type UnknownReceiverError struct {
@@ -124,6 +126,20 @@ func (srv AlertmanagerSrv) RouteGetAMStatus(c *models.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, am.GetStatus())
 }
 
+func (srv AlertmanagerSrv) RouteGetReceivers(c *models.ReqContext) response.Response {
+	// the errors of the attempts can contain the URLs of the integrations, which may be secret
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	return response.JSON(http.StatusOK, am.GetReceiversStatus())
+}
+
 func (srv AlertmanagerSrv) RouteCreateSilence(c *models.ReqContext, postableSilence apimodels.PostableSilence) response.Response {
 	if !c.HasUserRole(models.ROLE_EDITOR) {
 		return ErrResp(http.StatusForbidden, errors.New("permission denied"), "")
@@ -154,6 +170,16 @@ func (srv AlertmanagerSrv) RouteDeleteAlertingConfig(c *models.ReqContext) respo
 		return ErrResp(http.StatusForbidden, errors.New("permission denied"), "")
 	}
 
+	provisioned, err := srv.getFileProvenances(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance of the Alertmanager configuration")
+	}
+	for _, ids := range provisioned {
+		if len(ids) > 0 {
+			return ErrResp(http.StatusConflict, fmt.Errorf("the configuration contains provisioned resources: %w", errProvisionedResource), "")
+		}
+	}
+
 	am, errResp := srv.AlertmanagerFor(c.OrgId)
 	if errResp != nil {
 		return errResp
@@ -247,6 +273,90 @@ func (srv AlertmanagerSrv) RouteGetAlertingConfig(c *models.ReqContext) response
 	return response.JSON(http.StatusOK, result)
 }
 
+// RouteGetAlertingConfigExport exports the contact points, notification policies, mute timings and templates of the
+// organization in the format of the provisioning files, or as Terraform resources. The secure settings of the
+// contact points are redacted unless the decrypt parameter is set, which requires the Admin role.
+func (srv AlertmanagerSrv) RouteGetAlertingConfigExport(c *models.ReqContext) response.Response {
+	decrypt := c.QueryBool("decrypt")
+	if !c.HasUserRole(models.ROLE_EDITOR) || decrypt && !c.HasUserRole(models.ROLE_ADMIN) {
+		return ErrResp(http.StatusForbidden, errors.New("permission denied"), "")
+	}
+	format, err := getExportFormat(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: c.OrgId}
+	if err := srv.store.GetLatestAlertmanagerConfiguration(c.Req.Context(), &query); err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get latest configuration")
+	}
+
+	cfg, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to unmarshal alertmanager configuration")
+	}
+
+	export := apimodels.AlertingFileExport{APIVersion: exportFileAPIVersion}
+	for _, recv := range cfg.AlertmanagerConfig.Receivers {
+		cp := apimodels.ContactPointExport{
+			OrgID:     c.OrgId,
+			Name:      recv.Name,
+			Receivers: make([]apimodels.ReceiverExport, 0, len(recv.PostableGrafanaReceivers.GrafanaManagedReceivers)),
+		}
+		for _, pr := range recv.PostableGrafanaReceivers.GrafanaManagedReceivers {
+			r := apimodels.ReceiverExport{
+				UID:                   pr.UID,
+				Type:                  pr.Type,
+				DisableResolveMessage: pr.DisableResolveMessage,
+				Settings:              map[string]interface{}{},
+			}
+			if pr.Settings != nil {
+				r.Settings = pr.Settings.MustMap()
+			}
+			for k := range pr.SecureSettings {
+				decryptedValue, err := srv.getDecryptedSecret(pr, k)
+				if err != nil {
+					return ErrResp(http.StatusInternalServerError, err, "failed to decrypt stored secure setting: %s", k)
+				}
+				if decryptedValue == "" {
+					continue
+				}
+				if r.SecureSettings == nil {
+					r.SecureSettings = make(map[string]string, len(pr.SecureSettings))
+				}
+				if decrypt {
+					r.SecureSettings[k] = decryptedValue
+				} else {
+					r.SecureSettings[k] = apimodels.RedactedValue
+				}
+			}
+			cp.Receivers = append(cp.Receivers, r)
+		}
+		export.ContactPoints = append(export.ContactPoints, cp)
+	}
+
+	if route := cfg.AlertmanagerConfig.Config.Route; route != nil {
+		export.Policies = append(export.Policies, apimodels.NotificationPolicyExport{OrgID: c.OrgId, Route: *route})
+	}
+	for _, mt := range cfg.AlertmanagerConfig.Config.MuteTimeIntervals {
+		export.MuteTimes = append(export.MuteTimes, apimodels.MuteTimingExport{OrgID: c.OrgId, MuteTimeInterval: mt})
+	}
+
+	names := make([]string, 0, len(cfg.TemplateFiles))
+	for name := range cfg.TemplateFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		export.Templates = append(export.Templates, apimodels.TemplateExport{OrgID: c.OrgId, Name: name, Template: cfg.TemplateFiles[name]})
+	}
+
+	return exportResponse(format, export)
+}
+
 func (srv AlertmanagerSrv) RouteGetAMAlertGroups(c *models.ReqContext) response.Response {
 	am, errResp := srv.AlertmanagerFor(c.OrgId)
 	if errResp != nil {
@@ -347,6 +457,20 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 		}
 	}
 
+	if query.Result != nil {
+		currentConfig, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to load latest configuration")
+		}
+		provisioned, err := srv.getFileProvenances(c.Req.Context(), c.OrgId)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get provenance of the Alertmanager configuration")
+		}
+		if err := checkProvisionedResources(currentConfig, &body, provisioned); err != nil {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+	}
+
 	if err := srv.loadSecureSettings(c.Req.Context(), c.OrgId, body.AlertmanagerConfig.Receivers); err != nil {
 		var unknownReceiverError UnknownReceiverError
 		if errors.As(err, &unknownReceiverError) {
@@ -422,6 +546,27 @@ func (srv AlertmanagerSrv) RoutePostTestReceivers(c *models.ReqContext, body api
 	return response.JSON(statusForTestReceivers(result.Receivers), newTestReceiversResult(result))
 }
 
+func (srv AlertmanagerSrv) RoutePostTestTemplates(c *models.ReqContext, body apimodels.TestTemplatesConfigBodyParams) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	result, err := am.TestTemplate(c.Req.Context(), body)
+	if err != nil {
+		if errors.Is(err, notifier.ErrAlertmanagerNotReady) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
 // contextWithTimeoutFromRequest returns a context with a deadline set from the
 // Request-Timeout header in the HTTP request. If the header is absent then the
 // context will use the default timeout. The timeout in the Request-Timeout
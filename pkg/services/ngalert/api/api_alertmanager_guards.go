@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// errProvisionedResource is returned when a request changes a resource that was provisioned from a file.
+// Such resources can only be changed by changing the provisioning files.
+var errProvisionedResource = errors.New("the resource is provisioned from a file and cannot be changed through the API")
+
+// provisionedResourceTypes are the types of the resources of the Alertmanager configuration that can be provisioned.
+var provisionedResourceTypes = []string{
+	ngmodels.ResourceTypeContactPoint,
+	ngmodels.ResourceTypeNotificationPolicy,
+	ngmodels.ResourceTypeMuteTiming,
+	ngmodels.ResourceTypeTemplate,
+}
+
+// getFileProvenances returns the IDs of the resources of the Alertmanager configuration that were provisioned from files, by resource type.
+func (srv AlertmanagerSrv) getFileProvenances(ctx context.Context, orgID int64) (map[string]map[string]struct{}, error) {
+	result := make(map[string]map[string]struct{}, len(provisionedResourceTypes))
+	for _, resourceType := range provisionedResourceTypes {
+		provenances, err := srv.provenanceStore.GetProvenances(ctx, orgID, resourceType)
+		if err != nil {
+			return nil, err
+		}
+		result[resourceType] = make(map[string]struct{})
+		for id, p := range provenances {
+			if p == ngmodels.ProvenanceFile {
+				result[resourceType][id] = struct{}{}
+			}
+		}
+	}
+	return result, nil
+}
+
+// checkProvisionedResources returns an error wrapping errProvisionedResource if the new configuration
+// changes or deletes any of the provisioned resources of the current configuration.
+func checkProvisionedResources(current, new *apimodels.PostableUserConfig, provisioned map[string]map[string]struct{}) error {
+	if current == nil {
+		return nil
+	}
+
+	for name := range provisioned[ngmodels.ResourceTypeContactPoint] {
+		currentReceiver := findReceiver(current, name)
+		if currentReceiver == nil {
+			continue
+		}
+		if !receiverUnchanged(currentReceiver, findReceiver(new, name)) {
+			return fmt.Errorf("contact point %q: %w", name, errProvisionedResource)
+		}
+	}
+
+	if _, ok := provisioned[ngmodels.ResourceTypeNotificationPolicy][ngmodels.NotificationPolicyResourceID]; ok {
+		if !jsonEqual(current.AlertmanagerConfig.Route, new.AlertmanagerConfig.Route) {
+			return fmt.Errorf("notification policies: %w", errProvisionedResource)
+		}
+	}
+
+	for name := range provisioned[ngmodels.ResourceTypeMuteTiming] {
+		currentMuteTiming, ok := findMuteTiming(current, name)
+		if !ok {
+			continue
+		}
+		newMuteTiming, ok := findMuteTiming(new, name)
+		if !ok || !jsonEqual(currentMuteTiming, newMuteTiming) {
+			return fmt.Errorf("mute timing %q: %w", name, errProvisionedResource)
+		}
+	}
+
+	for name := range provisioned[ngmodels.ResourceTypeTemplate] {
+		currentTemplate, ok := current.TemplateFiles[name]
+		if !ok {
+			continue
+		}
+		if newTemplate, ok := new.TemplateFiles[name]; !ok || newTemplate != currentTemplate {
+			return fmt.Errorf("template %q: %w", name, errProvisionedResource)
+		}
+	}
+
+	return nil
+}
+
+func findReceiver(cfg *apimodels.PostableUserConfig, name string) *apimodels.PostableApiReceiver {
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+func findMuteTiming(cfg *apimodels.PostableUserConfig, name string) (interface{}, bool) {
+	for _, mt := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+		if mt.Name == name {
+			return mt, true
+		}
+	}
+	return nil, false
+}
+
+// receiverUnchanged returns true if the new receiver has the same integrations as the current one.
+// Secure settings are not sent back by the API, so any secure setting in the new receiver is a change.
+func receiverUnchanged(current, new *apimodels.PostableApiReceiver) bool {
+	if new == nil {
+		return false
+	}
+	if len(current.GrafanaManagedReceivers) != len(new.GrafanaManagedReceivers) {
+		return false
+	}
+
+	currentByUID := make(map[string]*apimodels.PostableGrafanaReceiver, len(current.GrafanaManagedReceivers))
+	for _, gr := range current.GrafanaManagedReceivers {
+		currentByUID[gr.UID] = gr
+	}
+	for _, gr := range new.GrafanaManagedReceivers {
+		cgr, ok := currentByUID[gr.UID]
+		if !ok {
+			return false
+		}
+		if gr.Name != cgr.Name || gr.Type != cgr.Type || gr.DisableResolveMessage != cgr.DisableResolveMessage {
+			return false
+		}
+		if len(gr.SecureSettings) > 0 {
+			return false
+		}
+		if !jsonEqual(gr.Settings, cgr.Settings) {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonEqual(a, b interface{}) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aJSON) == string(bJSON)
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+const guardsTestConfig = `{
+	"template_files": {"provisioned": "{{ define \"a\" }}a{{ end }}"},
+	"alertmanager_config": {
+		"route": {"receiver": "provisioned", "routes": [{"receiver": "other"}]},
+		"mute_time_intervals": [{"name": "provisioned", "time_intervals": [{"weekdays": ["saturday"]}]}],
+		"receivers": [
+			{"name": "provisioned", "grafana_managed_receiver_configs": [{"uid": "a", "name": "provisioned", "type": "email", "settings": {"addresses": "a@example.com"}}]},
+			{"name": "other", "grafana_managed_receiver_configs": [{"uid": "b", "name": "other", "type": "email", "settings": {"addresses": "b@example.com"}}]}
+		]
+	}
+}`
+
+func TestCheckProvisionedResources(t *testing.T) {
+	provisioned := map[string]map[string]struct{}{
+		ngmodels.ResourceTypeContactPoint:       {"provisioned": {}},
+		ngmodels.ResourceTypeNotificationPolicy: {ngmodels.NotificationPolicyResourceID: {}},
+		ngmodels.ResourceTypeMuteTiming:         {"provisioned": {}},
+		ngmodels.ResourceTypeTemplate:           {"provisioned": {}},
+	}
+
+	load := func(t *testing.T) *apimodels.PostableUserConfig {
+		t.Helper()
+		cfg, err := notifier.Load([]byte(guardsTestConfig))
+		require.NoError(t, err)
+		return cfg
+	}
+
+	t.Run("unchanged configuration is accepted", func(t *testing.T) {
+		require.NoError(t, checkProvisionedResources(load(t), load(t), provisioned))
+	})
+
+	t.Run("changes to resources that are not provisioned are accepted", func(t *testing.T) {
+		newCfg := load(t)
+		newCfg.AlertmanagerConfig.Receivers[1].GrafanaManagedReceivers[0].Type = "slack"
+		require.NoError(t, checkProvisionedResources(load(t), newCfg, map[string]map[string]struct{}{}))
+		require.NoError(t, checkProvisionedResources(load(t), newCfg, provisioned))
+	})
+
+	testCases := []struct {
+		name   string
+		modify func(cfg *apimodels.PostableUserConfig)
+	}{
+		{
+			name: "contact point is deleted",
+			modify: func(cfg *apimodels.PostableUserConfig) {
+				cfg.AlertmanagerConfig.Receivers = cfg.AlertmanagerConfig.Receivers[1:]
+			},
+		},
+		{
+			name: "contact point settings are changed",
+			modify: func(cfg *apimodels.PostableUserConfig) {
+				cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].Settings.Set("addresses", "c@example.com")
+			},
+		},
+		{
+			name: "contact point secure settings are changed",
+			modify: func(cfg *apimodels.PostableUserConfig) {
+				cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].SecureSettings = map[string]string{"password": "secret"}
+			},
+		},
+		{
+			name: "notification policies are changed",
+			modify: func(cfg *apimodels.PostableUserConfig) {
+				cfg.AlertmanagerConfig.Route.Routes = nil
+			},
+		},
+		{
+			name: "mute timing is deleted",
+			modify: func(cfg *apimodels.PostableUserConfig) {
+				cfg.AlertmanagerConfig.MuteTimeIntervals = nil
+			},
+		},
+		{
+			name: "template is changed",
+			modify: func(cfg *apimodels.PostableUserConfig) {
+				cfg.TemplateFiles["provisioned"] = "changed"
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run("rejected when "+tc.name, func(t *testing.T) {
+			newCfg := load(t)
+			tc.modify(newCfg)
+			err := checkProvisionedResources(load(t), newCfg, provisioned)
+			require.ErrorIs(t, err, errProvisionedResource)
+		})
+	}
+}
@@ -235,7 +235,7 @@ func createSut(t *testing.T) AlertmanagerSrv {
 	store.Setup(2)
 	store.Setup(3)
 	secrets := fakes.NewFakeSecretsService()
-	return AlertmanagerSrv{mam: mam, store: store, secrets: secrets}
+	return AlertmanagerSrv{mam: mam, store: store, provenanceStore: newFakeProvisioningStore(), secrets: secrets}
 }
 
 func createAmConfigRequest(t *testing.T) apimodels.PostableUserConfig {
@@ -155,11 +155,12 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 			queryStr = string(encodedQuery)
 		}
 		alertingRule := apimodels.AlertingRule{
-			State:       "inactive",
-			Name:        rule.Title,
-			Query:       queryStr,
-			Duration:    rule.For.Seconds(),
-			Annotations: rule.Annotations,
+			State:         "inactive",
+			Name:          rule.Title,
+			Query:         queryStr,
+			Duration:      rule.For.Seconds(),
+			KeepFiringFor: rule.KeepFiringFor.Seconds(),
+			Annotations:   rule.Annotations,
 		}
 
 		newRule := apimodels.Rule{
@@ -170,6 +171,12 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 			LastEvaluation: time.Time{},
 		}
 
+		if rule.IsRecordingRule() {
+			newGroup.Rules = append(newGroup.Rules, toRecordingRule(srv.manager, rule, newRule, newGroup, queryStr))
+			newGroup.Interval = float64(rule.IntervalSeconds)
+			continue
+		}
+
 		for _, alertState := range srv.manager.GetStatesForRuleUID(c.OrgId, rule.UID) {
 			activeAt := alertState.StartsAt
 			valString := ""
@@ -218,3 +225,26 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 	}
 	return response.JSON(http.StatusOK, ruleResponse)
 }
+
+// toRecordingRule builds the status of a recording rule from its recording state. Recording rules have no alerts.
+func toRecordingRule(manager *state.Manager, rule *ngmodels.AlertRule, newRule apimodels.Rule, group *apimodels.RuleGroup, query string) apimodels.AlertingRule {
+	newRule.Type = apiv1.RuleTypeRecording
+	newRule.Query = query
+	newRule.Health = string(state.RecordingRuleHealthUnknown)
+	if s, ok := manager.GetRecordingRuleState(rule.OrgID, rule.UID); ok {
+		newRule.Health = string(s.Health)
+		newRule.LastEvaluation = s.LastEvaluationTime
+		newRule.EvaluationTime = s.EvaluationDuration.Seconds()
+		if s.Error != nil {
+			newRule.LastError = s.Error.Error()
+		}
+		if s.LastEvaluationTime.After(group.LastEvaluation) {
+			group.LastEvaluation = s.LastEvaluationTime
+		}
+	}
+	return apimodels.AlertingRule{
+		Name:  rule.Record.Metric,
+		Query: query,
+		Rule:  newRule,
+	}
+}
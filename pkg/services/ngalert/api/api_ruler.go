@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/apierrors"
@@ -19,15 +21,19 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type RulerSrv struct {
+	cfg             *setting.UnifiedAlertingSettings
 	store           store.RuleStore
+	provenanceStore store.ProvisioningStore
 	DatasourceCache datasources.CacheService
 	QuotaService    *quota.QuotaService
 	scheduleService schedule.ScheduleService
+	historyStore    store.StateHistoryStore
 	log             log.Logger
 }
 
@@ -38,6 +44,17 @@ func (srv RulerSrv) RouteDeleteNamespaceRulesConfig(c *models.ReqContext) respon
 		return toNamespaceErrorResponse(err)
 	}
 
+	q := ngmodels.ListNamespaceAlertRulesQuery{
+		OrgID:        c.SignedInUser.OrgId,
+		NamespaceUID: namespace.Uid,
+	}
+	if err := srv.store.GetNamespaceAlertRules(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespace alert rules")
+	}
+	if errResp := srv.checkRulesNotProvisioned(c, q.Result); errResp != nil {
+		return errResp
+	}
+
 	uids, err := srv.store.DeleteNamespaceAlertRules(c.Req.Context(), c.SignedInUser.OrgId, namespace.Uid)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete namespace alert rules")
@@ -60,6 +77,19 @@ func (srv RulerSrv) RouteDeleteRuleGroupConfig(c *models.ReqContext) response.Re
 		return toNamespaceErrorResponse(err)
 	}
 	ruleGroup := web.Params(c.Req)[":Groupname"]
+
+	q := ngmodels.ListRuleGroupAlertRulesQuery{
+		OrgID:        c.SignedInUser.OrgId,
+		NamespaceUID: namespace.Uid,
+		RuleGroup:    ruleGroup,
+	}
+	if err := srv.store.GetRuleGroupAlertRules(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get group alert rules")
+	}
+	if errResp := srv.checkRulesNotProvisioned(c, q.Result); errResp != nil {
+		return errResp
+	}
+
 	uids, err := srv.store.DeleteRuleGroupAlertRules(c.Req.Context(), c.SignedInUser.OrgId, namespace.Uid, ruleGroup)
 
 	if err != nil {
@@ -94,6 +124,11 @@ func (srv RulerSrv) RouteGetNamespaceRulesConfig(c *models.ReqContext) response.
 		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
 
+	provenances, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.OrgId, (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance for rules")
+	}
+
 	result := apimodels.NamespaceConfigResponse{}
 	ruleGroupConfigs := make(map[string]apimodels.GettableRuleGroupConfig)
 	for _, r := range q.Result {
@@ -104,11 +139,11 @@ func (srv RulerSrv) RouteGetNamespaceRulesConfig(c *models.ReqContext) response.
 				Name:     r.RuleGroup,
 				Interval: ruleGroupInterval,
 				Rules: []apimodels.GettableExtendedRuleNode{
-					toGettableExtendedRuleNode(*r, namespace.Id),
+					toGettableExtendedRuleNode(*r, namespace.Id, provenances[r.UID]),
 				},
 			}
 		} else {
-			ruleGroupConfig.Rules = append(ruleGroupConfig.Rules, toGettableExtendedRuleNode(*r, namespace.Id))
+			ruleGroupConfig.Rules = append(ruleGroupConfig.Rules, toGettableExtendedRuleNode(*r, namespace.Id, provenances[r.UID]))
 			ruleGroupConfigs[r.RuleGroup] = ruleGroupConfig
 		}
 	}
@@ -137,11 +172,16 @@ func (srv RulerSrv) RouteGetRulegGroupConfig(c *models.ReqContext) response.Resp
 		return ErrResp(http.StatusInternalServerError, err, "failed to get group alert rules")
 	}
 
+	provenances, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.OrgId, (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance for rules")
+	}
+
 	var ruleGroupInterval model.Duration
 	ruleNodes := make([]apimodels.GettableExtendedRuleNode, 0, len(q.Result))
 	for _, r := range q.Result {
 		ruleGroupInterval = model.Duration(time.Duration(r.IntervalSeconds) * time.Second)
-		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, namespace.Id))
+		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, namespace.Id, provenances[r.UID]))
 	}
 
 	result := apimodels.RuleGroupConfigResponse{
@@ -191,6 +231,11 @@ func (srv RulerSrv) RouteGetRulesConfig(c *models.ReqContext) response.Response
 		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
 	}
 
+	provenances, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.OrgId, (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance for rules")
+	}
+
 	configs := make(map[string]map[string]apimodels.GettableRuleGroupConfig)
 	for _, r := range q.Result {
 		folder, ok := namespaceMap[r.NamespaceUID]
@@ -207,7 +252,7 @@ func (srv RulerSrv) RouteGetRulesConfig(c *models.ReqContext) response.Response
 				Name:     r.RuleGroup,
 				Interval: ruleGroupInterval,
 				Rules: []apimodels.GettableExtendedRuleNode{
-					toGettableExtendedRuleNode(*r, folder.Id),
+					toGettableExtendedRuleNode(*r, folder.Id, provenances[r.UID]),
 				},
 			}
 		} else {
@@ -218,11 +263,11 @@ func (srv RulerSrv) RouteGetRulesConfig(c *models.ReqContext) response.Response
 					Name:     r.RuleGroup,
 					Interval: ruleGroupInterval,
 					Rules: []apimodels.GettableExtendedRuleNode{
-						toGettableExtendedRuleNode(*r, folder.Id),
+						toGettableExtendedRuleNode(*r, folder.Id, provenances[r.UID]),
 					},
 				}
 			} else {
-				ruleGroupConfig.Rules = append(ruleGroupConfig.Rules, toGettableExtendedRuleNode(*r, folder.Id))
+				ruleGroupConfig.Rules = append(ruleGroupConfig.Rules, toGettableExtendedRuleNode(*r, folder.Id, provenances[r.UID]))
 				configs[namespace][r.RuleGroup] = ruleGroupConfig
 			}
 		}
@@ -236,6 +281,66 @@ func (srv RulerSrv) RouteGetRulesConfig(c *models.ReqContext) response.Response
 	return response.JSON(http.StatusOK, result)
 }
 
+// RouteGetRulesExport exports the rule groups of the folders visible to the user in the format of the
+// provisioning files, or as Terraform resources. The export can be limited to some folders and to a rule group.
+func (srv RulerSrv) RouteGetRulesExport(c *models.ReqContext) response.Response {
+	format, err := getExportFormat(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	folderUIDs := c.QueryStrings("folderUid")
+	group := c.Query("group")
+	if group != "" && len(folderUIDs) != 1 {
+		return ErrResp(http.StatusBadRequest, errors.New("exporting a rule group requires exactly one folderUid"), "")
+	}
+
+	namespaceMap, err := srv.store.GetNamespaces(c.Req.Context(), c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	for _, uid := range folderUIDs {
+		if _, ok := namespaceMap[uid]; !ok {
+			return ErrResp(http.StatusNotFound, fmt.Errorf("folder %s not found", uid), "")
+		}
+	}
+	if len(folderUIDs) == 0 {
+		for uid := range namespaceMap {
+			folderUIDs = append(folderUIDs, uid)
+		}
+	}
+
+	export := apimodels.AlertingFileExport{APIVersion: exportFileAPIVersion}
+	if len(folderUIDs) == 0 {
+		return exportResponse(format, export)
+	}
+
+	q := ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.OrgId,
+		NamespaceUIDs: folderUIDs,
+	}
+	if err := srv.store.GetOrgAlertRules(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+	rules := q.Result
+	if group != "" {
+		rules = make([]*ngmodels.AlertRule, 0, len(q.Result))
+		for _, r := range q.Result {
+			if r.RuleGroup == group {
+				rules = append(rules, r)
+			}
+		}
+		if len(rules) == 0 {
+			return ErrResp(http.StatusNotFound, fmt.Errorf("rule group %s not found", group), "")
+		}
+	}
+
+	export.Groups, err = rulesToExport(rules, namespaceMap)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to export alert rules")
+	}
+	return exportResponse(format, export)
+}
+
 func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConfig apimodels.PostableRuleGroupConfig) response.Response {
 	namespaceTitle := web.Params(c.Req)[":Namespace"]
 	namespace, err := srv.store.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.OrgId, c.SignedInUser, true)
@@ -255,6 +360,16 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 			OrgID:     c.SignedInUser.OrgId,
 			Data:      r.GrafanaManagedAlert.Data,
 		}
+		if record := r.GrafanaManagedAlert.Record; record != nil {
+			if !srv.cfg.RecordingRules.Enabled {
+				return ErrResp(http.StatusBadRequest, errors.New("recording rules are not enabled"), "failed to validate alert rule %q", r.GrafanaManagedAlert.Title)
+			}
+			if !model.IsValidMetricName(model.LabelValue(record.Metric)) {
+				return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid metric name %q", record.Metric), "failed to validate alert rule %q", r.GrafanaManagedAlert.Title)
+			}
+			// recording rules write the results of the query or expression they record from
+			cond.Condition = record.From
+		}
 		if err := validateCondition(c.Req.Context(), cond, c.SignedInUser, c.SkipCache, srv.DatasourceCache); err != nil {
 			return ErrResp(http.StatusBadRequest, err, "failed to validate alert rule %q", r.GrafanaManagedAlert.Title)
 		}
@@ -267,6 +382,22 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 		}
 	}
 
+	existingRulesQuery := ngmodels.ListRuleGroupAlertRulesQuery{
+		OrgID:        c.SignedInUser.OrgId,
+		NamespaceUID: namespace.Uid,
+		RuleGroup:    ruleGroupConfig.Name,
+	}
+	if err := srv.store.GetRuleGroupAlertRules(c.Req.Context(), &existingRulesQuery); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get group alert rules")
+	}
+	provenances, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.OrgId, (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance for rules")
+	}
+	if err := checkProvisionedRules(existingRulesQuery.Result, ruleGroupConfig, provenances); err != nil {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+
 	numOfNewRules := len(ruleGroupConfig.Rules) - len(alertRuleUIDs)
 	if numOfNewRules > 0 {
 		// quotas are checked in advanced
@@ -305,7 +436,73 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
 }
 
-func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64) apimodels.GettableExtendedRuleNode {
+func (srv RulerSrv) RoutePostPauseRule(c *models.ReqContext) response.Response {
+	return srv.setRulePaused(c, true)
+}
+
+func (srv RulerSrv) RoutePostResumeRule(c *models.ReqContext) response.Response {
+	return srv.setRulePaused(c, false)
+}
+
+// setRulePaused pauses or resumes the evaluation of a rule. The rule keeps its configuration, and its
+// states are cleared when it is paused, which resolves its alerts.
+func (srv RulerSrv) setRulePaused(c *models.ReqContext, paused bool) response.Response {
+	q := ngmodels.GetAlertRuleByUIDQuery{
+		OrgID: c.SignedInUser.OrgId,
+		UID:   web.Params(c.Req)[":RuleUID"],
+	}
+	if err := srv.store.GetAlertRuleByUID(c.Req.Context(), &q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+	}
+	existing := q.Result
+
+	if _, err := srv.store.GetNamespaceByUID(c.Req.Context(), existing.NamespaceUID, c.SignedInUser.OrgId, c.SignedInUser, true); err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+	if errResp := srv.checkRulesNotProvisioned(c, []*ngmodels.AlertRule{existing}); errResp != nil {
+		return errResp
+	}
+
+	if existing.IsPaused != paused {
+		updated := *existing
+		updated.IsPaused = paused
+		if err := srv.store.UpsertAlertRules(c.Req.Context(), []store.UpsertRule{{Existing: existing, New: updated}}); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to update alert rule")
+		}
+
+		if paused {
+			srv.scheduleService.DeleteAlertRule(existing.GetKey())
+			if err := srv.store.DeleteAlertInstancesByRuleUID(c.Req.Context(), existing.OrgID, existing.UID); err != nil {
+				return ErrResp(http.StatusInternalServerError, err, "failed to delete alert instances of paused rule")
+			}
+		}
+	}
+
+	if paused {
+		return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule paused"})
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule resumed"})
+}
+
+// checkRulesNotProvisioned returns an error response if any of the rules was provisioned from a file.
+// Provisioned rules can only be changed by changing the provisioning files.
+func (srv RulerSrv) checkRulesNotProvisioned(c *models.ReqContext, rules []*ngmodels.AlertRule) response.Response {
+	provenances, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.OrgId, (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance for rules")
+	}
+	for _, r := range rules {
+		if provenances[r.UID] == ngmodels.ProvenanceFile {
+			return ErrResp(http.StatusConflict, fmt.Errorf("alert rule %q: %w", r.Title, errProvisionedResource), "")
+		}
+	}
+	return nil
+}
+
+func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provenance ngmodels.Provenance) apimodels.GettableExtendedRuleNode {
 	gettableExtendedRuleNode := apimodels.GettableExtendedRuleNode{
 		GrafanaManagedAlert: &apimodels.GettableGrafanaRule{
 			ID:              r.ID,
@@ -322,12 +519,16 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64) apimode
 			RuleGroup:       r.RuleGroup,
 			NoDataState:     apimodels.NoDataState(r.NoDataState),
 			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
+			Record:          r.Record,
+			IsPaused:        r.IsPaused,
+			Provenance:      provenance,
 		},
 	}
 	gettableExtendedRuleNode.ApiRuleNode = &apimodels.ApiRuleNode{
-		For:         model.Duration(r.For),
-		Annotations: r.Annotations,
-		Labels:      r.Labels,
+		For:           model.Duration(r.For),
+		KeepFiringFor: model.Duration(r.KeepFiringFor),
+		Annotations:   r.Annotations,
+		Labels:        r.Labels,
 	}
 	return gettableExtendedRuleNode
 }
@@ -341,3 +542,83 @@ func toNamespaceErrorResponse(err error) response.Response {
 	}
 	return apierrors.ToFolderErrorResponse(err)
 }
+
+// RouteGetRuleStateHistory returns the history of the state changes of the alert instances of a rule,
+// grouped by instance.
+func (srv RulerSrv) RouteGetRuleStateHistory(c *models.ReqContext) response.Response {
+	q := ngmodels.GetAlertRuleByUIDQuery{
+		OrgID: c.SignedInUser.OrgId,
+		UID:   web.Params(c.Req)[":RuleUID"],
+	}
+	if err := srv.store.GetAlertRuleByUID(c.Req.Context(), &q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+	}
+	if _, err := srv.store.GetNamespaceByUID(c.Req.Context(), q.Result.NamespaceUID, c.SignedInUser.OrgId, c.SignedInUser, false); err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	historyQuery := ngmodels.ListAlertStateHistoryQuery{
+		OrgID:   q.OrgID,
+		RuleUID: q.UID,
+		Limit:   c.QueryInt("limit"),
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		historyQuery.From = time.Unix(0, from*int64(time.Millisecond))
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		historyQuery.To = time.Unix(0, to*int64(time.Millisecond))
+	}
+	for _, s := range c.QueryStrings("matcher") {
+		matcher, err := labels.ParseMatcher(s)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid matcher")
+		}
+		historyQuery.Matchers = append(historyQuery.Matchers, matcher)
+	}
+
+	if err := srv.historyStore.ListAlertStateHistory(c.Req.Context(), &historyQuery); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert state history")
+	}
+
+	result := apimodels.RuleStateHistory{Instances: []apimodels.InstanceStateHistory{}}
+	instanceIdx := make(map[string]int)
+	for _, entry := range historyQuery.Result {
+		key, err := entry.Labels.StringKey()
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get alert state history")
+		}
+		idx, ok := instanceIdx[key]
+		if !ok {
+			idx = len(result.Instances)
+			instanceIdx[key] = idx
+			result.Instances = append(result.Instances, apimodels.InstanceStateHistory{
+				Labels:  entry.Labels,
+				History: []apimodels.StateHistoryEntry{},
+			})
+		}
+		result.Instances[idx].History = append(result.Instances[idx].History, toStateHistoryEntry(entry))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func toStateHistoryEntry(entry *ngmodels.AlertStateHistoryEntry) apimodels.StateHistoryEntry {
+	var values map[string]string
+	if len(entry.Values) > 0 {
+		values = make(map[string]string, len(entry.Values))
+		for refID, v := range entry.Values {
+			if v != nil {
+				values[refID] = strconv.FormatFloat(*v, 'g', -1, 64)
+			}
+		}
+	}
+	return apimodels.StateHistoryEntry{
+		PreviousState: string(entry.PreviousState),
+		State:         string(entry.State),
+		Values:        values,
+		Error:         entry.Error,
+		Timestamp:     entry.Timestamp,
+	}
+}
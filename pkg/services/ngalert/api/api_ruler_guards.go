@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// checkProvisionedRules returns an error wrapping errProvisionedResource if the new configuration of a rule group
+// changes or deletes any of the rules of the group that were provisioned from a file, or moves a provisioned rule
+// from another group. The other rules of the group can be changed.
+func checkProvisionedRules(existing []*ngmodels.AlertRule, ruleGroupConfig apimodels.PostableRuleGroupConfig, provenances map[string]ngmodels.Provenance) error {
+	newRules := make(map[string]apimodels.PostableExtendedRuleNode, len(ruleGroupConfig.Rules))
+	for _, r := range ruleGroupConfig.Rules {
+		if r.GrafanaManagedAlert != nil && r.GrafanaManagedAlert.UID != "" {
+			newRules[r.GrafanaManagedAlert.UID] = r
+		}
+	}
+
+	existingUIDs := make(map[string]struct{}, len(existing))
+	for _, r := range existing {
+		existingUIDs[r.UID] = struct{}{}
+		if provenances[r.UID] != ngmodels.ProvenanceFile {
+			continue
+		}
+		newRule, ok := newRules[r.UID]
+		if !ok || !ruleUnchanged(r, newRule, ruleGroupConfig.Interval) {
+			return fmt.Errorf("alert rule %q: %w", r.Title, errProvisionedResource)
+		}
+	}
+
+	for uid := range newRules {
+		if _, ok := existingUIDs[uid]; !ok && provenances[uid] == ngmodels.ProvenanceFile {
+			return fmt.Errorf("alert rule with UID %q: %w", uid, errProvisionedResource)
+		}
+	}
+	return nil
+}
+
+// ruleUnchanged returns true if the new rule has the same definition as the current one, including the interval
+// of its group, which is the interval of the rule.
+func ruleUnchanged(current *ngmodels.AlertRule, new apimodels.PostableExtendedRuleNode, interval model.Duration) bool {
+	gr := new.GrafanaManagedAlert
+	if gr.Title != current.Title || gr.Condition != current.Condition ||
+		string(gr.NoDataState) != string(current.NoDataState) || string(gr.ExecErrState) != string(current.ExecErrState) {
+		return false
+	}
+	if int64(time.Duration(interval).Seconds()) != current.IntervalSeconds {
+		return false
+	}
+	if gr.IsPaused != nil && *gr.IsPaused != current.IsPaused {
+		return false
+	}
+	if !jsonEqual(gr.Record, current.Record) || !queriesEqual(gr.Data, current.Data) {
+		return false
+	}
+
+	var node apimodels.ApiRuleNode
+	if new.ApiRuleNode != nil {
+		node = *new.ApiRuleNode
+	}
+	return time.Duration(node.For) == current.For && time.Duration(node.KeepFiringFor) == current.KeepFiringFor &&
+		mapsEqual(node.Labels, current.Labels) && mapsEqual(node.Annotations, current.Annotations)
+}
+
+// queriesEqual compares the JSON of the queries regardless of the order of the keys of their models,
+// as the models are sent back as they were edited.
+func queriesEqual(a, b []ngmodels.AlertQuery) bool {
+	var aValue, bValue interface{}
+	if err := remarshal(a, &aValue); err != nil {
+		return false
+	}
+	if err := remarshal(b, &bValue); err != nil {
+		return false
+	}
+	return jsonEqual(aValue, bValue)
+}
+
+func remarshal(v interface{}, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// mapsEqual returns true if the maps have the same keys and values. A nil map is equal to an empty map.
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
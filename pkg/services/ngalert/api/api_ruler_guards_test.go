@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestCheckProvisionedRules(t *testing.T) {
+	existing := []*ngmodels.AlertRule{
+		{
+			UID:             "provisioned",
+			Title:           "provisioned",
+			Condition:       "A",
+			Data:            []ngmodels.AlertQuery{{RefID: "A", DatasourceUID: "-100", Model: json.RawMessage(`{"type": "math", "expression": "1 > 0"}`)}},
+			IntervalSeconds: 60,
+			NoDataState:     ngmodels.NoData,
+			ExecErrState:    ngmodels.AlertingErrState,
+			For:             time.Minute,
+			Labels:          map[string]string{"team": "a"},
+		},
+		{
+			UID:             "ui",
+			Title:           "ui",
+			Condition:       "A",
+			Data:            []ngmodels.AlertQuery{{RefID: "A", DatasourceUID: "-100", Model: json.RawMessage(`{"type": "math", "expression": "1 > 0"}`)}},
+			IntervalSeconds: 60,
+			NoDataState:     ngmodels.NoData,
+			ExecErrState:    ngmodels.AlertingErrState,
+		},
+	}
+	provenances := map[string]ngmodels.Provenance{"provisioned": ngmodels.ProvenanceFile, "other-group": ngmodels.ProvenanceFile}
+
+	// newConfig returns the configuration of the group as it is sent back by the UI,
+	// with the keys of the models in a different order.
+	newConfig := func() apimodels.PostableRuleGroupConfig {
+		cfg := apimodels.PostableRuleGroupConfig{Name: "group", Interval: model.Duration(time.Minute)}
+		for _, r := range existing {
+			cfg.Rules = append(cfg.Rules, apimodels.PostableExtendedRuleNode{
+				ApiRuleNode: &apimodels.ApiRuleNode{For: model.Duration(r.For), Labels: r.Labels},
+				GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+					UID:          r.UID,
+					Title:        r.Title,
+					Condition:    r.Condition,
+					Data:         []ngmodels.AlertQuery{{RefID: "A", DatasourceUID: "-100", Model: json.RawMessage(`{"expression": "1 > 0", "type": "math"}`)}},
+					NoDataState:  apimodels.NoDataState(r.NoDataState),
+					ExecErrState: apimodels.ExecutionErrorState(r.ExecErrState),
+				},
+			})
+		}
+		return cfg
+	}
+
+	t.Run("unchanged group is accepted", func(t *testing.T) {
+		require.NoError(t, checkProvisionedRules(existing, newConfig(), provenances))
+	})
+
+	t.Run("rules that are not provisioned can be changed, added and deleted", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Rules[1].GrafanaManagedAlert.Title = "changed"
+		require.NoError(t, checkProvisionedRules(existing, cfg, provenances))
+
+		cfg.Rules = append(cfg.Rules, apimodels.PostableExtendedRuleNode{GrafanaManagedAlert: &apimodels.PostableGrafanaRule{Title: "new"}})
+		require.NoError(t, checkProvisionedRules(existing, cfg, provenances))
+
+		cfg.Rules = cfg.Rules[:1]
+		require.NoError(t, checkProvisionedRules(existing, cfg, provenances))
+	})
+
+	testCases := []struct {
+		name   string
+		modify func(cfg *apimodels.PostableRuleGroupConfig)
+	}{
+		{
+			name: "provisioned rule is deleted",
+			modify: func(cfg *apimodels.PostableRuleGroupConfig) {
+				cfg.Rules = cfg.Rules[1:]
+			},
+		},
+		{
+			name: "provisioned rule is changed",
+			modify: func(cfg *apimodels.PostableRuleGroupConfig) {
+				cfg.Rules[0].GrafanaManagedAlert.Data[0].Model = json.RawMessage(`{"type": "math", "expression": "1 < 0"}`)
+			},
+		},
+		{
+			name: "labels of the provisioned rule are changed",
+			modify: func(cfg *apimodels.PostableRuleGroupConfig) {
+				cfg.Rules[0].ApiRuleNode.Labels = map[string]string{"team": "b"}
+			},
+		},
+		{
+			name: "interval of the group is changed",
+			modify: func(cfg *apimodels.PostableRuleGroupConfig) {
+				cfg.Interval = model.Duration(2 * time.Minute)
+			},
+		},
+		{
+			name: "provisioned rule is moved from another group",
+			modify: func(cfg *apimodels.PostableRuleGroupConfig) {
+				cfg.Rules = append(cfg.Rules, apimodels.PostableExtendedRuleNode{GrafanaManagedAlert: &apimodels.PostableGrafanaRule{UID: "other-group"}})
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name+" is rejected", func(t *testing.T) {
+			cfg := newConfig()
+			tc.modify(&cfg)
+			require.ErrorIs(t, checkProvisionedRules(existing, cfg, provenances), errProvisionedResource)
+		})
+	}
+}
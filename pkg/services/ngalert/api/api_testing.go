@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/expr"
@@ -15,11 +16,17 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
+// maxBacktestEvaluations is the maximum number of evaluations of a rule in a backtest,
+// which is enough to backtest a rule evaluated every minute over a week.
+const maxBacktestEvaluations = 10080
+
 type TestingApiSrv struct {
 	*AlertingProxy
 	Cfg               *setting.Cfg
@@ -96,3 +103,85 @@ func (srv TestingApiSrv) RouteEvalQueries(c *models.ReqContext, cmd apimodels.Ev
 
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
+
+func (srv TestingApiSrv) RouteBacktestGrafanaRule(c *models.ReqContext, cmd apimodels.BacktestRulePayload) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	if len(cmd.Data) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("no queries or expressions are found"), "invalid condition")
+	}
+	condition := ngmodels.Condition{
+		Condition: cmd.Condition,
+		OrgID:     c.SignedInUser.OrgId,
+		Data:      cmd.Data,
+	}
+	if err := validateCondition(c.Req.Context(), condition, c.SignedInUser, c.SkipCache, srv.DatasourceCache); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid condition")
+	}
+
+	baseInterval := int64(srv.Cfg.UnifiedAlerting.BaseInterval.Seconds())
+	if cmd.IntervalSeconds <= 0 || baseInterval > 0 && cmd.IntervalSeconds%baseInterval != 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("interval (%v) should be non-zero and divided exactly by scheduler interval: %v", time.Duration(cmd.IntervalSeconds)*time.Second, srv.Cfg.UnifiedAlerting.BaseInterval), "")
+	}
+	if cmd.From.IsZero() || cmd.To.IsZero() || cmd.To.Before(cmd.From) {
+		return ErrResp(http.StatusBadRequest, errors.New("the time range must have a start before its end"), "")
+	}
+	if cmd.To.After(timeNow()) {
+		return ErrResp(http.StatusBadRequest, errors.New("the time range must be in the past"), "")
+	}
+	if evaluations := int64(cmd.To.Sub(cmd.From)/time.Second)/cmd.IntervalSeconds + 1; evaluations > maxBacktestEvaluations {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("the rule would be evaluated %d times, which is more than the maximum of %d: reduce the time range or increase the interval", evaluations, maxBacktestEvaluations), "")
+	}
+
+	rule := &ngmodels.AlertRule{
+		OrgID:           c.SignedInUser.OrgId,
+		Title:           cmd.Title,
+		Condition:       cmd.Condition,
+		Data:            cmd.Data,
+		IntervalSeconds: cmd.IntervalSeconds,
+		For:             time.Duration(cmd.For),
+		KeepFiringFor:   time.Duration(cmd.KeepFiringFor),
+		NoDataState:     ngmodels.NoDataState(cmd.NoDataState),
+		ExecErrState:    ngmodels.ExecutionErrorState(cmd.ExecErrState),
+		Labels:          cmd.Labels,
+	}
+	if rule.NoDataState == "" {
+		rule.NoDataState = ngmodels.NoData
+	}
+	if rule.ExecErrState == "" {
+		rule.ExecErrState = ngmodels.AlertingErrState
+	}
+
+	externalURL, err := url.Parse(srv.Cfg.AppURL)
+	if err != nil {
+		srv.log.Error("unable to parse application URL", "url", srv.Cfg.AppURL, "error", err)
+		externalURL = nil
+	}
+	evaluator := eval.NewEvaluator(srv.Cfg, srv.log, srv.DatasourceCache, srv.secretsService)
+	timelines, err := state.Backtest(c.Req.Context(), srv.log, externalURL, rule, cmd.From, cmd.To, func(now time.Time) (eval.Results, error) {
+		return evaluator.ConditionEval(&condition, now, srv.ExpressionService)
+	})
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to backtest the rule")
+	}
+
+	result := apimodels.BacktestResult{Series: make([]apimodels.BacktestSeries, 0, len(timelines))}
+	for _, t := range timelines {
+		series := apimodels.BacktestSeries{
+			Labels:  t.Labels,
+			Periods: make([]apimodels.BacktestPeriod, 0, len(t.Periods)),
+		}
+		for _, p := range t.Periods {
+			series.Periods = append(series.Periods, apimodels.BacktestPeriod{
+				State:    p.State.String(),
+				Resolved: p.Resolved,
+				Start:    p.Start,
+				End:      p.End,
+			})
+		}
+		result.Series = append(result.Series, series)
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteBacktestGrafanaRule(t *testing.T) {
+	cfg := &setting.Cfg{
+		ExpressionsEnabled: true,
+		UnifiedAlerting: setting.UnifiedAlertingSettings{
+			BaseInterval:      10 * time.Second,
+			EvaluationTimeout: 10 * time.Second,
+		},
+	}
+	srv := TestingApiSrv{
+		Cfg:               cfg,
+		ExpressionService: expr.ProvideService(cfg, nil, nil),
+		secretsService:    fakes.NewFakeSecretsService(),
+		log:               log.New("test"),
+	}
+	from := time.Now().Add(-time.Hour).Truncate(time.Second)
+	payload := func() apimodels.BacktestRulePayload {
+		return apimodels.BacktestRulePayload{
+			Title:     "my rule",
+			Condition: "A",
+			Data: []ngmodels.AlertQuery{{
+				RefID:             "A",
+				RelativeTimeRange: ngmodels.RelativeTimeRange{From: ngmodels.Duration(10 * time.Minute)},
+				DatasourceUID:     "-100",
+				Model:             json.RawMessage(`{"type": "math", "expression": "2 + 3 > 1"}`),
+			}},
+			From:            from,
+			To:              from.Add(time.Minute),
+			IntervalSeconds: 10,
+			For:             model.Duration(20 * time.Second),
+		}
+	}
+	backtest := func(t *testing.T, role models.RoleType, cmd apimodels.BacktestRulePayload) (int, string) {
+		t.Helper()
+		rc := models.ReqContext{
+			Context: &web.Context{
+				Req: httptest.NewRequest("POST", "/api/v1/rule/backtest/grafana", nil),
+			},
+			SignedInUser: &models.SignedInUser{
+				OrgRole: role,
+				OrgId:   1,
+			},
+		}
+		resp := srv.RouteBacktestGrafanaRule(&rc, cmd)
+		return resp.Status(), string(resp.Body())
+	}
+
+	t.Run("requires the Editor role", func(t *testing.T) {
+		status, _ := backtest(t, models.ROLE_VIEWER, payload())
+		require.Equal(t, 403, status)
+	})
+
+	t.Run("rejects invalid payloads", func(t *testing.T) {
+		testCases := map[string]func(cmd *apimodels.BacktestRulePayload){
+			"unknown condition":          func(cmd *apimodels.BacktestRulePayload) { cmd.Condition = "B" },
+			"no data":                    func(cmd *apimodels.BacktestRulePayload) { cmd.Data = nil },
+			"no interval":                func(cmd *apimodels.BacktestRulePayload) { cmd.IntervalSeconds = 0 },
+			"interval not aligned":       func(cmd *apimodels.BacktestRulePayload) { cmd.IntervalSeconds = 15 },
+			"end before start":           func(cmd *apimodels.BacktestRulePayload) { cmd.To = cmd.From.Add(-time.Minute) },
+			"time range in the future":   func(cmd *apimodels.BacktestRulePayload) { cmd.To = time.Now().Add(time.Hour) },
+			"too many evaluations":       func(cmd *apimodels.BacktestRulePayload) { cmd.From = cmd.To.Add(-30 * 24 * time.Hour) },
+			"no start of the time range": func(cmd *apimodels.BacktestRulePayload) { cmd.From = time.Time{} },
+		}
+		for name, modify := range testCases {
+			t.Run(name, func(t *testing.T) {
+				cmd := payload()
+				modify(&cmd)
+				status, _ := backtest(t, models.ROLE_EDITOR, cmd)
+				require.Equal(t, 400, status)
+			})
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// exportFileAPIVersion is the version of the provisioning files that the export is written in.
+const exportFileAPIVersion = 1
+
+func getExportFormat(c *models.ReqContext) (apimodels.ExportFormat, error) {
+	switch format := apimodels.ExportFormat(c.Query("format")); format {
+	case "":
+		return apimodels.ExportFormatYAML, nil
+	case apimodels.ExportFormatYAML, apimodels.ExportFormatJSON, apimodels.ExportFormatHCL:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q, must be one of yaml, json or hcl", format)
+	}
+}
+
+// exportResponse writes the export in the requested format.
+func exportResponse(format apimodels.ExportFormat, export apimodels.AlertingFileExport) response.Response {
+	switch format {
+	case apimodels.ExportFormatJSON:
+		return response.JSON(http.StatusOK, export)
+	case apimodels.ExportFormatHCL:
+		body, err := exportToHCL(export)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to write the export as HCL")
+		}
+		return response.Respond(http.StatusOK, body).SetHeader("Content-Type", "text/hcl")
+	default:
+		body, err := yaml.Marshal(export)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to write the export as YAML")
+		}
+		return response.Respond(http.StatusOK, body).SetHeader("Content-Type", "application/yaml")
+	}
+}
+
+// rulesToExport groups the rules by folder and rule group. The groups are sorted by the title of their folder and
+// their name, and keep the order of the rules.
+func rulesToExport(rules []*ngmodels.AlertRule, folders map[string]*models.Folder) ([]apimodels.AlertRuleGroupExport, error) {
+	groups := make(map[ngmodels.AlertRuleGroupKey]*apimodels.AlertRuleGroupExport)
+	var keys []ngmodels.AlertRuleGroupKey
+	for _, rule := range rules {
+		folder, ok := folders[rule.NamespaceUID]
+		if !ok {
+			continue
+		}
+		key := rule.GetGroupKey()
+		group, ok := groups[key]
+		if !ok {
+			group = &apimodels.AlertRuleGroupExport{
+				OrgID:     rule.OrgID,
+				Name:      rule.RuleGroup,
+				Folder:    folder.Title,
+				FolderUID: folder.Uid,
+				Interval:  model.Duration(time.Duration(rule.IntervalSeconds) * time.Second).String(),
+			}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		r, err := ruleToExport(rule)
+		if err != nil {
+			return nil, err
+		}
+		group.Rules = append(group.Rules, r)
+	}
+
+	result := make([]apimodels.AlertRuleGroupExport, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *groups[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Folder != result[j].Folder {
+			return result[i].Folder < result[j].Folder
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func ruleToExport(rule *ngmodels.AlertRule) (apimodels.AlertRuleExport, error) {
+	r := apimodels.AlertRuleExport{
+		UID:          rule.UID,
+		Title:        rule.Title,
+		Condition:    rule.Condition,
+		NoDataState:  string(rule.NoDataState),
+		ExecErrState: string(rule.ExecErrState),
+		Annotations:  rule.Annotations,
+		Labels:       rule.Labels,
+		IsPaused:     rule.IsPaused,
+		Record:       rule.Record,
+	}
+	if rule.For > 0 {
+		r.For = model.Duration(rule.For).String()
+	}
+	if rule.KeepFiringFor > 0 {
+		r.KeepFiringFor = model.Duration(rule.KeepFiringFor).String()
+	}
+
+	for _, q := range rule.Data {
+		var m map[string]interface{}
+		if err := json.Unmarshal(q.Model, &m); err != nil {
+			return apimodels.AlertRuleExport{}, fmt.Errorf("invalid model of query %q of rule %q: %w", q.RefID, rule.UID, err)
+		}
+		r.Data = append(r.Data, apimodels.AlertQueryExport{
+			RefID:     q.RefID,
+			QueryType: q.QueryType,
+			RelativeTimeRange: apimodels.RelativeTimeRangeExport{
+				From: int64(time.Duration(q.RelativeTimeRange.From).Seconds()),
+				To:   int64(time.Duration(q.RelativeTimeRange.To).Seconds()),
+			},
+			DatasourceUID: q.DatasourceUID,
+			Model:         m,
+		})
+	}
+	return r, nil
+}
@@ -0,0 +1,419 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+var invalidHCLNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// hclWriter writes Terraform resources of the Grafana provider in the HCL syntax.
+type hclWriter struct {
+	buf    bytes.Buffer
+	indent int
+	// names counts the names of the resources per type so that they are unique.
+	names map[string]map[string]int
+}
+
+func newHCLWriter() *hclWriter {
+	return &hclWriter{names: map[string]map[string]int{}}
+}
+
+// resource writes a resource of the given type. Its name is derived from the given name so that it is a valid
+// and unique identifier.
+func (w *hclWriter) resource(resourceType, name string, body func()) {
+	name = invalidHCLNameChars.ReplaceAllString(name, "_")
+	if name == "" || !unicode.IsLetter(rune(name[0])) && name[0] != '_' {
+		name = "_" + name
+	}
+	if w.names[resourceType] == nil {
+		w.names[resourceType] = map[string]int{}
+	}
+	w.names[resourceType][name]++
+	if n := w.names[resourceType][name]; n > 1 {
+		name = fmt.Sprintf("%s_%d", name, n)
+	}
+
+	if w.buf.Len() > 0 {
+		w.buf.WriteString("\n")
+	}
+	w.block(fmt.Sprintf("resource %s %s", hclString(resourceType), hclString(name)), body)
+}
+
+func (w *hclWriter) block(header string, body func()) {
+	w.line(header + " {")
+	w.indent++
+	body()
+	w.indent--
+	w.line("}")
+}
+
+// attr writes an attribute with a value that is already in the HCL syntax.
+func (w *hclWriter) attr(name, value string) {
+	w.line(name + " = " + value)
+}
+
+func (w *hclWriter) line(s string) {
+	w.buf.WriteString(strings.Repeat("  ", w.indent))
+	w.buf.WriteString(s)
+	w.buf.WriteString("\n")
+}
+
+// hclString quotes and escapes a string, including the template sequences that Terraform would interpolate.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && i+1 < len(s) && s[i+1] == '{':
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case r < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func hclStringList(l []string) string {
+	values := make([]string, 0, len(l))
+	for _, s := range l {
+		values = append(values, hclString(s))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func hclStringMap(m map[string]string) string {
+	values := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		values[k] = v
+	}
+	return hclValue(values)
+}
+
+// hclValue writes a value decoded from JSON or YAML in the HCL syntax.
+func hclValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return hclString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			values = append(values, hclValue(e))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, 0, len(v))
+		for _, k := range keys {
+			values = append(values, hclString(k)+" = "+hclValue(v[k]))
+		}
+		return "{ " + strings.Join(values, ", ") + " }"
+	default:
+		return hclString(fmt.Sprint(v))
+	}
+}
+
+// hclAttributeName converts the camel case name of a setting to the snake case name of the attribute of the provider.
+func hclAttributeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return invalidHCLNameChars.ReplaceAllString(b.String(), "_")
+}
+
+// exportToHCL writes the export as resources of the Grafana Terraform provider.
+func exportToHCL(export apimodels.AlertingFileExport) ([]byte, error) {
+	w := newHCLWriter()
+	for _, g := range export.Groups {
+		if err := writeRuleGroupHCL(w, g); err != nil {
+			return nil, err
+		}
+	}
+	for _, cp := range export.ContactPoints {
+		writeContactPointHCL(w, cp)
+	}
+	for _, p := range export.Policies {
+		writeNotificationPolicyHCL(w, p)
+	}
+	for _, mt := range export.MuteTimes {
+		if err := writeMuteTimingHCL(w, mt); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range export.Templates {
+		w.resource("grafana_message_template", t.Name, func() {
+			w.attr("org_id", strconv.FormatInt(t.OrgID, 10))
+			w.attr("name", hclString(t.Name))
+			w.attr("template", hclString(t.Template))
+		})
+	}
+	return w.buf.Bytes(), nil
+}
+
+func writeRuleGroupHCL(w *hclWriter, g apimodels.AlertRuleGroupExport) error {
+	interval, err := model.ParseDuration(g.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid interval of rule group %q: %w", g.Name, err)
+	}
+	w.resource("grafana_rule_group", g.Folder+"_"+g.Name, func() {
+		w.attr("org_id", strconv.FormatInt(g.OrgID, 10))
+		w.attr("name", hclString(g.Name))
+		w.attr("folder_uid", hclString(g.FolderUID))
+		w.attr("interval_seconds", strconv.FormatInt(int64(time.Duration(interval).Seconds()), 10))
+		for _, r := range g.Rules {
+			w.block("rule", func() {
+				w.attr("name", hclString(r.Title))
+				w.attr("uid", hclString(r.UID))
+				if r.Condition != "" {
+					w.attr("condition", hclString(r.Condition))
+				}
+				if r.For != "" {
+					w.attr("for", hclString(r.For))
+				}
+				if r.KeepFiringFor != "" {
+					w.attr("keep_firing_for", hclString(r.KeepFiringFor))
+				}
+				if r.NoDataState != "" {
+					w.attr("no_data_state", hclString(r.NoDataState))
+				}
+				if r.ExecErrState != "" {
+					w.attr("exec_err_state", hclString(r.ExecErrState))
+				}
+				if len(r.Annotations) > 0 {
+					w.attr("annotations", hclStringMap(r.Annotations))
+				}
+				if len(r.Labels) > 0 {
+					w.attr("labels", hclStringMap(r.Labels))
+				}
+				w.attr("is_paused", strconv.FormatBool(r.IsPaused))
+				if r.Record != nil {
+					w.block("record", func() {
+						w.attr("metric", hclString(r.Record.Metric))
+						w.attr("from", hclString(r.Record.From))
+					})
+				}
+				for _, q := range r.Data {
+					w.block("data", func() {
+						w.attr("ref_id", hclString(q.RefID))
+						if q.QueryType != "" {
+							w.attr("query_type", hclString(q.QueryType))
+						}
+						w.attr("datasource_uid", hclString(q.DatasourceUID))
+						w.block("relative_time_range", func() {
+							w.attr("from", strconv.FormatInt(q.RelativeTimeRange.From, 10))
+							w.attr("to", strconv.FormatInt(q.RelativeTimeRange.To, 10))
+						})
+						w.attr("model", "jsonencode("+hclValue(q.Model)+")")
+					})
+				}
+			})
+		}
+	})
+	return nil
+}
+
+// hclReceiverTypes maps the types of the receivers whose block has a different name in the provider.
+var hclReceiverTypes = map[string]string{
+	"prometheus-alertmanager": "alertmanager",
+}
+
+func writeContactPointHCL(w *hclWriter, cp apimodels.ContactPointExport) {
+	w.resource("grafana_contact_point", cp.Name, func() {
+		w.attr("org_id", strconv.FormatInt(cp.OrgID, 10))
+		w.attr("name", hclString(cp.Name))
+		for _, r := range cp.Receivers {
+			blockType, ok := hclReceiverTypes[r.Type]
+			if !ok {
+				blockType = hclAttributeName(r.Type)
+			}
+			w.block(blockType, func() {
+				w.attr("uid", hclString(r.UID))
+				w.attr("disable_resolve_message", strconv.FormatBool(r.DisableResolveMessage))
+				settings := make(map[string]string, len(r.Settings)+len(r.SecureSettings))
+				for k, v := range r.Settings {
+					settings[hclAttributeName(k)] = hclValue(v)
+				}
+				for k, v := range r.SecureSettings {
+					settings[hclAttributeName(k)] = hclString(v)
+				}
+				names := make([]string, 0, len(settings))
+				for k := range settings {
+					names = append(names, k)
+				}
+				sort.Strings(names)
+				for _, k := range names {
+					w.attr(k, settings[k])
+				}
+			})
+		}
+	})
+}
+
+func writeNotificationPolicyHCL(w *hclWriter, p apimodels.NotificationPolicyExport) {
+	w.resource("grafana_notification_policy", fmt.Sprintf("policy_%d", p.OrgID), func() {
+		w.attr("org_id", strconv.FormatInt(p.OrgID, 10))
+		writeRouteHCL(w, &p.Route, true)
+	})
+}
+
+func writeRouteHCL(w *hclWriter, r *apimodels.Route, root bool) {
+	if r.Receiver != "" {
+		w.attr("contact_point", hclString(r.Receiver))
+	}
+	if len(r.GroupByStr) > 0 {
+		w.attr("group_by", hclStringList(r.GroupByStr))
+	}
+	if !root {
+		var matchers labels.Matchers
+		// the deprecated matchers of the route are written as the matchers that replace them
+		for name, value := range r.Match {
+			matchers = append(matchers, &labels.Matcher{Type: labels.MatchEqual, Name: name, Value: value})
+		}
+		for name, re := range r.MatchRE {
+			value, _ := re.MarshalYAML()
+			s, _ := value.(string)
+			matchers = append(matchers, &labels.Matcher{Type: labels.MatchRegexp, Name: name, Value: s})
+		}
+		sort.Sort(matchers)
+		matchers = append(matchers, r.Matchers...)
+		matchers = append(matchers, r.ObjectMatchers...)
+		for _, m := range matchers {
+			w.block("matcher", func() {
+				w.attr("label", hclString(m.Name))
+				w.attr("match", hclString(m.Type.String()))
+				w.attr("value", hclString(m.Value))
+			})
+		}
+		if len(r.MuteTimeIntervals) > 0 {
+			w.attr("mute_timings", hclStringList(r.MuteTimeIntervals))
+		}
+		if r.Continue {
+			w.attr("continue", "true")
+		}
+	}
+	if r.GroupWait != nil {
+		w.attr("group_wait", hclString(r.GroupWait.String()))
+	}
+	if r.GroupInterval != nil {
+		w.attr("group_interval", hclString(r.GroupInterval.String()))
+	}
+	if r.RepeatInterval != nil {
+		w.attr("repeat_interval", hclString(r.RepeatInterval.String()))
+	}
+	for _, child := range r.Routes {
+		w.block("policy", func() {
+			writeRouteHCL(w, child, false)
+		})
+	}
+}
+
+func writeMuteTimingHCL(w *hclWriter, mt apimodels.MuteTimingExport) error {
+	// The ranges of the time intervals are written in the same syntax as in the configuration, for example
+	// monday:friday, which the JSON encoding of the time intervals already does.
+	b, err := json.Marshal(mt.TimeIntervals)
+	if err != nil {
+		return fmt.Errorf("invalid time intervals of mute timing %q: %w", mt.Name, err)
+	}
+	var intervals []struct {
+		Times []struct {
+			StartTime string `json:"start_time"`
+			EndTime   string `json:"end_time"`
+		} `json:"times"`
+		Weekdays    []string              `json:"weekdays"`
+		DaysOfMonth []string              `json:"days_of_month"`
+		Months      []string              `json:"months"`
+		Years       []string              `json:"years"`
+		Location    string                `json:"location"`
+		DateRanges  []apimodels.DateRange `json:"date_ranges"`
+	}
+	if err := json.Unmarshal(b, &intervals); err != nil {
+		return fmt.Errorf("invalid time intervals of mute timing %q: %w", mt.Name, err)
+	}
+
+	w.resource("grafana_mute_timing", mt.Name, func() {
+		w.attr("org_id", strconv.FormatInt(mt.OrgID, 10))
+		w.attr("name", hclString(mt.Name))
+		for _, ti := range intervals {
+			w.block("intervals", func() {
+				for _, tr := range ti.Times {
+					w.block("times", func() {
+						w.attr("start", hclString(tr.StartTime))
+						w.attr("end", hclString(tr.EndTime))
+					})
+				}
+				if len(ti.Weekdays) > 0 {
+					w.attr("weekdays", hclStringList(ti.Weekdays))
+				}
+				if len(ti.DaysOfMonth) > 0 {
+					w.attr("days_of_month", hclStringList(ti.DaysOfMonth))
+				}
+				if len(ti.Months) > 0 {
+					w.attr("months", hclStringList(ti.Months))
+				}
+				if len(ti.Years) > 0 {
+					w.attr("years", hclStringList(ti.Years))
+				}
+				if ti.Location != "" {
+					w.attr("location", hclString(ti.Location))
+				}
+				for _, dr := range ti.DateRanges {
+					w.block("date_ranges", func() {
+						w.attr("start_date", hclString(dr.StartDate))
+						w.attr("end_date", hclString(dr.EndDate))
+					})
+				}
+			})
+		}
+	})
+	return nil
+}
@@ -0,0 +1,345 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRulesToExport(t *testing.T) {
+	folders := map[string]*models.Folder{
+		"folder-a": {Uid: "folder-a", Title: "A"},
+		"folder-b": {Uid: "folder-b", Title: "B"},
+	}
+	newRule := func(uid, folderUID, group string) *ngmodels.AlertRule {
+		return &ngmodels.AlertRule{
+			OrgID:           1,
+			UID:             uid,
+			Title:           "rule " + uid,
+			Condition:       "A",
+			NamespaceUID:    folderUID,
+			RuleGroup:       group,
+			IntervalSeconds: 60,
+			NoDataState:     ngmodels.NoData,
+			ExecErrState:    ngmodels.AlertingErrState,
+			Data: []ngmodels.AlertQuery{{
+				RefID:             "A",
+				RelativeTimeRange: ngmodels.RelativeTimeRange{From: ngmodels.Duration(10 * time.Minute)},
+				DatasourceUID:     "-100",
+				Model:             json.RawMessage(`{"type": "math", "expression": "2 + 3 > 1"}`),
+			}},
+		}
+	}
+
+	rule := newRule("rule-1", "folder-b", "group")
+	rule.For = 5 * time.Minute
+	rule.KeepFiringFor = 90 * time.Second
+	rule.Labels = map[string]string{"team": "ops"}
+	recordingRule := newRule("rule-2", "folder-b", "group")
+	recordingRule.Condition = ""
+	recordingRule.Record = &ngmodels.Record{Metric: "my_metric", From: "A"}
+	otherFolderRule := newRule("rule-3", "folder-a", "group")
+	hiddenRule := newRule("rule-4", "folder-c", "group")
+
+	groups, err := rulesToExport([]*ngmodels.AlertRule{rule, otherFolderRule, recordingRule, hiddenRule}, folders)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.Equal(t, "A", groups[0].Folder)
+	require.Len(t, groups[0].Rules, 1)
+
+	require.Equal(t, apimodels.AlertRuleGroupExport{
+		OrgID:     1,
+		Name:      "group",
+		Folder:    "B",
+		FolderUID: "folder-b",
+		Interval:  "1m",
+		Rules: []apimodels.AlertRuleExport{
+			{
+				UID:       "rule-1",
+				Title:     "rule rule-1",
+				Condition: "A",
+				Data: []apimodels.AlertQueryExport{{
+					RefID:             "A",
+					RelativeTimeRange: apimodels.RelativeTimeRangeExport{From: 600},
+					DatasourceUID:     "-100",
+					Model:             map[string]interface{}{"type": "math", "expression": "2 + 3 > 1"},
+				}},
+				For:           "5m",
+				KeepFiringFor: "1m30s",
+				NoDataState:   "NoData",
+				ExecErrState:  "Alerting",
+				Labels:        map[string]string{"team": "ops"},
+			},
+			{
+				UID:   "rule-2",
+				Title: "rule rule-2",
+				Data: []apimodels.AlertQueryExport{{
+					RefID:             "A",
+					RelativeTimeRange: apimodels.RelativeTimeRangeExport{From: 600},
+					DatasourceUID:     "-100",
+					Model:             map[string]interface{}{"type": "math", "expression": "2 + 3 > 1"},
+				}},
+				NoDataState:  "NoData",
+				ExecErrState: "Alerting",
+				Record:       &ngmodels.Record{Metric: "my_metric", From: "A"},
+			},
+		},
+	}, groups[1])
+
+	b, err := yaml.Marshal(apimodels.AlertingFileExport{APIVersion: 1, Groups: groups[:1]})
+	require.NoError(t, err)
+	require.Equal(t, `apiVersion: 1
+groups:
+    - orgId: 1
+      name: group
+      folder: A
+      interval: 1m
+      rules:
+        - uid: rule-3
+          title: rule rule-3
+          condition: A
+          data:
+            - refId: A
+              relativeTimeRange:
+                from: 600
+                to: 0
+              datasourceUid: "-100"
+              model:
+                expression: 2 + 3 > 1
+                type: math
+          noDataState: NoData
+          execErrState: Alerting
+`, string(b))
+}
+
+const exportTestConfig = `{
+	"template_files": {
+		"my-template": "{{ define \"my-template\" }}custom message{{ end }}"
+	},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "ops",
+			"group_by": ["alertname"],
+			"routes": [{
+				"receiver": "ops",
+				"object_matchers": [["team", "=", "ops"]],
+				"mute_time_intervals": ["weekends"],
+				"group_wait": "1m"
+			}]
+		},
+		"mute_time_intervals": [{
+			"name": "weekends",
+			"time_intervals": [{"weekdays": ["saturday", "sunday"], "location": "Europe/Berlin"}]
+		}],
+		"receivers": [{
+			"name": "ops",
+			"grafana_managed_receiver_configs": [{
+				"uid": "ops-slack",
+				"name": "ops",
+				"type": "slack",
+				"disableResolveMessage": false,
+				"settings": {"recipient": "#ops", "mentionChannel": "here"},
+				"secureSettings": {"url": "aHR0cHM6Ly9ob29rcy5zbGFjay5jb20vc2VydmljZXMvc2VjcmV0"}
+			}]
+		}]
+	}
+}`
+
+func TestRouteGetAlertingConfigExport(t *testing.T) {
+	store := newFakeAlertingStore(t)
+	store.SetupWithConfig(1, exportTestConfig)
+	sut := AlertmanagerSrv{store: store, provenanceStore: newFakeProvisioningStore(), secrets: fakes.NewFakeSecretsService()}
+
+	export := func(t *testing.T, role models.RoleType, query string) (int, string) {
+		t.Helper()
+		rc := models.ReqContext{
+			Context: &web.Context{
+				Req: httptest.NewRequest("GET", "/api/alertmanager/grafana/config/api/v1/export?"+query, nil),
+			},
+			SignedInUser: &models.SignedInUser{
+				OrgRole: role,
+				OrgId:   1,
+			},
+		}
+		resp := sut.RouteGetAlertingConfigExport(&rc)
+		return resp.Status(), string(resp.Body())
+	}
+
+	t.Run("exports the configuration with redacted secure settings", func(t *testing.T) {
+		status, body := export(t, models.ROLE_EDITOR, "")
+		require.Equal(t, 200, status)
+		require.Equal(t, `apiVersion: 1
+contactPoints:
+    - orgId: 1
+      name: ops
+      receivers:
+        - uid: ops-slack
+          type: slack
+          settings:
+            mentionChannel: here
+            recipient: '#ops'
+          secureSettings:
+            url: '[REDACTED]'
+policies:
+    - orgId: 1
+      receiver: ops
+      group_by:
+        - alertname
+      continue: false
+      routes:
+        - receiver: ops
+          object_matchers:
+            - - team
+              - =
+              - ops
+          mute_time_intervals:
+            - weekends
+          continue: false
+          group_wait: 1m
+muteTimes:
+    - orgId: 1
+      name: weekends
+      time_intervals:
+        - weekdays: [saturday, sunday]
+          location: Europe/Berlin
+templates:
+    - orgId: 1
+      name: my-template
+      template: '{{ define "my-template" }}custom message{{ end }}'
+`, body)
+	})
+
+	t.Run("exports the decrypted secure settings to admins", func(t *testing.T) {
+		status, body := export(t, models.ROLE_ADMIN, "decrypt=true&format=json")
+		require.Equal(t, 200, status)
+		var result apimodels.AlertingFileExport
+		require.NoError(t, json.Unmarshal([]byte(body), &result))
+		require.Equal(t, map[string]string{"url": "https://hooks.slack.com/services/secret"}, result.ContactPoints[0].Receivers[0].SecureSettings)
+	})
+
+	t.Run("exports the configuration as Terraform resources", func(t *testing.T) {
+		status, body := export(t, models.ROLE_EDITOR, "format=hcl")
+		require.Equal(t, 200, status)
+		require.Equal(t, `resource "grafana_contact_point" "ops" {
+  org_id = 1
+  name = "ops"
+  slack {
+    uid = "ops-slack"
+    disable_resolve_message = false
+    mention_channel = "here"
+    recipient = "#ops"
+    url = "[REDACTED]"
+  }
+}
+
+resource "grafana_notification_policy" "policy_1" {
+  org_id = 1
+  contact_point = "ops"
+  group_by = ["alertname"]
+  policy {
+    contact_point = "ops"
+    matcher {
+      label = "team"
+      match = "="
+      value = "ops"
+    }
+    mute_timings = ["weekends"]
+    group_wait = "1m"
+  }
+}
+
+resource "grafana_mute_timing" "weekends" {
+  org_id = 1
+  name = "weekends"
+  intervals {
+    weekdays = ["saturday", "sunday"]
+    location = "Europe/Berlin"
+  }
+}
+
+resource "grafana_message_template" "my-template" {
+  org_id = 1
+  name = "my-template"
+  template = "{{ define \"my-template\" }}custom message{{ end }}"
+}
+`, body)
+	})
+
+	t.Run("requires the Editor role", func(t *testing.T) {
+		status, _ := export(t, models.ROLE_VIEWER, "")
+		require.Equal(t, 403, status)
+	})
+
+	t.Run("requires the Admin role to decrypt the secure settings", func(t *testing.T) {
+		status, _ := export(t, models.ROLE_EDITOR, "decrypt=true")
+		require.Equal(t, 403, status)
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		status, body := export(t, models.ROLE_EDITOR, "format=xml")
+		require.Equal(t, 400, status)
+		require.Contains(t, body, `unsupported export format \"xml\"`)
+	})
+}
+
+func TestExportRuleGroupToHCL(t *testing.T) {
+	b, err := exportToHCL(apimodels.AlertingFileExport{
+		Groups: []apimodels.AlertRuleGroupExport{{
+			OrgID:     1,
+			Name:      "my group",
+			Folder:    "1st folder",
+			FolderUID: "folder-uid",
+			Interval:  "1m",
+			Rules: []apimodels.AlertRuleExport{{
+				UID:       "rule-uid",
+				Title:     "My rule",
+				Condition: "A",
+				Data: []apimodels.AlertQueryExport{{
+					RefID:             "A",
+					RelativeTimeRange: apimodels.RelativeTimeRangeExport{From: 600},
+					DatasourceUID:     "-100",
+					Model:             map[string]interface{}{"type": "math", "expression": "2 + 3 > 1"},
+				}},
+				For:         "5m",
+				NoDataState: "OK",
+				Annotations: map[string]string{"summary": "${value} is {{ $value }}\n"},
+			}},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `resource "grafana_rule_group" "_1st_folder_my_group" {
+  org_id = 1
+  name = "my group"
+  folder_uid = "folder-uid"
+  interval_seconds = 60
+  rule {
+    name = "My rule"
+    uid = "rule-uid"
+    condition = "A"
+    for = "5m"
+    no_data_state = "OK"
+    annotations = { "summary" = "$${value} is {{ $value }}\n" }
+    is_paused = false
+    data {
+      ref_id = "A"
+      datasource_uid = "-100"
+      relative_time_range {
+        from = 600
+        to = 0
+      }
+      model = jsonencode({ "expression" = "2 + 3 > 1", "type" = "math" })
+    }
+  }
+}
+`, string(b))
+}
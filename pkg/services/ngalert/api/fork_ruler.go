@@ -136,3 +136,19 @@ func (f *ForkedRulerApi) forkRoutePostNameGrafanaRulesConfig(ctx *models.ReqCont
 	}
 	return f.GrafanaRuler.RoutePostNameRulesConfig(ctx, conf)
 }
+
+func (f *ForkedRulerApi) forkRouteGetGrafanaRuleStateHistory(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RouteGetRuleStateHistory(ctx)
+}
+
+func (f *ForkedRulerApi) forkRoutePostPauseGrafanaRule(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RoutePostPauseRule(ctx)
+}
+
+func (f *ForkedRulerApi) forkRoutePostResumeGrafanaRule(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RoutePostResumeRule(ctx)
+}
+
+func (f *ForkedRulerApi) forkRouteGetGrafanaRulesExport(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RouteGetRulesExport(ctx)
+}
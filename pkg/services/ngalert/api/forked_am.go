@@ -179,10 +179,18 @@ func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaAMAlertGroups(ctx *models.Req
 	return f.GrafanaSvc.RouteGetAMAlertGroups(ctx)
 }
 
+func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaReceivers(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetReceivers(ctx)
+}
+
 func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaAlertingConfig(ctx *models.ReqContext) response.Response {
 	return f.GrafanaSvc.RouteGetAlertingConfig(ctx)
 }
 
+func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaAlertingConfigExport(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetAlertingConfigExport(ctx)
+}
+
 func (f *ForkedAlertmanagerApi) forkRouteGetGrafanaSilence(ctx *models.ReqContext) response.Response {
 	return f.GrafanaSvc.RouteGetSilence(ctx)
 }
@@ -202,3 +210,7 @@ func (f *ForkedAlertmanagerApi) forkRoutePostGrafanaAlertingConfig(ctx *models.R
 func (f *ForkedAlertmanagerApi) forkRoutePostTestGrafanaReceivers(ctx *models.ReqContext, conf apimodels.TestReceiversConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
+
+func (f *ForkedAlertmanagerApi) forkRoutePostTestGrafanaTemplates(ctx *models.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
+}
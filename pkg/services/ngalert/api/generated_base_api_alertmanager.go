@@ -33,6 +33,8 @@ type AlertmanagerApiForkingService interface {
 	RouteGetGrafanaAMAlerts(*models.ReqContext) response.Response
 	RouteGetGrafanaAMStatus(*models.ReqContext) response.Response
 	RouteGetGrafanaAlertingConfig(*models.ReqContext) response.Response
+	RouteGetGrafanaAlertingConfigExport(*models.ReqContext) response.Response
+	RouteGetGrafanaReceivers(*models.ReqContext) response.Response
 	RouteGetGrafanaSilence(*models.ReqContext) response.Response
 	RouteGetGrafanaSilences(*models.ReqContext) response.Response
 	RouteGetSilence(*models.ReqContext) response.Response
@@ -42,6 +44,7 @@ type AlertmanagerApiForkingService interface {
 	RoutePostGrafanaAMAlerts(*models.ReqContext) response.Response
 	RoutePostGrafanaAlertingConfig(*models.ReqContext) response.Response
 	RoutePostTestGrafanaReceivers(*models.ReqContext) response.Response
+	RoutePostTestGrafanaTemplates(*models.ReqContext) response.Response
 	RoutePostTestReceivers(*models.ReqContext) response.Response
 }
 
@@ -109,6 +112,14 @@ func (f *ForkedAlertmanagerApi) RouteGetGrafanaAlertingConfig(ctx *models.ReqCon
 	return f.forkRouteGetGrafanaAlertingConfig(ctx)
 }
 
+func (f *ForkedAlertmanagerApi) RouteGetGrafanaAlertingConfigExport(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaAlertingConfigExport(ctx)
+}
+
+func (f *ForkedAlertmanagerApi) RouteGetGrafanaReceivers(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaReceivers(ctx)
+}
+
 func (f *ForkedAlertmanagerApi) RouteGetGrafanaSilence(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetGrafanaSilence(ctx)
 }
@@ -165,6 +176,14 @@ func (f *ForkedAlertmanagerApi) RoutePostTestGrafanaReceivers(ctx *models.ReqCon
 	return f.forkRoutePostTestGrafanaReceivers(ctx, conf)
 }
 
+func (f *ForkedAlertmanagerApi) RoutePostTestGrafanaTemplates(ctx *models.ReqContext) response.Response {
+	conf := apimodels.TestTemplatesConfigBodyParams{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostTestGrafanaTemplates(ctx, conf)
+}
+
 func (f *ForkedAlertmanagerApi) RoutePostTestReceivers(ctx *models.ReqContext) response.Response {
 	conf := apimodels.TestReceiversConfigBodyParams{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -305,6 +324,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/config/api/v1/receivers"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/api/v1/receivers"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/config/api/v1/receivers",
+				srv.RouteGetGrafanaReceivers,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/alertmanager/grafana/config/api/v1/alerts"),
 			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/api/v1/alerts"),
@@ -315,6 +344,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/config/api/v1/export"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/api/v1/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/config/api/v1/export",
+				srv.RouteGetGrafanaAlertingConfigExport,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/alertmanager/grafana/api/v2/silence/{SilenceId}"),
 			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v2/silence/{SilenceId}"),
@@ -405,6 +444,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApiForkingServi
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/config/api/v1/templates/test"),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/config/api/v1/templates/test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/config/api/v1/templates/test",
+				srv.RoutePostTestGrafanaTemplates,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/alertmanager/{Recipient}/config/api/v1/receivers/test"),
 			api.authorize(http.MethodPost, "/api/alertmanager/{Recipient}/config/api/v1/receivers/test"),
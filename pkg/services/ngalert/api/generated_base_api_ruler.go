@@ -24,13 +24,17 @@ type RulerApiForkingService interface {
 	RouteDeleteNamespaceRulesConfig(*models.ReqContext) response.Response
 	RouteDeleteRuleGroupConfig(*models.ReqContext) response.Response
 	RouteGetGrafanaRuleGroupConfig(*models.ReqContext) response.Response
+	RouteGetGrafanaRuleStateHistory(*models.ReqContext) response.Response
 	RouteGetGrafanaRulesConfig(*models.ReqContext) response.Response
+	RouteGetGrafanaRulesExport(*models.ReqContext) response.Response
 	RouteGetNamespaceGrafanaRulesConfig(*models.ReqContext) response.Response
 	RouteGetNamespaceRulesConfig(*models.ReqContext) response.Response
 	RouteGetRulegGroupConfig(*models.ReqContext) response.Response
 	RouteGetRulesConfig(*models.ReqContext) response.Response
 	RoutePostNameGrafanaRulesConfig(*models.ReqContext) response.Response
 	RoutePostNameRulesConfig(*models.ReqContext) response.Response
+	RoutePostPauseGrafanaRule(*models.ReqContext) response.Response
+	RoutePostResumeGrafanaRule(*models.ReqContext) response.Response
 }
 
 func (f *ForkedRulerApi) RouteDeleteGrafanaRuleGroupConfig(ctx *models.ReqContext) response.Response {
@@ -89,6 +93,22 @@ func (f *ForkedRulerApi) RoutePostNameRulesConfig(ctx *models.ReqContext) respon
 	return f.forkRoutePostNameRulesConfig(ctx, conf)
 }
 
+func (f *ForkedRulerApi) RouteGetGrafanaRuleStateHistory(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaRuleStateHistory(ctx)
+}
+
+func (f *ForkedRulerApi) RoutePostPauseGrafanaRule(ctx *models.ReqContext) response.Response {
+	return f.forkRoutePostPauseGrafanaRule(ctx)
+}
+
+func (f *ForkedRulerApi) RoutePostResumeGrafanaRule(ctx *models.ReqContext) response.Response {
+	return f.forkRoutePostResumeGrafanaRule(ctx)
+}
+
+func (f *ForkedRulerApi) RouteGetGrafanaRulesExport(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaRulesExport(ctx)
+}
+
 func (api *API) RegisterRulerApiEndpoints(srv RulerApiForkingService, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
 		group.Delete(
@@ -211,5 +231,45 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApiForkingService, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/history"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/history",
+				srv.RouteGetGrafanaRuleStateHistory,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/pause"),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rule/{RuleUID}/pause"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/pause",
+				srv.RoutePostPauseGrafanaRule,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/resume"),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rule/{RuleUID}/resume"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/resume",
+				srv.RoutePostResumeGrafanaRule,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/export/rules"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/export/rules"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/export/rules",
+				srv.RouteGetGrafanaRulesExport,
+				m,
+			),
+		)
 	})
 }
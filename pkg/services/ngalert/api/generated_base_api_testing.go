@@ -19,11 +19,20 @@ import (
 )
 
 type TestingApiForkingService interface {
+	RouteBacktestGrafanaRule(*models.ReqContext) response.Response
 	RouteEvalQueries(*models.ReqContext) response.Response
 	RouteTestRuleConfig(*models.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*models.ReqContext) response.Response
 }
 
+func (f *ForkedTestingApi) RouteBacktestGrafanaRule(ctx *models.ReqContext) response.Response {
+	conf := apimodels.BacktestRulePayload{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRouteBacktestGrafanaRule(ctx, conf)
+}
+
 func (f *ForkedTestingApi) RouteEvalQueries(ctx *models.ReqContext) response.Response {
 	conf := apimodels.EvalQueriesPayload{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -50,6 +59,16 @@ func (f *ForkedTestingApi) RouteTestRuleGrafanaConfig(ctx *models.ReqContext) re
 
 func (api *API) RegisterTestingApiEndpoints(srv TestingApiForkingService, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest/grafana"),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest/grafana"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest/grafana",
+				srv.RouteBacktestGrafanaRule,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/eval"),
 			api.authorize(http.MethodPost, "/api/v1/eval"),
@@ -10,6 +10,7 @@ import (
 
 type FakeAlertingStore struct {
 	orgsWithConfig map[int64]bool
+	configs        map[int64]string
 }
 
 func newFakeAlertingStore(t *testing.T) FakeAlertingStore {
@@ -17,6 +18,7 @@ func newFakeAlertingStore(t *testing.T) FakeAlertingStore {
 
 	return FakeAlertingStore{
 		orgsWithConfig: map[int64]bool{},
+		configs:        map[int64]string{},
 	}
 }
 
@@ -24,9 +26,56 @@ func (f FakeAlertingStore) Setup(orgID int64) {
 	f.orgsWithConfig[orgID] = true
 }
 
+// SetupWithConfig makes the store return the given Alertmanager configuration for the organization.
+func (f FakeAlertingStore) SetupWithConfig(orgID int64, config string) {
+	f.orgsWithConfig[orgID] = true
+	f.configs[orgID] = config
+}
+
 func (f FakeAlertingStore) GetLatestAlertmanagerConfiguration(_ context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
 	if _, ok := f.orgsWithConfig[query.OrgID]; ok {
+		if config, ok := f.configs[query.OrgID]; ok {
+			query.Result = &models.AlertConfiguration{AlertmanagerConfiguration: config, OrgID: query.OrgID}
+		}
 		return nil
 	}
 	return store.ErrNoAlertmanagerConfiguration
 }
+
+type fakeProvisioningStore struct {
+	records map[int64]map[string]map[string]models.Provenance // orgID > resourceType > resourceID > provenance
+}
+
+func newFakeProvisioningStore() *fakeProvisioningStore {
+	return &fakeProvisioningStore{
+		records: map[int64]map[string]map[string]models.Provenance{},
+	}
+}
+
+func (f *fakeProvisioningStore) GetProvenance(_ context.Context, o models.Provisionable) (models.Provenance, error) {
+	return f.records[o.ResourceOrgID()][o.ResourceType()][o.ResourceID()], nil
+}
+
+func (f *fakeProvisioningStore) GetProvenances(_ context.Context, orgID int64, resourceType string) (map[string]models.Provenance, error) {
+	result := make(map[string]models.Provenance)
+	for id, p := range f.records[orgID][resourceType] {
+		result[id] = p
+	}
+	return result, nil
+}
+
+func (f *fakeProvisioningStore) SetProvenance(_ context.Context, o models.Provisionable, p models.Provenance) error {
+	if _, ok := f.records[o.ResourceOrgID()]; !ok {
+		f.records[o.ResourceOrgID()] = map[string]map[string]models.Provenance{}
+	}
+	if _, ok := f.records[o.ResourceOrgID()][o.ResourceType()]; !ok {
+		f.records[o.ResourceOrgID()][o.ResourceType()] = map[string]models.Provenance{}
+	}
+	f.records[o.ResourceOrgID()][o.ResourceType()][o.ResourceID()] = p
+	return nil
+}
+
+func (f *fakeProvisioningStore) DeleteProvenance(_ context.Context, o models.Provisionable) error {
+	delete(f.records[o.ResourceOrgID()][o.ResourceType()], o.ResourceID())
+	return nil
+}
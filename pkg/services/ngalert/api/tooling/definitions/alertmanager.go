@@ -134,6 +134,16 @@ import (
 //       200: alertGroups
 //       400: ValidationError
 
+// swagger:route GET /api/alertmanager/grafana/config/api/v1/receivers alertmanager RouteGetGrafanaReceivers
+//
+// Get the delivery status of the integrations of the Grafana managed receivers.
+//
+//     Responses:
+//       200: GettableReceiversStatus
+//       403: PermissionDenied
+//       404: AlertManagerNotFound
+//       409: AlertManagerNotReady
+
 // swagger:route POST /api/alertmanager/grafana/config/api/v1/receivers/test alertmanager RoutePostTestGrafanaReceivers
 //
 // Test Grafana managed receivers without saving them.
@@ -162,6 +172,17 @@ import (
 //       408: Failure
 //       409: AlertManagerNotReady
 
+// swagger:route POST /api/alertmanager/grafana/config/api/v1/templates/test alertmanager RoutePostTestGrafanaTemplates
+//
+// Render a notification template against sample or currently firing alerts without saving it.
+//
+//     Responses:
+//
+//       200: TestTemplatesResults
+//       400: ValidationError
+//       403: PermissionDenied
+//       409: AlertManagerNotReady
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -279,6 +300,101 @@ type TestReceiverConfigResult struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// swagger:model
+type GettableReceiversStatus []GettableReceiverStatus
+
+// swagger:model
+type GettableReceiverStatus struct {
+	Name         string                      `json:"name"`
+	Integrations []GettableIntegrationStatus `json:"integrations"`
+}
+
+// swagger:model
+type GettableIntegrationStatus struct {
+	// Name of the integration.
+	Name string `json:"name"`
+	UID  string `json:"uid"`
+	// Type of the integration, for example slack or pagerduty.
+	Type         string `json:"type"`
+	SendResolved bool   `json:"sendResolved"`
+	// LastNotifyAttempt is the time of the last attempt to send a notification. It is not set if there has been none since Grafana started.
+	LastNotifyAttempt *time.Time `json:"lastNotifyAttempt,omitempty"`
+	// LastNotifyAttemptDuration is how long the last attempt took, for example 1.5s.
+	LastNotifyAttemptDuration string                    `json:"lastNotifyAttemptDuration,omitempty"`
+	LastNotifyAttemptStatus   NotificationAttemptStatus `json:"lastNotifyAttemptStatus,omitempty"`
+	LastNotifyAttemptError    string                    `json:"lastNotifyAttemptError,omitempty"`
+	// Attempts are the last attempts to send a notification, the most recent first.
+	Attempts []NotificationAttempt `json:"attempts"`
+}
+
+// swagger:enum NotificationAttemptStatus
+type NotificationAttemptStatus string
+
+const (
+	NotificationAttemptSuccess NotificationAttemptStatus = "success"
+	NotificationAttemptFailed  NotificationAttemptStatus = "failed"
+)
+
+// swagger:model
+type NotificationAttempt struct {
+	Time time.Time `json:"time"`
+	// Duration of the attempt, for example 1.5s.
+	Duration string                    `json:"duration"`
+	Status   NotificationAttemptStatus `json:"status"`
+	Error    string                    `json:"error,omitempty"`
+	// GroupKey identifies the group of alerts that was notified.
+	GroupKey string `json:"groupKey"`
+	// Alerts is the number of alerts in the notification.
+	Alerts int `json:"alerts"`
+}
+
+// swagger:parameters RoutePostTestGrafanaTemplates
+type TestTemplatesConfigParams struct {
+	// in:body
+	Body TestTemplatesConfigBodyParams
+}
+
+type TestTemplatesConfigBodyParams struct {
+	// Alerts to render the template with. When empty, the alerts that are currently firing are used,
+	// or a test alert if there are none.
+	Alerts []*amv2.PostableAlert `yaml:"alerts,omitempty" json:"alerts,omitempty"`
+	// Template is the text of the template file to test.
+	Template string `yaml:"template" json:"template"`
+	// Name of the template file. A template file of the configuration with the same name is replaced by the tested one.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+}
+
+// swagger:model
+type TestTemplatesResults struct {
+	Results []TestTemplatesResult      `json:"results"`
+	Errors  []TestTemplatesErrorResult `json:"errors"`
+}
+
+// swagger:model
+type TestTemplatesResult struct {
+	// Name of the rendered template, or of the template file when it does not define templates.
+	Name string `json:"name"`
+	// Text is the rendered template.
+	Text string `json:"text"`
+}
+
+// swagger:enum TemplateErrorKind
+type TemplateErrorKind string
+
+const (
+	TemplateInvalidError   TemplateErrorKind = "invalid_template"
+	TemplateExecutionError TemplateErrorKind = "execution_error"
+)
+
+// swagger:model
+type TestTemplatesErrorResult struct {
+	Name string            `json:"name"`
+	Kind TemplateErrorKind `json:"kind"`
+	// Line of the template file where the error is, if known.
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -692,11 +808,11 @@ func (c *GettableApiAlertingConfig) validate() error {
 
 // Config is the top-level configuration for Alertmanager's config files.
 type Config struct {
-	Global            *config.GlobalConfig      `yaml:"global,omitempty" json:"global,omitempty"`
-	Route             *Route                    `yaml:"route,omitempty" json:"route,omitempty"`
-	InhibitRules      []*config.InhibitRule     `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
-	MuteTimeIntervals []config.MuteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
-	Templates         []string                  `yaml:"templates" json:"templates"`
+	Global            *config.GlobalConfig  `yaml:"global,omitempty" json:"global,omitempty"`
+	Route             *Route                `yaml:"route,omitempty" json:"route,omitempty"`
+	InhibitRules      []*config.InhibitRule `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
+	MuteTimeIntervals []MuteTimeInterval    `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	Templates         []string              `yaml:"templates" json:"templates"`
 }
 
 // A Route is a node that contains definitions of how to handle alerts. This is modified
@@ -122,6 +122,30 @@ import (
 //     Responses:
 //       202: Ack
 
+// swagger:route POST /api/ruler/grafana/api/v1/rule/{RuleUID}/pause ruler RoutePostPauseGrafanaRule
+//
+// Pause the evaluation of a rule
+//
+//     Responses:
+//       202: Ack
+
+// swagger:route POST /api/ruler/grafana/api/v1/rule/{RuleUID}/resume ruler RoutePostResumeGrafanaRule
+//
+// Resume the evaluation of a paused rule
+//
+//     Responses:
+//       202: Ack
+
+// swagger:route GET /api/ruler/grafana/api/v1/rule/{RuleUID}/history ruler RouteGetGrafanaRuleStateHistory
+//
+// Get the history of the state changes of the alert instances of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleStateHistory
+
 // swagger:parameters RoutePostNameRulesConfig RoutePostNameGrafanaRulesConfig
 type NamespaceConfig struct {
 	// in:path
@@ -144,6 +168,51 @@ type PathRouleGroupConfig struct {
 	Groupname string
 }
 
+// swagger:parameters RoutePostPauseGrafanaRule RoutePostResumeGrafanaRule RouteGetGrafanaRuleStateHistory
+type PathRuleUID struct {
+	// in: path
+	RuleUID string
+}
+
+// swagger:parameters RouteGetGrafanaRuleStateHistory
+type RuleStateHistoryParams struct {
+	// Start of the time range in epoch milliseconds
+	// in: query
+	From int64 `json:"from"`
+	// End of the time range in epoch milliseconds
+	// in: query
+	To int64 `json:"to"`
+	// Label matchers of the alert instances, e.g. team="ops"
+	// in: query
+	Matchers []string `json:"matcher"`
+	// Maximum number of state changes to return
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type RuleStateHistory struct {
+	// Instances are ordered by their most recent state change.
+	Instances []InstanceStateHistory `json:"instances"`
+}
+
+// swagger:model
+type InstanceStateHistory struct {
+	Labels map[string]string `json:"labels"`
+	// History is ordered from the most recent state change.
+	History []StateHistoryEntry `json:"history"`
+}
+
+// swagger:model
+type StateHistoryEntry struct {
+	PreviousState string `json:"previousState"`
+	State         string `json:"state"`
+	// Values contains the RefID and value of reduce and math expressions of the evaluation that caused the change.
+	Values    map[string]string `json:"values,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
 // swagger:parameters RouteGetRulesConfig RouteGetGrafanaRulesConfig
 type PathGetRulesParams struct {
 	// in: query
@@ -253,12 +322,13 @@ func (c *GettableRuleGroupConfig) validate() error {
 }
 
 type ApiRuleNode struct {
-	Record      string            `yaml:"record,omitempty" json:"record,omitempty"`
-	Alert       string            `yaml:"alert,omitempty" json:"alert,omitempty"`
-	Expr        string            `yaml:"expr" json:"expr"`
-	For         model.Duration    `yaml:"for,omitempty" json:"for,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Record        string            `yaml:"record,omitempty" json:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty" json:"alert,omitempty"`
+	Expr          string            `yaml:"expr" json:"expr"`
+	For           model.Duration    `yaml:"for,omitempty" json:"for,omitempty"`
+	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty" json:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
 type RuleType int
@@ -348,6 +418,7 @@ const (
 	Alerting NoDataState = "Alerting"
 	NoData   NoDataState = "NoData"
 	OK       NoDataState = "OK"
+	KeepLast NoDataState = "KeepLast"
 )
 
 // swagger:enum ExecutionErrorState
@@ -356,6 +427,7 @@ type ExecutionErrorState string
 const (
 	AlertingErrState ExecutionErrorState = "Alerting"
 	ErrorErrState    ExecutionErrorState = "Error"
+	KeepLastErrState ExecutionErrorState = "KeepLast"
 )
 
 // swagger:model
@@ -366,6 +438,10 @@ type PostableGrafanaRule struct {
 	UID          string              `json:"uid" yaml:"uid"`
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	// Record makes the rule a recording rule that writes its results as series instead of firing alerts.
+	Record *models.Record `json:"record,omitempty" yaml:"record,omitempty"`
+	// IsPaused pauses or resumes the evaluation of the rule. Existing rules keep their current value if it is not set.
+	IsPaused *bool `json:"is_paused,omitempty" yaml:"is_paused,omitempty"`
 }
 
 // swagger:model
@@ -384,4 +460,7 @@ type GettableGrafanaRule struct {
 	RuleGroup       string              `json:"rule_group" yaml:"rule_group"`
 	NoDataState     NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	Record          *models.Record      `json:"record,omitempty" yaml:"record,omitempty"`
+	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	Provenance      models.Provenance   `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 }
@@ -0,0 +1,158 @@
+package definitions
+
+import (
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/ruler/grafana/api/v1/export/rules ruler RouteGetGrafanaRulesExport
+//
+// Export the rule groups in the format of the provisioning files, or as Terraform resources.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+
+// swagger:route GET /api/alertmanager/grafana/config/api/v1/export alertmanager RouteGetGrafanaAlertingConfigExport
+//
+// Export the contact points, notification policies, mute timings and templates in the format of the provisioning files, or as Terraform resources.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+//       403: PermissionDenied
+
+// swagger:enum ExportFormat
+type ExportFormat string
+
+const (
+	ExportFormatYAML ExportFormat = "yaml"
+	ExportFormatJSON ExportFormat = "json"
+	ExportFormatHCL  ExportFormat = "hcl"
+)
+
+// RedactedValue replaces the value of the secure settings of exported contact points unless they are decrypted.
+const RedactedValue = "[REDACTED]"
+
+// swagger:parameters RouteGetGrafanaRulesExport RouteGetGrafanaAlertingConfigExport
+type ExportFormatParams struct {
+	// Format of the export, yaml by default.
+	// in: query
+	Format ExportFormat `json:"format"`
+}
+
+// swagger:parameters RouteGetGrafanaRulesExport
+type RulesExportParams struct {
+	// UIDs of the folders to export the rule groups of. All folders are exported by default.
+	// in: query
+	FolderUIDs []string `json:"folderUid"`
+	// Name of the rule group to export. It requires exactly one folder.
+	// in: query
+	Group string `json:"group"`
+}
+
+// swagger:parameters RouteGetGrafanaAlertingConfigExport
+type AlertingConfigExportParams struct {
+	// Whether to export the decrypted secure settings of the contact points. It requires the Admin role.
+	// in: query
+	Decrypt bool `json:"decrypt"`
+}
+
+// AlertingFileExport is the alerting configuration in the format of the provisioning files.
+// swagger:model
+type AlertingFileExport struct {
+	APIVersion    int64                      `json:"apiVersion" yaml:"apiVersion"`
+	Groups        []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
+	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimes     []MuteTimingExport         `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	Templates     []TemplateExport           `json:"templates,omitempty" yaml:"templates,omitempty"`
+}
+
+// AlertRuleGroupExport is a rule group in the format of the provisioning files.
+type AlertRuleGroupExport struct {
+	OrgID int64  `json:"orgId" yaml:"orgId"`
+	Name  string `json:"name" yaml:"name"`
+	// Folder is the title of the folder of the rule group.
+	Folder string `json:"folder" yaml:"folder"`
+	// FolderUID is only used by the Terraform resources, which refer to folders by UID.
+	FolderUID string            `json:"-" yaml:"-"`
+	Interval  string            `json:"interval" yaml:"interval"`
+	Rules     []AlertRuleExport `json:"rules" yaml:"rules"`
+}
+
+// AlertRuleExport is an alert or recording rule in the format of the provisioning files.
+type AlertRuleExport struct {
+	UID           string             `json:"uid" yaml:"uid"`
+	Title         string             `json:"title" yaml:"title"`
+	Condition     string             `json:"condition,omitempty" yaml:"condition,omitempty"`
+	Data          []AlertQueryExport `json:"data" yaml:"data"`
+	For           string             `json:"for,omitempty" yaml:"for,omitempty"`
+	KeepFiringFor string             `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	NoDataState   string             `json:"noDataState,omitempty" yaml:"noDataState,omitempty"`
+	ExecErrState  string             `json:"execErrState,omitempty" yaml:"execErrState,omitempty"`
+	Annotations   map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Labels        map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
+	IsPaused      bool               `json:"isPaused,omitempty" yaml:"isPaused,omitempty"`
+	Record        *models.Record     `json:"record,omitempty" yaml:"record,omitempty"`
+}
+
+// AlertQueryExport is a query or expression of a rule in the format of the provisioning files.
+type AlertQueryExport struct {
+	RefID             string                  `json:"refId" yaml:"refId"`
+	QueryType         string                  `json:"queryType,omitempty" yaml:"queryType,omitempty"`
+	RelativeTimeRange RelativeTimeRangeExport `json:"relativeTimeRange" yaml:"relativeTimeRange"`
+	DatasourceUID     string                  `json:"datasourceUid" yaml:"datasourceUid"`
+	Model             map[string]interface{}  `json:"model" yaml:"model"`
+}
+
+// RelativeTimeRangeExport is the relative time range of a query in seconds.
+type RelativeTimeRangeExport struct {
+	From int64 `json:"from" yaml:"from"`
+	To   int64 `json:"to" yaml:"to"`
+}
+
+// ContactPointExport is a contact point in the format of the provisioning files.
+type ContactPointExport struct {
+	OrgID     int64            `json:"orgId" yaml:"orgId"`
+	Name      string           `json:"name" yaml:"name"`
+	Receivers []ReceiverExport `json:"receivers" yaml:"receivers"`
+}
+
+// ReceiverExport is an integration of a contact point in the format of the provisioning files.
+type ReceiverExport struct {
+	UID                   string                 `json:"uid" yaml:"uid"`
+	Type                  string                 `json:"type" yaml:"type"`
+	DisableResolveMessage bool                   `json:"disableResolveMessage,omitempty" yaml:"disableResolveMessage,omitempty"`
+	Settings              map[string]interface{} `json:"settings" yaml:"settings"`
+	// SecureSettings are replaced by RedactedValue unless they are decrypted.
+	SecureSettings map[string]string `json:"secureSettings,omitempty" yaml:"secureSettings,omitempty"`
+}
+
+// NotificationPolicyExport is the notification policy tree of an organization in the format of the provisioning files.
+type NotificationPolicyExport struct {
+	OrgID int64 `json:"orgId" yaml:"orgId"`
+	Route `yaml:",inline"`
+}
+
+// MuteTimingExport is a mute timing in the format of the provisioning files.
+type MuteTimingExport struct {
+	OrgID            int64 `json:"orgId" yaml:"orgId"`
+	MuteTimeInterval `yaml:",inline"`
+}
+
+// TemplateExport is a notification template in the format of the provisioning files.
+type TemplateExport struct {
+	OrgID    int64  `json:"orgId" yaml:"orgId"`
+	Name     string `json:"name" yaml:"name"`
+	Template string `json:"template" yaml:"template"`
+}
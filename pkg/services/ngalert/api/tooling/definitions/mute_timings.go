@@ -0,0 +1,170 @@
+package definitions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+)
+
+// dateRangeLayout is the layout of the dates of a DateRange.
+const dateRangeLayout = "2006-01-02"
+
+// MuteTimeInterval represents a named set of time intervals for which a route should be muted.
+// It replaces the one of the upstream Alertmanager so that the time intervals can have a time zone and date ranges.
+type MuteTimeInterval struct {
+	Name          string         `yaml:"name" json:"name"`
+	TimeIntervals []TimeInterval `yaml:"time_intervals" json:"time_intervals"`
+}
+
+// TimeInterval describes intervals of time. It extends the time interval of the upstream Alertmanager
+// with the time zone in which it is evaluated and with absolute date ranges.
+type TimeInterval struct {
+	Times       []timeinterval.TimeRange       `yaml:"times,omitempty" json:"times,omitempty"`
+	Weekdays    []timeinterval.WeekdayRange    `yaml:"weekdays,flow,omitempty" json:"weekdays,omitempty"`
+	DaysOfMonth []timeinterval.DayOfMonthRange `yaml:"days_of_month,flow,omitempty" json:"days_of_month,omitempty"`
+	Months      []timeinterval.MonthRange      `yaml:"months,flow,omitempty" json:"months,omitempty"`
+	Years       []timeinterval.YearRange       `yaml:"years,flow,omitempty" json:"years,omitempty"`
+	// Location is the time zone in which the time interval is evaluated, for example Europe/Berlin. Defaults to UTC.
+	Location *Location `yaml:"location,omitempty" json:"location,omitempty"`
+	// DateRanges are the inclusive ranges of dates, in the time zone of the time interval, that the time interval matches.
+	DateRanges []DateRange `yaml:"date_ranges,omitempty" json:"date_ranges,omitempty"`
+}
+
+// ContainsTime returns true if the time interval contains the given time. The time is converted to the location
+// of the time interval before being compared with its weekdays, times, days, months, years and dates.
+func (ti TimeInterval) ContainsTime(t time.Time) bool {
+	if ti.Location != nil && ti.Location.Location != nil {
+		t = t.In(ti.Location.Location)
+	} else {
+		t = t.UTC()
+	}
+
+	if len(ti.DateRanges) > 0 {
+		in := false
+		for _, dr := range ti.DateRanges {
+			if dr.contains(t) {
+				in = true
+				break
+			}
+		}
+		if !in {
+			return false
+		}
+	}
+
+	return timeinterval.TimeInterval{
+		Times:       ti.Times,
+		Weekdays:    ti.Weekdays,
+		DaysOfMonth: ti.DaysOfMonth,
+		Months:      ti.Months,
+		Years:       ti.Years,
+	}.ContainsTime(t)
+}
+
+// Location is a time zone of the IANA Time Zone database, such as Europe/Berlin, in which a time interval is evaluated.
+//
+// swagger:type string
+type Location struct {
+	*time.Location
+}
+
+func loadLocation(name string) (*Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", name, err)
+	}
+	return &Location{loc}, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Location.
+func (l *Location) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err != nil {
+		return err
+	}
+	loc, err := loadLocation(name)
+	if err != nil {
+		return err
+	}
+	*l = *loc
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for Location.
+func (l Location) MarshalYAML() (interface{}, error) {
+	if l.Location == nil {
+		return nil, nil
+	}
+	return l.String(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Location.
+func (l *Location) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	loc, err := loadLocation(name)
+	if err != nil {
+		return err
+	}
+	*l = *loc
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for Location.
+func (l Location) MarshalJSON() ([]byte, error) {
+	if l.Location == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(l.String())
+}
+
+// DateRange is an inclusive range of dates in the format YYYY-MM-DD, such as 2022-12-24.
+type DateRange struct {
+	StartDate string `yaml:"start_date" json:"start_date"`
+	EndDate   string `yaml:"end_date" json:"end_date"`
+}
+
+// Validate checks that the dates of the range are valid and that the range does not end before it starts.
+func (dr DateRange) Validate() error {
+	start, err := time.Parse(dateRangeLayout, dr.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start date %q of date range, must be in the format YYYY-MM-DD", dr.StartDate)
+	}
+	end, err := time.Parse(dateRangeLayout, dr.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end date %q of date range, must be in the format YYYY-MM-DD", dr.EndDate)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("end date %q of date range is before its start date %q", dr.EndDate, dr.StartDate)
+	}
+	return nil
+}
+
+// contains returns true if the date of t, in the location of t, is within the range.
+func (dr DateRange) contains(t time.Time) bool {
+	// dates in the format YYYY-MM-DD are ordered the same way as strings and as dates
+	date := t.Format(dateRangeLayout)
+	return date >= dr.StartDate && date <= dr.EndDate
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for DateRange.
+func (dr *DateRange) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain DateRange
+	if err := unmarshal((*plain)(dr)); err != nil {
+		return err
+	}
+	return dr.Validate()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for DateRange.
+func (dr *DateRange) UnmarshalJSON(b []byte) error {
+	type plain DateRange
+	if err := json.Unmarshal(b, (*plain)(dr)); err != nil {
+		return err
+	}
+	return dr.Validate()
+}
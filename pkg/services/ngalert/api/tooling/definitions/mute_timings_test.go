@@ -0,0 +1,162 @@
+package definitions
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_TimeIntervalContainsTime(t *testing.T) {
+	const businessHoursInBerlin = `{
+		"times": [{"start_time": "09:00", "end_time": "17:00"}],
+		"weekdays": ["monday:friday"],
+		"location": "Europe/Berlin"
+	}`
+
+	for _, tc := range []struct {
+		desc     string
+		interval string
+		time     string
+		contains bool
+	}{
+		{
+			desc:     "times are evaluated in UTC without location",
+			interval: `{"times": [{"start_time": "09:00", "end_time": "17:00"}]}`,
+			time:     "2022-01-10T08:30:00Z",
+			contains: false,
+		},
+		{
+			desc:     "times are evaluated in the location",
+			interval: businessHoursInBerlin,
+			time:     "2022-01-10T08:30:00Z",
+			contains: true,
+		},
+		{
+			desc:     "times are evaluated with the daylight saving time of the location",
+			interval: businessHoursInBerlin,
+			time:     "2022-07-11T15:30:00Z",
+			contains: false,
+		},
+		{
+			desc:     "weekdays are evaluated in the location",
+			interval: `{"weekdays": ["saturday"], "location": "Asia/Tokyo"}`,
+			time:     "2022-01-14T20:00:00Z",
+			contains: true,
+		},
+		{
+			desc:     "date ranges are inclusive",
+			interval: `{"date_ranges": [{"start_date": "2022-12-24", "end_date": "2022-12-26"}]}`,
+			time:     "2022-12-26T23:59:00Z",
+			contains: true,
+		},
+		{
+			desc:     "date ranges are evaluated in the location",
+			interval: `{"date_ranges": [{"start_date": "2022-12-24", "end_date": "2022-12-26"}], "location": "America/New_York"}`,
+			time:     "2022-12-24T02:00:00Z",
+			contains: false,
+		},
+		{
+			desc:     "one of the date ranges must match",
+			interval: `{"date_ranges": [{"start_date": "2022-01-01", "end_date": "2022-01-01"}, {"start_date": "2022-12-25", "end_date": "2022-12-25"}]}`,
+			time:     "2022-12-25T12:00:00Z",
+			contains: true,
+		},
+		{
+			desc:     "date ranges and times must both match",
+			interval: `{"date_ranges": [{"start_date": "2022-12-25", "end_date": "2022-12-25"}], "times": [{"start_time": "00:00", "end_time": "06:00"}]}`,
+			time:     "2022-12-25T12:00:00Z",
+			contains: false,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var ti TimeInterval
+			require.NoError(t, json.Unmarshal([]byte(tc.interval), &ti))
+			now, err := time.Parse(time.RFC3339, tc.time)
+			require.NoError(t, err)
+			require.Equal(t, tc.contains, ti.ContainsTime(now))
+		})
+	}
+}
+
+func Test_TimeIntervalUnmarshaling(t *testing.T) {
+	for _, tc := range []struct {
+		desc  string
+		input string
+		err   string
+	}{
+		{
+			desc:  "valid location and date ranges",
+			input: `{"location": "Australia/Sydney", "date_ranges": [{"start_date": "2022-12-24", "end_date": "2023-01-01"}]}`,
+		},
+		{
+			desc:  "unknown location",
+			input: `{"location": "Mars/Olympus_Mons"}`,
+			err:   `invalid location "Mars/Olympus_Mons": unknown time zone Mars/Olympus_Mons`,
+		},
+		{
+			desc:  "invalid start date",
+			input: `{"date_ranges": [{"start_date": "24/12/2022", "end_date": "2022-12-26"}]}`,
+			err:   `invalid start date "24/12/2022" of date range, must be in the format YYYY-MM-DD`,
+		},
+		{
+			desc:  "missing end date",
+			input: `{"date_ranges": [{"start_date": "2022-12-24"}]}`,
+			err:   `invalid end date "" of date range, must be in the format YYYY-MM-DD`,
+		},
+		{
+			desc:  "end date before start date",
+			input: `{"date_ranges": [{"start_date": "2022-12-26", "end_date": "2022-12-24"}]}`,
+			err:   `end date "2022-12-24" of date range is before its start date "2022-12-26"`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var fromJSON TimeInterval
+			err := json.Unmarshal([]byte(tc.input), &fromJSON)
+			// JSON is valid YAML, so the same input checks the YAML unmarshaling.
+			var fromYAML TimeInterval
+			yamlErr := yaml.Unmarshal([]byte(tc.input), &fromYAML)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				require.EqualError(t, yamlErr, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, yamlErr)
+			require.Equal(t, fromJSON, fromYAML)
+		})
+	}
+}
+
+func Test_TimeIntervalRoundtrip(t *testing.T) {
+	input := `
+times:
+    - start_time: "17:00"
+      end_time: "24:00"
+weekdays: [monday:friday]
+location: Europe/Berlin
+date_ranges:
+    - start_date: "2022-12-24"
+      end_date: "2022-12-26"
+`
+	var ti TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte(input), &ti))
+	require.Equal(t, "Europe/Berlin", ti.Location.String())
+
+	out, err := yaml.Marshal(ti)
+	require.NoError(t, err)
+	var fromYAML TimeInterval
+	require.NoError(t, yaml.Unmarshal(out, &fromYAML))
+	require.Equal(t, ti, fromYAML)
+
+	b, err := json.Marshal(ti)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"times": [{"start_time": "17:00", "end_time": "24:00"}],
+		"weekdays": ["monday:friday"],
+		"location": "Europe/Berlin",
+		"date_ranges": [{"start_date": "2022-12-24", "end_date": "2022-12-26"}]
+	}`, string(b))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/promql"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -52,6 +53,22 @@ import (
 //     Responses:
 //       200: EvalQueriesResponse
 
+// swagger:route Post /api/v1/rule/backtest/grafana testing RouteBacktestGrafanaRule
+//
+// Replay the evaluations of a rule over a past time range and return the states of its alert instances.
+// Nothing is saved and no notification is sent.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestResult
+//       400: ValidationError
+//       403: PermissionDenied
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -84,6 +101,54 @@ type EvalQueriesPayload struct {
 	Now  time.Time           `json:"now"`
 }
 
+// swagger:parameters RouteBacktestGrafanaRule
+type BacktestRuleRequest struct {
+	// in:body
+	Body BacktestRulePayload
+}
+
+// swagger:model
+type BacktestRulePayload struct {
+	// Title is the value of the alertname label of the alert instances.
+	Title     string              `json:"title"`
+	Condition string              `json:"condition"`
+	Data      []models.AlertQuery `json:"data"`
+	// From is the time of the first evaluation of the rule.
+	From time.Time `json:"from"`
+	// To is the time after which the rule is no longer evaluated.
+	To              time.Time           `json:"to"`
+	IntervalSeconds int64               `json:"interval_seconds"`
+	For             model.Duration      `json:"for,omitempty"`
+	KeepFiringFor   model.Duration      `json:"keep_firing_for,omitempty"`
+	NoDataState     NoDataState         `json:"no_data_state,omitempty"`
+	ExecErrState    ExecutionErrorState `json:"exec_err_state,omitempty"`
+	Labels          map[string]string   `json:"labels,omitempty"`
+}
+
+// swagger:model
+type BacktestResult struct {
+	// Series contains the timeline of the states of each alert instance, sorted by labels.
+	Series []BacktestSeries `json:"series"`
+}
+
+type BacktestSeries struct {
+	Labels  map[string]string `json:"labels"`
+	Periods []BacktestPeriod  `json:"periods"`
+}
+
+// BacktestPeriod is a time range in which an alert instance stays in the same state.
+type BacktestPeriod struct {
+	// State is one of Normal, Pending, Alerting, NoData or Error.
+	State string `json:"state"`
+	// Resolved is true if the period starts with the resolution of a firing alert.
+	Resolved bool `json:"resolved,omitempty"`
+	// Start is the time of the evaluation at which the alert instance entered the state.
+	Start time.Time `json:"start"`
+	// End is the time of the evaluation at which the alert instance left the state or was no longer returned,
+	// or the time of the last evaluation.
+	End time.Time `json:"end"`
+}
+
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
 	type plain TestRulePayload
 	if err := json.Unmarshal(b, (*plain)(p)); err != nil {
@@ -0,0 +1,148 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	screenshotWidth  = 1000
+	screenshotHeight = 500
+
+	// imageExpiration is how long an image can be attached to notifications after it was taken.
+	imageExpiration = 24 * time.Hour
+)
+
+var (
+	// ErrNoDashboard is returned when the alert rule is not linked to a dashboard.
+	ErrNoDashboard = errors.New("no dashboard")
+	// ErrNoPanel is returned when the alert rule is not linked to a panel.
+	ErrNoPanel = errors.New("no panel")
+)
+
+// ImageService takes screenshots of the panels linked to alert rules.
+type ImageService interface {
+	// NewImage takes a screenshot of the panel of the alert rule and saves it.
+	// It returns ErrNoDashboard or ErrNoPanel if the alert rule is not linked to a panel.
+	NewImage(ctx context.Context, r *ngmodels.AlertRule) (*ngmodels.Image, error)
+}
+
+type dashboardStore interface {
+	GetDashboard(ctx context.Context, query *models.GetDashboardQuery) error
+}
+
+// ScreenshotImageService takes screenshots with the image renderer and saves them in the image store.
+// Screenshots are uploaded to the external image storage when an uploader is configured.
+type ScreenshotImageService struct {
+	cfg        setting.ScreenshotSettings
+	renderer   rendering.Service
+	uploader   imguploader.ImageUploader
+	store      store.ImageStore
+	dashboards dashboardStore
+	logger     log.Logger
+}
+
+// NewScreenshotImageService returns a new ScreenshotImageService.
+// The uploader is optional: without it images are only stored on disk.
+func NewScreenshotImageService(cfg setting.ScreenshotSettings, renderer rendering.Service, uploader imguploader.ImageUploader,
+	imageStore store.ImageStore, dashboards dashboardStore, logger log.Logger) *ScreenshotImageService {
+	return &ScreenshotImageService{
+		cfg:        cfg,
+		renderer:   renderer,
+		uploader:   uploader,
+		store:      imageStore,
+		dashboards: dashboards,
+		logger:     logger,
+	}
+}
+
+// NewScreenshotImageServiceFromCfg returns the ImageService configured in the [unified_alerting.screenshots] section,
+// or nil if screenshots are disabled.
+func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, renderer rendering.Service, imageStore store.ImageStore,
+	dashboards dashboardStore, logger log.Logger) (ImageService, error) {
+	if !cfg.UnifiedAlerting.Screenshots.Capture {
+		return nil, nil
+	}
+	var uploader imguploader.ImageUploader
+	if cfg.UnifiedAlerting.Screenshots.UploadExternalImageStorage {
+		u, err := imguploader.NewImageUploader()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the uploader of screenshots: %w", err)
+		}
+		uploader = u
+	}
+	return NewScreenshotImageService(cfg.UnifiedAlerting.Screenshots, renderer, uploader, imageStore, dashboards, logger), nil
+}
+
+func (s *ScreenshotImageService) NewImage(ctx context.Context, r *ngmodels.AlertRule) (*ngmodels.Image, error) {
+	if r.DashboardUID == nil || *r.DashboardUID == "" {
+		return nil, ErrNoDashboard
+	}
+	if r.PanelID == nil || *r.PanelID == 0 {
+		return nil, ErrNoPanel
+	}
+
+	// The screenshot is taken during the evaluation of the rule, so the whole screenshot, including the upload to
+	// the external image storage, must not take longer than the capture timeout.
+	if s.cfg.CaptureTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.CaptureTimeout)
+		defer cancel()
+	}
+
+	q := models.GetDashboardQuery{Uid: *r.DashboardUID, OrgId: r.OrgID}
+	if err := s.dashboards.GetDashboard(ctx, &q); err != nil {
+		return nil, fmt.Errorf("failed to get dashboard %s: %w", *r.DashboardUID, err)
+	}
+
+	opts := rendering.Opts{
+		TimeoutOpts: rendering.TimeoutOpts{
+			Timeout: s.cfg.CaptureTimeout,
+		},
+		AuthOpts: rendering.AuthOpts{
+			OrgID:   r.OrgID,
+			OrgRole: models.ROLE_ADMIN,
+		},
+		Width:           screenshotWidth,
+		Height:          screenshotHeight,
+		Path:            fmt.Sprintf("d-solo/%s/%s?orgId=%d&panelId=%d", q.Result.Uid, q.Result.Slug, r.OrgID, *r.PanelID),
+		ConcurrentLimit: int(s.cfg.MaxConcurrentScreenshots),
+		Theme:           models.ThemeDark,
+	}
+
+	start := time.Now()
+	result, err := s.renderer.Render(ctx, opts, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take screenshot: %w", err)
+	}
+	s.logger.Debug("took screenshot of panel", "rule_uid", r.UID, "path", result.FilePath, "took", time.Since(start))
+
+	image := ngmodels.Image{
+		Path:      result.FilePath,
+		CreatedAt: time.Now().UTC(),
+	}
+	image.ExpiresAt = image.CreatedAt.Add(imageExpiration)
+
+	if s.uploader != nil {
+		image.URL, err = s.uploader.Upload(ctx, result.FilePath)
+		if err != nil {
+			// the image can still be attached to notifications from the disk
+			s.logger.Warn("failed to upload screenshot to the external image storage", "rule_uid", r.UID, "error", err)
+		}
+	}
+
+	if err := s.store.SaveImage(ctx, &image); err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
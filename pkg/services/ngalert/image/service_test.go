@@ -0,0 +1,145 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeRenderer struct {
+	rendering.Service
+	opts rendering.Opts
+	err  error
+	// wait is set to render until the context is done
+	wait bool
+}
+
+func (f *fakeRenderer) Render(ctx context.Context, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+	f.opts = opts
+	if f.wait {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &rendering.RenderResult{FilePath: "/tmp/screenshot.png"}, nil
+}
+
+type fakeUploader struct {
+	err error
+}
+
+func (f *fakeUploader) Upload(_ context.Context, path string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return "https://www.example.com/screenshot.png", nil
+}
+
+type fakeImageStore struct {
+	images []*ngmodels.Image
+}
+
+func (f *fakeImageStore) GetImage(_ context.Context, _ string) (*ngmodels.Image, error) {
+	return nil, ngmodels.ErrImageNotFound
+}
+
+func (f *fakeImageStore) SaveImage(_ context.Context, image *ngmodels.Image) error {
+	image.Token = "token"
+	f.images = append(f.images, image)
+	return nil
+}
+
+func (f *fakeImageStore) DeleteExpiredImages(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
+type fakeDashboardStore struct{}
+
+func (f fakeDashboardStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	if query.Uid != "dashboard" {
+		return models.ErrDashboardNotFound
+	}
+	query.Result = &models.Dashboard{Uid: query.Uid, Slug: "my-dashboard"}
+	return nil
+}
+
+func TestScreenshotImageService(t *testing.T) {
+	cfg := setting.ScreenshotSettings{
+		Capture:                  true,
+		CaptureTimeout:           5 * time.Second,
+		MaxConcurrentScreenshots: 2,
+	}
+	dashboardUID := "dashboard"
+	panelID := int64(2)
+	rule := &ngmodels.AlertRule{UID: "rule", OrgID: 1, DashboardUID: &dashboardUID, PanelID: &panelID}
+
+	t.Run("takes a screenshot of the panel and saves it", func(t *testing.T) {
+		renderer := &fakeRenderer{}
+		store := &fakeImageStore{}
+		s := NewScreenshotImageService(cfg, renderer, nil, store, fakeDashboardStore{}, log.New("test"))
+
+		image, err := s.NewImage(context.Background(), rule)
+		require.NoError(t, err)
+		require.Equal(t, "token", image.Token)
+		require.Equal(t, "/tmp/screenshot.png", image.Path)
+		require.False(t, image.HasURL())
+		require.Equal(t, image.CreatedAt.Add(imageExpiration), image.ExpiresAt)
+		require.Len(t, store.images, 1)
+
+		require.Equal(t, "d-solo/dashboard/my-dashboard?orgId=1&panelId=2", renderer.opts.Path)
+		require.Equal(t, 5*time.Second, renderer.opts.Timeout)
+		require.Equal(t, 2, renderer.opts.ConcurrentLimit)
+		require.Equal(t, int64(1), renderer.opts.OrgID)
+	})
+
+	t.Run("uploads the screenshot", func(t *testing.T) {
+		s := NewScreenshotImageService(cfg, &fakeRenderer{}, &fakeUploader{}, &fakeImageStore{}, fakeDashboardStore{}, log.New("test"))
+		image, err := s.NewImage(context.Background(), rule)
+		require.NoError(t, err)
+		require.Equal(t, "https://www.example.com/screenshot.png", image.URL)
+	})
+
+	t.Run("saves the screenshot when it cannot be uploaded", func(t *testing.T) {
+		store := &fakeImageStore{}
+		s := NewScreenshotImageService(cfg, &fakeRenderer{}, &fakeUploader{err: errors.New("failed")}, store, fakeDashboardStore{}, log.New("test"))
+		image, err := s.NewImage(context.Background(), rule)
+		require.NoError(t, err)
+		require.False(t, image.HasURL())
+		require.Len(t, store.images, 1)
+	})
+
+	t.Run("returns an error when the rule is not linked to a panel", func(t *testing.T) {
+		s := NewScreenshotImageService(cfg, &fakeRenderer{}, nil, &fakeImageStore{}, fakeDashboardStore{}, log.New("test"))
+		_, err := s.NewImage(context.Background(), &ngmodels.AlertRule{UID: "rule", OrgID: 1})
+		require.ErrorIs(t, err, ErrNoDashboard)
+		_, err = s.NewImage(context.Background(), &ngmodels.AlertRule{UID: "rule", OrgID: 1, DashboardUID: &dashboardUID})
+		require.ErrorIs(t, err, ErrNoPanel)
+	})
+
+	t.Run("returns an error when the screenshot fails", func(t *testing.T) {
+		store := &fakeImageStore{}
+		s := NewScreenshotImageService(cfg, &fakeRenderer{err: errors.New("failed")}, nil, store, fakeDashboardStore{}, log.New("test"))
+		_, err := s.NewImage(context.Background(), rule)
+		require.Error(t, err)
+		require.Empty(t, store.images)
+	})
+
+	t.Run("returns an error when the screenshot takes longer than the capture timeout", func(t *testing.T) {
+		cfg := cfg
+		cfg.CaptureTimeout = 10 * time.Millisecond
+		s := NewScreenshotImageService(cfg, &fakeRenderer{wait: true}, nil, &fakeImageStore{}, fakeDashboardStore{}, log.New("test"))
+		_, err := s.NewImage(context.Background(), rule)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
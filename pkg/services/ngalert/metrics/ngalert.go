@@ -42,6 +42,7 @@ type NGAlert struct {
 	stateMetrics                *State
 	multiOrgAlertmanagerMetrics *MultiOrgAlertmanager
 	apiMetrics                  *API
+	remoteWriterMetrics         *RemoteWriter
 }
 
 type Scheduler struct {
@@ -52,6 +53,7 @@ type Scheduler struct {
 	EvalDuration             *prometheus.SummaryVec
 	GetAlertRulesDuration    prometheus.Histogram
 	SchedulePeriodicDuration prometheus.Histogram
+	SchedulableRules         prometheus.Gauge
 }
 
 type MultiOrgAlertmanager struct {
@@ -65,6 +67,11 @@ type API struct {
 	RequestDuration *prometheus.HistogramVec
 }
 
+type RemoteWriter struct {
+	WritesTotal   *prometheus.CounterVec
+	WriteDuration *prometheus.HistogramVec
+}
+
 type Alertmanager struct {
 	Registerer prometheus.Registerer
 	*metrics.Alerts
@@ -91,6 +98,10 @@ func (ng *NGAlert) GetMultiOrgAlertmanagerMetrics() *MultiOrgAlertmanager {
 	return ng.multiOrgAlertmanagerMetrics
 }
 
+func (ng *NGAlert) GetRemoteWriterMetrics() *RemoteWriter {
+	return ng.remoteWriterMetrics
+}
+
 // NewNGAlert manages the metrics of all the alerting components.
 func NewNGAlert(r prometheus.Registerer) *NGAlert {
 	return &NGAlert{
@@ -99,6 +110,7 @@ func NewNGAlert(r prometheus.Registerer) *NGAlert {
 		stateMetrics:                newStateMetrics(r),
 		multiOrgAlertmanagerMetrics: newMultiOrgAlertmanagerMetrics(r),
 		apiMetrics:                  newAPIMetrics(r),
+		remoteWriterMetrics:         newRemoteWriterMetrics(r),
 	}
 }
 
@@ -179,6 +191,14 @@ func newSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 				Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 5, 10},
 			},
 		),
+		SchedulableRules: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_alert_rules",
+				Help:      "The number of alert rules evaluated by this Grafana instance.",
+			},
+		),
 	}
 }
 
@@ -238,6 +258,30 @@ func newAPIMetrics(r prometheus.Registerer) *API {
 	}
 }
 
+func newRemoteWriterMetrics(r prometheus.Registerer) *RemoteWriter {
+	return &RemoteWriter{
+		WritesTotal: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "remote_writer_writes_total",
+				Help:      "The total number of remote writes attempted by recording rules.",
+			},
+			[]string{"org", "status_code"},
+		),
+		WriteDuration: promauto.With(r).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "remote_writer_write_duration_seconds",
+				Help:      "Histogram of remote write durations of recording rules.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"org"},
+		),
+	}
+}
+
 // OrgRegistries represents a map of registries per org.
 type OrgRegistries struct {
 	regsMu sync.Mutex
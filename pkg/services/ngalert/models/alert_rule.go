@@ -31,6 +31,8 @@ const (
 	Alerting NoDataState = "Alerting"
 	NoData   NoDataState = "NoData"
 	OK       NoDataState = "OK"
+	// KeepLast keeps the instances in the state they were in before the query returned no data.
+	KeepLast NoDataState = "KeepLast"
 )
 
 type ExecutionErrorState string
@@ -42,6 +44,8 @@ func (executionErrorState ExecutionErrorState) String() string {
 const (
 	AlertingErrState ExecutionErrorState = "Alerting"
 	ErrorErrState    ExecutionErrorState = "Error"
+	// KeepLastErrState keeps the instances in the state they were in before the evaluation failed.
+	KeepLastErrState ExecutionErrorState = "KeepLast"
 )
 
 const (
@@ -51,6 +55,11 @@ const (
 	// Annotations are actually a set of labels, so technically this is the label name of an annotation.
 	DashboardUIDAnnotation = "__dashboardUid__"
 	PanelIDAnnotation      = "__panelId__"
+
+	// ImageTokenAnnotation is the token of the screenshot of the panel taken when the state of the alert changed.
+	ImageTokenAnnotation = "__alertImageToken__"
+	// ImageURLAnnotation is the URL of the screenshot if it was uploaded to an external image storage.
+	ImageURLAnnotation = "__alertImageURL__"
 )
 
 // AlertRule is the model for alert rules in unified alerting.
@@ -68,13 +77,33 @@ type AlertRule struct {
 	DashboardUID    *string `xorm:"dashboard_uid"`
 	PanelID         *int64  `xorm:"panel_id"`
 	RuleGroup       string
+	RuleGroupIndex  int
 	NoDataState     NoDataState
 	ExecErrState    ExecutionErrorState
-	// ideally this field should have been apimodels.ApiDuration
+	// ideally these fields should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For           time.Duration
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	// Record is set for recording rules. A recording rule writes the result
+	// of its query or expression as a new series instead of firing alerts.
+	Record *Record `xorm:"json"`
+	// IsPaused is set for rules that are not evaluated. Paused rules keep their configuration.
+	IsPaused bool
+}
+
+// Record contains the configuration of a recording rule.
+type Record struct {
+	// Metric is the name of the metric the results are written to.
+	Metric string `json:"metric" yaml:"metric"`
+	// From is the RefID of the query or expression whose results are written.
+	From string `json:"from" yaml:"from"`
+}
+
+// IsRecordingRule returns true if the rule writes its results as series instead of alerting.
+func (alertRule *AlertRule) IsRecordingRule() bool {
+	return alertRule.Record != nil
 }
 
 // AlertRuleKey is the alert definition identifier
@@ -92,6 +121,22 @@ func (alertRule *AlertRule) GetKey() AlertRuleKey {
 	return AlertRuleKey{OrgID: alertRule.OrgID, UID: alertRule.UID}
 }
 
+// AlertRuleGroupKey is the identifier of the group of an alert rule.
+type AlertRuleGroupKey struct {
+	OrgID        int64
+	NamespaceUID string
+	RuleGroup    string
+}
+
+func (k AlertRuleGroupKey) String() string {
+	return fmt.Sprintf("{orgID: %d, namespaceUID: %s, groupName: %s}", k.OrgID, k.NamespaceUID, k.RuleGroup)
+}
+
+// GetGroupKey returns the identifier of the group of the alert rule.
+func (alertRule *AlertRule) GetGroupKey() AlertRuleGroupKey {
+	return AlertRuleGroupKey{OrgID: alertRule.OrgID, NamespaceUID: alertRule.NamespaceUID, RuleGroup: alertRule.RuleGroup}
+}
+
 // PreSave sets default values and loads the updated model for each alert query.
 func (alertRule *AlertRule) PreSave(timeNow func() time.Time) error {
 	for i, q := range alertRule.Data {
@@ -124,6 +169,7 @@ type AlertRuleVersion struct {
 	RuleUID          string `xorm:"rule_uid"`
 	RuleNamespaceUID string `xorm:"rule_namespace_uid"`
 	RuleGroup        string
+	RuleGroupIndex   int
 	ParentVersion    int64
 	RestoredFrom     int64
 	Version          int64
@@ -135,11 +181,14 @@ type AlertRuleVersion struct {
 	IntervalSeconds int64
 	NoDataState     NoDataState
 	ExecErrState    ExecutionErrorState
-	// ideally this field should have been apimodels.ApiDuration
+	// ideally these fields should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For           time.Duration
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	Record        *Record `xorm:"json"`
+	IsPaused      bool
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
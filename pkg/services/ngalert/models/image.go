@@ -0,0 +1,30 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrImageNotFound = errors.New("image not found")
+
+// Image is a screenshot of the panel of an alert rule, taken when the state of the rule changes.
+// Notifications reference it by its token.
+type Image struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	Token string `xorm:"token"`
+	// Path is the path of the image on the disk of the Grafana instance that took it.
+	Path string `xorm:"path"`
+	// URL is the public URL of the image if it was uploaded to an external image storage.
+	URL       string    `xorm:"url"`
+	CreatedAt time.Time `xorm:"created_at"`
+	ExpiresAt time.Time `xorm:"expires_at"`
+}
+
+func (i Image) TableName() string {
+	return "alert_image"
+}
+
+// HasURL returns true if the image can be linked to in notifications.
+func (i *Image) HasURL() bool {
+	return i.URL != ""
+}
@@ -14,3 +14,33 @@ type Provisionable interface {
 	ResourceID() string
 	ResourceOrgID() int64
 }
+
+const (
+	ResourceTypeContactPoint       = "contactPoint"
+	ResourceTypeNotificationPolicy = "notificationPolicy"
+	ResourceTypeMuteTiming         = "muteTiming"
+	ResourceTypeTemplate           = "template"
+)
+
+// NotificationPolicyResourceID is the resource ID of the notification policy tree. There is a single tree per organization.
+const NotificationPolicyResourceID = "policies"
+
+// ProvisionableResource is a Provisionable for resources that are not stored in their own table,
+// such as the contact points, notification policies, mute timings and templates of the Alertmanager configuration.
+type ProvisionableResource struct {
+	Type  string
+	ID    string
+	OrgID int64
+}
+
+func (r ProvisionableResource) ResourceType() string {
+	return r.Type
+}
+
+func (r ProvisionableResource) ResourceID() string {
+	return r.ID
+}
+
+func (r ProvisionableResource) ResourceOrgID() int64 {
+	return r.OrgID
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+// AlertStateHistoryEntry is a change of the state of an alert instance.
+type AlertStateHistoryEntry struct {
+	ID      int64
+	OrgID   int64
+	RuleUID string
+	// Labels are the labels of the alert instance, including the labels of the rule.
+	Labels        InstanceLabels
+	PreviousState InstanceStateType
+	State         InstanceStateType
+	// Values contains the RefID and value of reduce and math expressions of the evaluation
+	// that caused the change.
+	Values map[string]*float64
+	// Error is the error of the evaluation if the new state is Error.
+	Error     string
+	Timestamp time.Time
+}
+
+// ListAlertStateHistoryQuery is the query for listing the state history of the alert instances of a rule.
+type ListAlertStateHistoryQuery struct {
+	OrgID   int64
+	RuleUID string
+	// Matchers filters the history by the labels of the alert instances.
+	Matchers labels.Matchers
+	From     time.Time
+	To       time.Time
+	// Limit is the maximum number of entries to return, the most recent entries are returned first.
+	Limit int
+
+	Result []*AlertStateHistoryEntry
+}
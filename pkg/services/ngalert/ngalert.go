@@ -3,6 +3,7 @@ package ngalert
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"golang.org/x/sync/errgroup"
@@ -11,17 +12,21 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
@@ -29,7 +34,8 @@ import (
 
 func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService, routeRegister routing.RouteRegister,
 	sqlStore *sqlstore.SQLStore, kvStore kvstore.KVStore, expressionService *expr.Service, dataProxy *datasourceproxy.DataSourceProxyService,
-	quotaService *quota.QuotaService, secretsService secrets.Service, notificationService notifications.Service, m *metrics.NGAlert) (*AlertNG, error) {
+	quotaService *quota.QuotaService, secretsService secrets.Service, notificationService notifications.Service, renderService rendering.Service, serverLockService *serverlock.ServerLockService,
+	m *metrics.NGAlert) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                 cfg,
 		DataSourceCache:     dataSourceCache,
@@ -42,6 +48,8 @@ func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService,
 		SecretsService:      secretsService,
 		Metrics:             m,
 		NotificationService: notificationService,
+		RenderService:       renderService,
+		ServerLockService:   serverLockService,
 		Log:                 log.New("ngalert"),
 	}
 
@@ -69,9 +77,13 @@ type AlertNG struct {
 	SecretsService      secrets.Service
 	Metrics             *metrics.NGAlert
 	NotificationService notifications.Service
+	RenderService       rendering.Service
+	ServerLockService   *serverlock.ServerLockService
 	Log                 log.Logger
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
+	historyStore        store.StateHistoryStore
+	imageStore          store.ImageStore
 
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
@@ -79,6 +91,7 @@ type AlertNG struct {
 
 func (ng *AlertNG) init() error {
 	var err error
+	var historyStore store.StateHistoryStore
 
 	store := &store.DBstore{
 		BaseInterval:    ng.Cfg.UnifiedAlerting.BaseInterval,
@@ -99,6 +112,11 @@ func (ng *AlertNG) init() error {
 		return err
 	}
 
+	var recordingWriter writer.Writer = writer.NoopWriter{}
+	if ng.Cfg.UnifiedAlerting.RecordingRules.Enabled {
+		recordingWriter = writer.NewPrometheusWriter(ng.Cfg.UnifiedAlerting.RecordingRules, ng.Metrics.GetRemoteWriterMetrics(), log.New("ngalert.writer"))
+	}
+
 	schedCfg := schedule.SchedulerCfg{
 		C:                       clock.New(),
 		BaseInterval:            ng.Cfg.UnifiedAlerting.BaseInterval,
@@ -114,6 +132,10 @@ func (ng *AlertNG) init() error {
 		AdminConfigPollInterval: ng.Cfg.UnifiedAlerting.AdminConfigPollInterval,
 		DisabledOrgs:            ng.Cfg.UnifiedAlerting.DisabledOrgs,
 		MinRuleInterval:         ng.Cfg.UnifiedAlerting.MinInterval,
+		RecordingWriter:         recordingWriter,
+	}
+	if ng.Cfg.UnifiedAlerting.HAEvaluationSharding && len(ng.Cfg.UnifiedAlerting.HAPeers) > 0 {
+		schedCfg.ClusterMembership = ng.MultiOrgAlertmanager
 	}
 
 	appUrl, err := url.Parse(ng.Cfg.AppURL)
@@ -121,11 +143,22 @@ func (ng *AlertNG) init() error {
 		ng.Log.Error("Failed to parse application URL. Continue without it.", "error", err)
 		appUrl = nil
 	}
-	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.SQLStore)
+	if ng.Cfg.UnifiedAlerting.StateHistory.Enabled {
+		historyStore = store
+	}
+	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, ng.RenderService, store, ng.SQLStore, log.New("ngalert.image"))
+	if err != nil {
+		return err
+	}
+	if imageService != nil {
+		ng.imageStore = store
+	}
+	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, historyStore, imageService, ng.SQLStore)
 	scheduler := schedule.NewScheduler(schedCfg, ng.ExpressionService, appUrl, stateManager)
 
 	ng.stateManager = stateManager
 	ng.schedule = scheduler
+	ng.historyStore = historyStore
 
 	api := api.API{
 		Cfg:                  ng.Cfg,
@@ -140,8 +173,10 @@ func (ng *AlertNG) init() error {
 		RuleStore:            store,
 		AlertingStore:        store,
 		AdminConfigStore:     store,
+		ProvenanceStore:      store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
+		StateHistoryStore:    store,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -163,9 +198,65 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.MultiOrgAlertmanager.Run(subCtx)
 	})
+	if ng.historyStore != nil && ng.Cfg.UnifiedAlerting.StateHistory.Retention > 0 {
+		children.Go(func() error {
+			ng.cleanUpStateHistory(subCtx)
+			return nil
+		})
+	}
+	if ng.imageStore != nil {
+		children.Go(func() error {
+			ng.cleanUpExpiredImages(subCtx)
+			return nil
+		})
+	}
 	return children.Wait()
 }
 
+// cleanUpStateHistory periodically deletes the state history that is older than the retention.
+func (ng *AlertNG) cleanUpStateHistory(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := ng.ServerLockService.LockAndExecute(ctx, "delete old alert state history", 10*time.Minute, func(ctx context.Context) {
+				before := time.Now().Add(-ng.Cfg.UnifiedAlerting.StateHistory.Retention)
+				affected, err := ng.historyStore.DeleteAlertStateHistoryBefore(ctx, before)
+				if err != nil {
+					ng.Log.Error("failed to delete old alert state history", "error", err)
+					return
+				}
+				ng.Log.Debug("deleted old alert state history", "rows affected", affected)
+			})
+			if err != nil {
+				ng.Log.Error("failed to lock and execute cleanup of old alert state history", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanUpExpiredImages periodically deletes the screenshots that can no longer be attached to notifications.
+func (ng *AlertNG) cleanUpExpiredImages(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := ng.imageStore.DeleteExpiredImages(ctx)
+			if err != nil {
+				ng.Log.Error("failed to delete expired images", "error", err)
+				continue
+			}
+			ng.Log.Debug("deleted expired images", "rows affected", deleted)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // IsDisabled returns true if the alerting service is disable for this instance.
 func (ng *AlertNG) IsDisabled() bool {
 	if ng.Cfg == nil {
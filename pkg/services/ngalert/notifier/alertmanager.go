@@ -17,7 +17,6 @@ import (
 
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/cluster"
-	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/dispatch"
 	"github.com/prometheus/alertmanager/inhibit"
 	"github.com/prometheus/alertmanager/nflog"
@@ -26,7 +25,6 @@ import (
 	"github.com/prometheus/alertmanager/provider/mem"
 	"github.com/prometheus/alertmanager/silence"
 	"github.com/prometheus/alertmanager/template"
-	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -113,7 +111,10 @@ type Alertmanager struct {
 
 	// muteTimes is a map where the key is the name of the mute_time_interval
 	// and the value represents all configured time_interval(s)
-	muteTimes map[string][]timeinterval.TimeInterval
+	muteTimes map[string][]apimodels.TimeInterval
+
+	// deliveryLog keeps the last notification attempts of each integration.
+	deliveryLog *deliveryLog
 
 	stageMetrics      *notify.Metrics
 	dispatcherMetrics *dispatch.DispatcherMetrics
@@ -142,6 +143,7 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store s
 		NotificationService: ns,
 		orgID:               orgID,
 		decryptFn:           decryptFn,
+		deliveryLog:         newDeliveryLog(),
 	}
 
 	am.fileStore = NewFileStore(am.orgID, kvStore, am.WorkingDirPath())
@@ -334,8 +336,8 @@ func (am *Alertmanager) templateFromPaths(paths ...string) (*template.Template,
 	return tmpl, nil
 }
 
-func (am *Alertmanager) buildMuteTimesMap(muteTimeIntervals []config.MuteTimeInterval) map[string][]timeinterval.TimeInterval {
-	muteTimes := make(map[string][]timeinterval.TimeInterval, len(muteTimeIntervals))
+func (am *Alertmanager) buildMuteTimesMap(muteTimeIntervals []apimodels.MuteTimeInterval) map[string][]apimodels.TimeInterval {
+	muteTimes := make(map[string][]apimodels.TimeInterval, len(muteTimeIntervals))
 	for _, ti := range muteTimeIntervals {
 		muteTimes[ti.Name] = ti.TimeIntervals
 	}
@@ -405,7 +407,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 
 	meshStage := notify.NewGossipSettleStage(am.peer)
 	inhibitionStage := notify.NewMuteStage(am.inhibitor)
-	timeMuteStage := notify.NewTimeMuteStage(am.muteTimes)
+	timeMuteStage := newTimeMuteStage(am.muteTimes)
 	silencingStage := notify.NewMuteStage(am.silencer)
 	for name := range integrationsMap {
 		stage := am.createReceiverStage(name, integrationsMap[name], am.waitFunc, am.notificationLog)
@@ -429,6 +431,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 
 	am.config = cfg
 	am.configHash = md5.Sum(rawConfig)
+	am.deliveryLog.retain(deliveryLogKeys(cfg.AlertmanagerConfig.Receivers))
 
 	return nil
 }
@@ -464,7 +467,8 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
-		integrations = append(integrations, notify.NewIntegration(n, n, r.Type, i))
+		rn := &recordingNotifier{NotificationChannel: n, key: deliveryLogKey(receiver.Name, r, i), log: am.deliveryLog}
+		integrations = append(integrations, notify.NewIntegration(rn, n, r.Type, i))
 	}
 	return integrations, nil
 }
@@ -499,7 +503,7 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 	)
 	switch r.Type {
 	case "email":
-		n, err = channels.NewEmailNotifier(cfg, am.NotificationService, tmpl) // Email notifier already has a default template.
+		n, err = channels.NewEmailNotifier(cfg, am.NotificationService, am.Store, tmpl) // Email notifier already has a default template.
 	case "pagerduty":
 		n, err = channels.NewPagerdutyNotifier(cfg, am.NotificationService, tmpl, am.decryptFn)
 	case "pushover":
@@ -507,7 +511,7 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 	case "slack":
 		n, err = channels.NewSlackNotifier(cfg, tmpl, am.decryptFn)
 	case "telegram":
-		n, err = channels.NewTelegramNotifier(cfg, am.NotificationService, tmpl, am.decryptFn)
+		n, err = channels.NewTelegramNotifier(cfg, am.NotificationService, am.Store, tmpl, am.decryptFn)
 	case "victorops":
 		n, err = channels.NewVictoropsNotifier(cfg, am.NotificationService, tmpl)
 	case "teams":
@@ -518,12 +522,14 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 		n, err = channels.NewKafkaNotifier(cfg, am.NotificationService, tmpl)
 	case "webhook":
 		n, err = channels.NewWebHookNotifier(cfg, am.NotificationService, tmpl, am.decryptFn)
+	case "http":
+		n, err = channels.NewHTTPNotifier(cfg, am.NotificationService, tmpl, am.decryptFn)
 	case "wecom":
 		n, err = channels.NewWeComNotifier(cfg, am.NotificationService, tmpl, am.decryptFn)
 	case "sensugo":
 		n, err = channels.NewSensuGoNotifier(cfg, am.NotificationService, tmpl, am.decryptFn)
 	case "discord":
-		n, err = channels.NewDiscordNotifier(cfg, am.NotificationService, tmpl)
+		n, err = channels.NewDiscordNotifier(cfg, am.NotificationService, am.Store, tmpl)
 	case "googlechat":
 		n, err = channels.NewGoogleChatNotifier(cfg, am.NotificationService, tmpl)
 	case "LINE":
@@ -534,6 +540,8 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 		n, err = channels.NewOpsgenieNotifier(cfg, am.NotificationService, tmpl, am.decryptFn)
 	case "prometheus-alertmanager":
 		n, err = channels.NewAlertmanagerNotifier(cfg, tmpl, am.decryptFn)
+	case "mqtt":
+		n, err = channels.NewMQTTNotifier(cfg, tmpl, am.decryptFn)
 	default:
 		return nil, InvalidReceiverError{
 			Receiver: r,
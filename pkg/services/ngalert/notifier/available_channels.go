@@ -165,6 +165,125 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Publishes notifications to an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []alerting.NotifierOption{
+				{
+					Label:        "Broker URL",
+					Description:  "The scheme is tcp, ssl, ws or wss.",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "brokerUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Description:  "You can use template variables.",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:        "Client ID",
+					Description:  "The ID of the client. Defaults to grafana_ followed by the UID of the contact point.",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "clientId",
+				},
+				{
+					Label:   "Message format",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: channels.MQTTMessageFormatJSON,
+							Label: "JSON",
+						},
+						{
+							Value: channels.MQTTMessageFormatText,
+							Label: "Text",
+						},
+					},
+					Description:  "JSON publishes the alerts along with the message, text publishes the message only.",
+					PropertyName: "messageFormat",
+				},
+				{
+					Label:        "Message",
+					Description:  "You can use template variables.",
+					Element:      alerting.ElementTypeTextArea,
+					Placeholder:  `{{ template "default.message" . }}`,
+					PropertyName: "message",
+				},
+				{
+					Label:   "QoS",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+					PropertyName: "qos",
+				},
+				{
+					Label:        "Retain",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "The broker keeps the last message of the topic for new subscribers.",
+					PropertyName: "retain",
+				},
+				{
+					Label:        "Username",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Disable certificate verification",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "Do not verify the certificate of the broker. Not recommended.",
+					PropertyName: "insecureSkipVerify",
+				},
+				{
+					Label:        "CA certificate",
+					Description:  "PEM encoded certificate of the authority that signed the certificate of the broker.",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "tlsCACertificate",
+				},
+				{
+					Label:        "Client certificate",
+					Description:  "PEM encoded certificate used to authenticate to the broker.",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "Client key",
+					Description:  "PEM encoded key of the client certificate.",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+			},
+		},
 		{
 			Type:        "email",
 			Name:        "Email",
@@ -637,6 +756,87 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "http",
+			Name:        "HTTP",
+			Description: "Sends an HTTP request with a templated body to a URL",
+			Heading:     "HTTP settings",
+			Options: []alerting.NotifierOption{
+				{
+					Label:        "URL",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Templated URL of the request",
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:   "HTTP Method",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: "POST",
+							Label: "POST",
+						},
+						{
+							Value: "PUT",
+							Label: "PUT",
+						},
+						{
+							Value: "PATCH",
+							Label: "PATCH",
+						},
+					},
+					PropertyName: "httpMethod",
+				},
+				{
+					Label:        "Content Type",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Content type of the body. JSON bodies are validated before they are sent.",
+					Placeholder:  "application/json",
+					PropertyName: "contentType",
+				},
+				{
+					Label:        "Headers",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Templated headers of the request, one per line in the format \"Name: value\"",
+					Placeholder:  "X-Source: grafana",
+					PropertyName: "headers",
+					Secure:       true,
+				},
+				{
+					Label:        "Body",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Templated body of the request",
+					Placeholder:  `{"summary": "{{ .CommonLabels.alertname }} is {{ .Status }}"}`,
+					PropertyName: "body",
+					Required:     true,
+				},
+				{
+					Label:        "Username",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Username for the HTTP basic authentication",
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					Description:  "Password for the HTTP basic authentication",
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Max Alerts",
+					Description:  "Max alerts to include in a notification. Remaining alerts in the same batch will be ignored above this number. 0 means no limit.",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "maxAlerts",
+				},
+			},
+		},
 		{
 			Type:        "wecom",
 			Name:        "WeCom",
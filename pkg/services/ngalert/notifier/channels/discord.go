@@ -1,8 +1,13 @@
 package channels
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -12,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -20,6 +26,7 @@ type DiscordNotifier struct {
 	*Base
 	log                log.Logger
 	ns                 notifications.WebhookSender
+	images             ImageStore
 	tmpl               *template.Template
 	Content            string
 	AvatarURL          string
@@ -27,7 +34,7 @@ type DiscordNotifier struct {
 	UseDiscordUsername bool
 }
 
-func NewDiscordNotifier(model *NotificationChannelConfig, ns notifications.WebhookSender, t *template.Template) (*DiscordNotifier, error) {
+func NewDiscordNotifier(model *NotificationChannelConfig, ns notifications.WebhookSender, images ImageStore, t *template.Template) (*DiscordNotifier, error) {
 	if model.Settings == nil {
 		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
 	}
@@ -57,6 +64,7 @@ func NewDiscordNotifier(model *NotificationChannelConfig, ns notifications.Webho
 		WebhookURL:         discordURL,
 		log:                log.New("alerting.notifier.discord"),
 		ns:                 ns,
+		images:             images,
 		tmpl:               t,
 		UseDiscordUsername: useDiscordUsername,
 	}, nil
@@ -98,6 +106,21 @@ func (d DiscordNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 	ruleURL := joinUrlPath(d.tmpl.ExternalURL.String(), "/alerting/list", d.log)
 	embed.Set("url", ruleURL)
 
+	// an embed shows one image, so the screenshot of the first alert is used
+	var attachment *ngmodels.Image
+	_ = withStoredImages(ctx, d.log, d.images, func(_ int, image ngmodels.Image) error {
+		if image.HasURL() {
+			embed.Set("image", map[string]interface{}{"url": image.URL})
+			return errStopImages
+		}
+		if hasLocalFile(image) {
+			attachment = &image
+			embed.Set("image", map[string]interface{}{"url": "attachment://" + filepath.Base(image.Path)})
+			return errStopImages
+		}
+		return nil
+	}, as...)
+
 	bodyJSON.Set("embeds", []interface{}{embed})
 
 	u := tmpl(d.WebhookURL)
@@ -116,6 +139,12 @@ func (d DiscordNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 		ContentType: "application/json",
 		Body:        string(body),
 	}
+	if attachment != nil {
+		// the screenshot is uploaded with the message, which is then sent as multipart form data
+		if err := d.withAttachment(cmd, body, *attachment); err != nil {
+			d.log.Warn("failed to attach image to Discord message", "err", err)
+		}
+	}
 
 	if err := d.ns.SendWebhookSync(ctx, cmd); err != nil {
 		d.log.Error("Failed to send notification to Discord", "error", err)
@@ -124,6 +153,46 @@ func (d DiscordNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 	return true, nil
 }
 
+// withAttachment changes the request to upload the image along with the message.
+// The request is not changed if the image cannot be read.
+func (d DiscordNotifier) withAttachment(cmd *models.SendWebhookSync, payload []byte, image ngmodels.Image) error {
+	f, err := os.Open(image.Path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			d.log.Warn("failed to close image", "err", err)
+		}
+	}()
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	boundary := GetBoundary()
+	if boundary != "" {
+		if err := w.SetBoundary(boundary); err != nil {
+			return err
+		}
+	}
+	if err := writeField(w, "payload_json", string(payload)); err != nil {
+		return err
+	}
+	fw, err := w.CreateFormFile("file", filepath.Base(image.Path))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fw, f); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	cmd.ContentType = w.FormDataContentType()
+	cmd.Body = b.String()
+	return nil
+}
+
 func (d DiscordNotifier) SendResolved() bool {
 	return !d.GetDisableResolveMessage()
 }
@@ -58,6 +58,34 @@ func TestDiscordNotifier(t *testing.T) {
 			},
 			expMsgError: nil,
 		},
+		{
+			name:     "Default config with the screenshot of the alert",
+			settings: `{"url": "http://localhost", "message": "{{ len .Alerts.Firing }} alerts are firing"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"__alertImageToken__": "abc", "__alertImageURL__": "https://www.example.com/image.png"},
+					},
+				},
+			},
+			expMsg: map[string]interface{}{
+				"content": "1 alerts are firing",
+				"embeds": []interface{}{map[string]interface{}{
+					"color": 1.4037554e+07,
+					"footer": map[string]interface{}{
+						"icon_url": "https://grafana.com/assets/img/fav32.png",
+						"text":     "Grafana v" + setting.BuildVersion,
+					},
+					"image": map[string]interface{}{"url": "https://www.example.com/image.png"},
+					"title": "[FIRING:1]  (val1)",
+					"url":   "http://localhost/alerting/list",
+					"type":  "rich",
+				}},
+				"username": "Grafana",
+			},
+			expMsgError: nil,
+		},
 		{
 			name: "Custom config with multiple alerts",
 			settings: `{
@@ -151,7 +179,7 @@ func TestDiscordNotifier(t *testing.T) {
 			}
 
 			webhookSender := mockNotificationService()
-			dn, err := NewDiscordNotifier(m, webhookSender, tmpl)
+			dn, err := NewDiscordNotifier(m, webhookSender, nil, tmpl)
 			if c.expInitError != "" {
 				require.Equal(t, c.expInitError, err.Error())
 				return
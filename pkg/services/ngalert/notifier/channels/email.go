@@ -4,12 +4,14 @@ import (
 	"context"
 	"net/url"
 	"path"
+	"path/filepath"
 
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -23,12 +25,13 @@ type EmailNotifier struct {
 	Message     string
 	log         log.Logger
 	ns          notifications.EmailSender
+	images      ImageStore
 	tmpl        *template.Template
 }
 
 // NewEmailNotifier is the constructor function
-// for the EmailNotifier.
-func NewEmailNotifier(model *NotificationChannelConfig, ns notifications.EmailSender, t *template.Template) (*EmailNotifier, error) {
+// for the EmailNotifier. If images is nil, only the screenshots with a URL are shown in emails.
+func NewEmailNotifier(model *NotificationChannelConfig, ns notifications.EmailSender, images ImageStore, t *template.Template) (*EmailNotifier, error) {
 	if model.Settings == nil {
 		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
 	}
@@ -56,6 +59,7 @@ func NewEmailNotifier(model *NotificationChannelConfig, ns notifications.EmailSe
 		Message:     model.Settings.Get("message").MustString(),
 		log:         log.New("alerting.notifier.email"),
 		ns:          ns,
+		images:      images,
 		tmpl:        t,
 	}, nil
 }
@@ -80,6 +84,25 @@ func (en *EmailNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 		en.log.Debug("failed to parse external URL", "url", en.tmpl.ExternalURL.String(), "err", err.Error())
 	}
 
+	// screenshots without a URL are embedded in the email, each file once
+	var embeddedFiles []string
+	embedded := map[string]struct{}{}
+	_ = withStoredImages(ctx, en.log, en.images, func(index int, image ngmodels.Image) error {
+		if image.HasURL() {
+			data.Alerts[index].ImageURL = image.URL
+			return nil
+		}
+		if !hasLocalFile(image) {
+			return nil
+		}
+		data.Alerts[index].EmbeddedImage = filepath.Base(image.Path)
+		if _, ok := embedded[image.Path]; !ok {
+			embedded[image.Path] = struct{}{}
+			embeddedFiles = append(embeddedFiles, image.Path)
+		}
+		return nil
+	}, as...)
+
 	cmd := &models.SendEmailCommandSync{
 		SendEmailCommand: models.SendEmailCommand{
 			Subject: title,
@@ -95,9 +118,10 @@ func (en *EmailNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 				"RuleUrl":           ruleURL,
 				"AlertPageUrl":      alertPageURL,
 			},
-			To:          en.Addresses,
-			SingleEmail: en.SingleEmail,
-			Template:    "ng_alert_notification",
+			EmbeddedFiles: embeddedFiles,
+			To:            en.Addresses,
+			SingleEmail:   en.SingleEmail,
+			Template:      "ng_alert_notification",
 		},
 	}
 
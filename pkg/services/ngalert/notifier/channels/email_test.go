@@ -3,6 +3,8 @@ package channels
 import (
 	"context"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/prometheus/alertmanager/template"
@@ -11,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 func TestEmailNotifier(t *testing.T) {
@@ -30,7 +33,7 @@ func TestEmailNotifier(t *testing.T) {
 			Settings: settingsJSON,
 		}
 
-		_, err := NewEmailNotifier(model, nil, tmpl)
+		_, err := NewEmailNotifier(model, nil, nil, tmpl)
 		require.Error(t, err)
 	})
 
@@ -48,7 +51,7 @@ func TestEmailNotifier(t *testing.T) {
 			Name:     "ops",
 			Type:     "email",
 			Settings: settingsJSON,
-		}, emailSender, tmpl)
+		}, emailSender, nil, tmpl)
 
 		require.NoError(t, err)
 
@@ -101,4 +104,72 @@ func TestEmailNotifier(t *testing.T) {
 			},
 		}, expected)
 	})
+	t.Run("with screenshots", func(t *testing.T) {
+		settingsJSON, err := simplejson.NewJson([]byte(`{"addresses": "someops@example.com"}`))
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "screenshot.png")
+		require.NoError(t, os.WriteFile(path, []byte("png"), 0600))
+		images := &fakeImageStore{images: map[string]*ngmodels.Image{
+			"local":  {Token: "local", Path: path},
+			"remote": {Token: "remote", Path: path, URL: "https://www.example.com/image.png"},
+		}}
+
+		emailSender := mockNotificationService()
+		emailNotifier, err := NewEmailNotifier(&NotificationChannelConfig{
+			Name:     "ops",
+			Type:     "email",
+			Settings: settingsJSON,
+		}, emailSender, images, tmpl)
+		require.NoError(t, err)
+
+		alerts := []*types.Alert{
+			{
+				Alert: model.Alert{
+					Labels:      model.LabelSet{"alertname": "alert1"},
+					Annotations: model.LabelSet{ngmodels.ImageTokenAnnotation: "local"},
+				},
+			}, {
+				Alert: model.Alert{
+					Labels:      model.LabelSet{"alertname": "alert2"},
+					Annotations: model.LabelSet{ngmodels.ImageTokenAnnotation: "local"},
+				},
+			}, {
+				Alert: model.Alert{
+					Labels:      model.LabelSet{"alertname": "alert3"},
+					Annotations: model.LabelSet{ngmodels.ImageTokenAnnotation: "remote"},
+				},
+			}, {
+				Alert: model.Alert{
+					Labels:      model.LabelSet{"alertname": "alert4"},
+					Annotations: model.LabelSet{ngmodels.ImageTokenAnnotation: "expired", ngmodels.ImageURLAnnotation: "https://www.example.com/expired.png"},
+				},
+			},
+		}
+
+		ok, err := emailNotifier.Notify(context.Background(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// the image shared by two alerts is embedded once
+		require.Equal(t, []string{path}, emailSender.EmailSync.EmbeddedFiles)
+		sent := emailSender.EmailSync.Data["Alerts"].(ExtendedAlerts)
+		require.Len(t, sent, 4)
+		require.Equal(t, "screenshot.png", sent[0].EmbeddedImage)
+		require.Equal(t, "screenshot.png", sent[1].EmbeddedImage)
+		require.Equal(t, "", sent[2].EmbeddedImage)
+		require.Equal(t, "https://www.example.com/image.png", sent[2].ImageURL)
+		require.Equal(t, "https://www.example.com/expired.png", sent[3].ImageURL)
+	})
+}
+
+type fakeImageStore struct {
+	images map[string]*ngmodels.Image
+}
+
+func (f *fakeImageStore) GetImage(_ context.Context, token string) (*ngmodels.Image, error) {
+	if image, ok := f.images[token]; ok {
+		return image, nil
+	}
+	return nil, ngmodels.ErrImageNotFound
 }
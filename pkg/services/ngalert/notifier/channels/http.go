@@ -0,0 +1,169 @@
+package channels
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+const defaultHTTPContentType = "application/json"
+
+// HTTPNotifier is responsible for sending alert notifications as HTTP requests
+// whose URL, headers and body are templated by the user.
+type HTTPNotifier struct {
+	*Base
+	URL         string
+	HTTPMethod  string
+	ContentType string
+	Headers     map[string]string
+	Body        string
+	User        string
+	Password    string
+	MaxAlerts   int
+	log         log.Logger
+	ns          notifications.WebhookSender
+	tmpl        *template.Template
+}
+
+// NewHTTPNotifier is the constructor for the HTTP notifier.
+func NewHTTPNotifier(model *NotificationChannelConfig, ns notifications.WebhookSender, t *template.Template, fn GetDecryptedValueFn) (*HTTPNotifier, error) {
+	if model.Settings == nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
+	}
+	if model.SecureSettings == nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "no secure settings supplied"}
+	}
+
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find url property in settings"}
+	}
+	body := model.Settings.Get("body").MustString()
+	if body == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find body property in settings"}
+	}
+
+	method := strings.ToUpper(model.Settings.Get("httpMethod").MustString(http.MethodPost))
+	if method != http.MethodPost && method != http.MethodPut && method != http.MethodPatch {
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid HTTP method %q, must be POST, PUT or PATCH", method)}
+	}
+
+	contentType := model.Settings.Get("contentType").MustString(defaultHTTPContentType)
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid content type %q", contentType), Err: err}
+	}
+
+	headers, err := parseHTTPHeaders(fn(context.Background(), model.SecureSettings, "headers", model.Settings.Get("headers").MustString()))
+	if err != nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "invalid headers", Err: err}
+	}
+
+	return &HTTPNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   model.UID,
+			Name:                  model.Name,
+			Type:                  model.Type,
+			DisableResolveMessage: model.DisableResolveMessage,
+			Settings:              model.Settings,
+		}),
+		URL:         url,
+		HTTPMethod:  method,
+		ContentType: contentType,
+		Headers:     headers,
+		Body:        body,
+		User:        model.Settings.Get("username").MustString(),
+		Password:    fn(context.Background(), model.SecureSettings, "password", model.Settings.Get("password").MustString()),
+		MaxAlerts:   model.Settings.Get("maxAlerts").MustInt(0),
+		log:         log.New("alerting.notifier.http"),
+		ns:          ns,
+		tmpl:        t,
+	}, nil
+}
+
+// parseHTTPHeaders parses headers written one per line in the "Name: value" format.
+// Empty lines are ignored.
+func parseHTTPHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid header %q, must be in the format \"Name: value\"", line)
+		}
+		name := strings.TrimSpace(line[:i])
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q, must be in the format \"Name: value\"", line)
+		}
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = strings.TrimSpace(line[i+1:])
+	}
+	return headers, scanner.Err()
+}
+
+// Notify sends the templated HTTP request.
+func (hn *HTTPNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	as, _ = truncateAlerts(hn.MaxAlerts, as)
+
+	var tmplErr error
+	tmpl, _ := TmplText(ctx, hn.tmpl, as, hn.log, &tmplErr)
+
+	url := tmpl(hn.URL)
+	body := tmpl(hn.Body)
+	headers := make(map[string]string, len(hn.Headers))
+	for name, value := range hn.Headers {
+		headers[name] = tmpl(value)
+	}
+	// Unlike with the other notifiers, the body is entirely written by the user,
+	// so a request with a partially templated body would most likely be rejected or, worse, misread.
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to template the HTTP request: %w", tmplErr)
+	}
+	if url == "" {
+		return false, errors.New("the URL of the HTTP request is empty")
+	}
+	if mediaType, _, _ := mime.ParseMediaType(hn.ContentType); isJSONMediaType(mediaType) && !json.Valid([]byte(body)) {
+		return false, fmt.Errorf("the templated body is not valid JSON for the content type %q", hn.ContentType)
+	}
+
+	cmd := &models.SendWebhookSync{
+		Url:         url,
+		User:        hn.User,
+		Password:    hn.Password,
+		Body:        body,
+		HttpMethod:  hn.HTTPMethod,
+		HttpHeader:  headers,
+		ContentType: hn.ContentType,
+	}
+
+	if err := hn.ns.SendWebhookSync(ctx, cmd); err != nil {
+		hn.log.Error("Failed to send HTTP request", "error", err, "receiver", hn.Name)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (hn *HTTPNotifier) SendResolved() bool {
+	return !hn.GetDisableResolveMessage()
+}
+
+// isJSONMediaType returns true for application/json and the JSON based media types, such as application/vnd.api+json.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || (strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,161 @@
+package channels
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestHTTPNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		}, {
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val2"},
+				Annotations: model.LabelSet{"ann1": "annv2"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		settings       string
+		secureSettings map[string]string
+		expWebhook     models.SendWebhookSync
+		expInitError   string
+		expMsgError    string
+	}{
+		{
+			name:     "Default config sends the templated body as JSON",
+			settings: `{"url": "http://localhost/test", "body": "{\"summary\": \"{{ .CommonLabels.alertname }} is {{ .Status }}\", \"count\": {{ len .Alerts }}}"}`,
+			expWebhook: models.SendWebhookSync{
+				Url:         "http://localhost/test",
+				Body:        `{"summary": "alert1 is firing", "count": 2}`,
+				HttpMethod:  "POST",
+				HttpHeader:  map[string]string{},
+				ContentType: "application/json",
+			},
+		}, {
+			name: "Custom config with method, headers and templated URL",
+			settings: `{
+				"url": "http://localhost/issues/{{ .CommonLabels.alertname }}",
+				"httpMethod": "patch",
+				"contentType": "text/plain; charset=utf-8",
+				"headers": "x-source: grafana\n\nX-Alert-Count:{{ len .Alerts.Firing }}",
+				"body": "{{ range .Alerts }}{{ .Labels.lbl1 }} {{ end }}",
+				"username": "user1",
+				"password": "mysecret",
+				"maxAlerts": 1
+			}`,
+			expWebhook: models.SendWebhookSync{
+				Url:         "http://localhost/issues/alert1",
+				User:        "user1",
+				Password:    "mysecret",
+				Body:        "val1 ",
+				HttpMethod:  "PATCH",
+				HttpHeader:  map[string]string{"X-Source": "grafana", "X-Alert-Count": "1"},
+				ContentType: "text/plain; charset=utf-8",
+			},
+		}, {
+			name:           "Headers from the secure settings",
+			settings:       `{"url": "http://localhost/test", "body": "body", "contentType": "text/plain", "headers": "X-Source: plain"}`,
+			secureSettings: map[string]string{"headers": "Authorization: Bearer secret\nX-Source: grafana"},
+			expWebhook: models.SendWebhookSync{
+				Url:         "http://localhost/test",
+				Body:        "body",
+				HttpMethod:  "POST",
+				HttpHeader:  map[string]string{"Authorization": "Bearer secret", "X-Source": "grafana"},
+				ContentType: "text/plain",
+			},
+		}, {
+			name:        "Body that is not valid JSON",
+			settings:    `{"url": "http://localhost/test", "body": "{\"summary\": {{ .CommonLabels.alertname }}}"}`,
+			expMsgError: `the templated body is not valid JSON for the content type "application/json"`,
+		}, {
+			name:        "Body that fails to template",
+			settings:    `{"url": "http://localhost/test", "body": "{{ template \"missing\" . }}"}`,
+			expMsgError: `failed to template the HTTP request: template: :1:12: executing "" at <{{template "missing" .}}>: template "missing" not defined`,
+		}, {
+			name:         "Missing URL",
+			settings:     `{"body": "body"}`,
+			expInitError: `failed to validate receiver "http_testing" of type "http": could not find url property in settings`,
+		}, {
+			name:         "Missing body",
+			settings:     `{"url": "http://localhost/test"}`,
+			expInitError: `failed to validate receiver "http_testing" of type "http": could not find body property in settings`,
+		}, {
+			name:         "Invalid method",
+			settings:     `{"url": "http://localhost/test", "body": "body", "httpMethod": "GET"}`,
+			expInitError: `failed to validate receiver "http_testing" of type "http": invalid HTTP method "GET", must be POST, PUT or PATCH`,
+		}, {
+			name:         "Invalid header",
+			settings:     `{"url": "http://localhost/test", "body": "body", "headers": "X-Source grafana"}`,
+			expInitError: `failed to validate receiver "http_testing" of type "http": invalid headers: invalid header "X-Source grafana", must be in the format "Name: value"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+			secureSettings := make(map[string][]byte)
+			for key, value := range c.secureSettings {
+				secureSettings[key], err = secretsService.Encrypt(context.Background(), []byte(value), secrets.WithoutScope())
+				require.NoError(t, err)
+			}
+
+			m := &NotificationChannelConfig{
+				Name:           "http_testing",
+				Type:           "http",
+				Settings:       settingsJSON,
+				SecureSettings: secureSettings,
+			}
+
+			webhookSender := mockNotificationService()
+			decryptFn := secretsService.GetDecryptedValue
+			pn, err := NewHTTPNotifier(m, webhookSender, tmpl, decryptFn)
+			if c.expInitError != "" {
+				require.Error(t, err)
+				require.Equal(t, c.expInitError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ok, err := pn.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.Error(t, err)
+				require.Equal(t, c.expMsgError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			require.Equal(t, c.expWebhook, webhookSender.Webhook)
+		})
+	}
+}
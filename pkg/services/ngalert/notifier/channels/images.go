@@ -0,0 +1,74 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// errStopImages is returned by the function passed to withStoredImages to stop at the current image.
+var errStopImages = errors.New("stop images")
+
+// ImageStore is used by the notifiers that attach the screenshots of alerts to notifications.
+type ImageStore interface {
+	GetImage(ctx context.Context, token string) (*ngmodels.Image, error)
+}
+
+// withStoredImages calls forEachFn for each alert that has a screenshot, with the index of the alert in alerts.
+// The same image is passed once per alert, so notifiers that send images separately must skip duplicates.
+func withStoredImages(ctx context.Context, l log.Logger, imageStore ImageStore, forEachFn func(index int, image ngmodels.Image) error, alerts ...*types.Alert) error {
+	for i, alert := range alerts {
+		image, ok := getImage(ctx, l, imageStore, alert)
+		if !ok {
+			continue
+		}
+		if err := forEachFn(i, image); err != nil {
+			if errors.Is(err, errStopImages) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// getImage returns the screenshot of the alert. If the image is not in the store anymore,
+// it returns the URL sent with the alert, if any.
+func getImage(ctx context.Context, l log.Logger, imageStore ImageStore, alert *types.Alert) (ngmodels.Image, bool) {
+	token := string(alert.Annotations[model.LabelName(ngmodels.ImageTokenAnnotation)])
+	if token == "" {
+		return ngmodels.Image{}, false
+	}
+
+	image := ngmodels.Image{
+		Token: token,
+		URL:   string(alert.Annotations[model.LabelName(ngmodels.ImageURLAnnotation)]),
+	}
+	if imageStore == nil {
+		return image, image.HasURL()
+	}
+
+	stored, err := imageStore.GetImage(ctx, token)
+	if err != nil {
+		if !errors.Is(err, ngmodels.ErrImageNotFound) {
+			l.Warn("failed to get image", "token", token, "err", err)
+		}
+		return image, image.HasURL()
+	}
+	return *stored, true
+}
+
+// hasLocalFile returns true if the image was taken by this Grafana instance and is still on its disk.
+func hasLocalFile(image ngmodels.Image) bool {
+	if image.Path == "" {
+		return false
+	}
+	_, err := os.Stat(image.Path)
+	return err == nil
+}
@@ -0,0 +1,224 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	MQTTMessageFormatJSON = "json"
+	MQTTMessageFormatText = "text"
+
+	// mqttTimeout is how long to wait for the broker to accept the connection and the message.
+	mqttTimeout = 10 * time.Second
+	// mqttDisconnectQuiesce is how long to wait for pending work when disconnecting, in milliseconds.
+	mqttDisconnectQuiesce = 250
+)
+
+// MQTTNotifier is responsible for publishing
+// alert notifications to an MQTT broker.
+type MQTTNotifier struct {
+	*Base
+	BrokerURL     string
+	ClientID      string
+	Topic         string
+	MessageFormat string
+	Message       string
+	QoS           byte
+	Retain        bool
+	Username      string
+	Password      string
+	TLSConfig     *tls.Config
+	log           log.Logger
+	tmpl          *template.Template
+}
+
+// mqttMessage is the payload of the notifications published in the JSON format.
+type mqttMessage struct {
+	*ExtendedData
+
+	Message string `json:"message"`
+}
+
+// NewMQTTNotifier is the constructor for the MQTT notifier.
+func NewMQTTNotifier(model *NotificationChannelConfig, t *template.Template, fn GetDecryptedValueFn) (*MQTTNotifier, error) {
+	if model.Settings == nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
+	}
+	if model.SecureSettings == nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "no secure settings supplied"}
+	}
+
+	brokerURL := model.Settings.Get("brokerUrl").MustString()
+	if brokerURL == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find broker URL in settings"}
+	}
+	topic := model.Settings.Get("topic").MustString()
+	if topic == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find topic in settings"}
+	}
+
+	messageFormat := model.Settings.Get("messageFormat").MustString(MQTTMessageFormatJSON)
+	if messageFormat != MQTTMessageFormatJSON && messageFormat != MQTTMessageFormatText {
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid message format %q, must be %q or %q", messageFormat, MQTTMessageFormatJSON, MQTTMessageFormatText)}
+	}
+
+	qos := model.Settings.Get("qos").MustInt64(0)
+	// the UI sends the QoS selected in a list as a string
+	if s := model.Settings.Get("qos").MustString(); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid QoS %q, must be 0, 1 or 2", s)}
+		}
+		qos = v
+	}
+	if qos < 0 || qos > 2 {
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid QoS %d, must be 0, 1 or 2", qos)}
+	}
+
+	clientID := model.Settings.Get("clientId").MustString()
+	if clientID == "" {
+		clientID = "grafana_" + model.UID
+	}
+
+	tlsConfig, err := newMQTTTLSConfig(model, fn)
+	if err != nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "invalid TLS configuration", Err: err}
+	}
+
+	return &MQTTNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   model.UID,
+			Name:                  model.Name,
+			Type:                  model.Type,
+			DisableResolveMessage: model.DisableResolveMessage,
+			Settings:              model.Settings,
+		}),
+		BrokerURL:     brokerURL,
+		ClientID:      clientID,
+		Topic:         topic,
+		MessageFormat: messageFormat,
+		Message:       model.Settings.Get("message").MustString(`{{ template "default.message" . }}`),
+		QoS:           byte(qos),
+		Retain:        model.Settings.Get("retain").MustBool(false),
+		Username:      model.Settings.Get("username").MustString(),
+		Password:      fn(context.Background(), model.SecureSettings, "password", model.Settings.Get("password").MustString()),
+		TLSConfig:     tlsConfig,
+		log:           log.New("alerting.notifier.mqtt"),
+		tmpl:          t,
+	}, nil
+}
+
+// newMQTTTLSConfig returns the TLS configuration used to connect to brokers with the ssl, tls or wss schemes.
+// The CA certificate and the client certificate and key are PEM encoded.
+func newMQTTTLSConfig(model *NotificationChannelConfig, fn GetDecryptedValueFn) (*tls.Config, error) {
+	cfg := &tls.Config{
+		// nolint:gosec
+		// the skip of the verification is an explicit choice of the user.
+		InsecureSkipVerify: model.Settings.Get("insecureSkipVerify").MustBool(false),
+	}
+
+	if caCert := model.Settings.Get("tlsCACertificate").MustString(); caCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("failed to parse the CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+
+	clientCert := model.Settings.Get("tlsClientCertificate").MustString()
+	clientKey := fn(context.Background(), model.SecureSettings, "tlsClientKey", model.Settings.Get("tlsClientKey").MustString())
+	if clientCert != "" || clientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// Notify publishes the alert notification to the MQTT broker.
+func (mn *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, data := TmplText(ctx, mn.tmpl, as, mn.log, &tmplErr)
+
+	message := tmpl(mn.Message)
+	topic := tmpl(mn.Topic)
+	if tmplErr != nil {
+		mn.log.Warn("failed to template MQTT message", "err", tmplErr.Error())
+	}
+	if topic == "" {
+		return false, errors.New("the MQTT topic is empty")
+	}
+
+	payload := []byte(message)
+	if mn.MessageFormat == MQTTMessageFormatJSON {
+		b, err := json.Marshal(mqttMessage{ExtendedData: data, Message: message})
+		if err != nil {
+			return false, err
+		}
+		payload = b
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(mn.BrokerURL).
+		SetClientID(mn.ClientID).
+		SetUsername(mn.Username).
+		SetPassword(mn.Password).
+		SetTLSConfig(mn.TLSConfig).
+		SetConnectTimeout(mqttTimeout).
+		SetAutoReconnect(false).
+		SetCleanSession(true)
+
+	mn.log.Debug("publishing MQTT notification", "topic", topic, "qos", mn.QoS)
+	if err := publishMQTT(ctx, opts, topic, mn.QoS, mn.Retain, payload); err != nil {
+		mn.log.Error("Failed to publish MQTT message", "error", err, "topic", topic)
+		return false, err
+	}
+	return true, nil
+}
+
+func (mn *MQTTNotifier) SendResolved() bool {
+	return !mn.GetDisableResolveMessage()
+}
+
+// publishMQTT connects to the broker, publishes the message and disconnects.
+// Notifications are rare enough for a connection per notification.
+func publishMQTT(ctx context.Context, opts *mqtt.ClientOptions, topic string, qos byte, retain bool, payload []byte) error {
+	client := mqtt.NewClient(opts)
+	if err := waitMQTTToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to the MQTT broker: %w", err)
+	}
+	defer client.Disconnect(mqttDisconnectQuiesce)
+
+	if err := waitMQTTToken(ctx, client.Publish(topic, qos, retain, payload)); err != nil {
+		return fmt.Errorf("failed to publish the MQTT message: %w", err)
+	}
+	return nil
+}
+
+func waitMQTTToken(ctx context.Context, token mqtt.Token) error {
+	ctx, cancel := context.WithTimeout(ctx, mqttTimeout)
+	defer cancel()
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
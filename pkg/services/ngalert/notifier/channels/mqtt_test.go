@@ -0,0 +1,289 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+type mqttPublished struct {
+	ClientID string
+	Username string
+	Password string
+	Topic    string
+	QoS      byte
+	Retain   bool
+	Payload  string
+}
+
+// mqttTestBroker is a minimal MQTT broker that accepts the connections of the MQTT notifier
+// and records the messages it publishes.
+type mqttTestBroker struct {
+	t         *testing.T
+	listener  net.Listener
+	password  string
+	published chan mqttPublished
+	wg        sync.WaitGroup
+}
+
+func newMQTTTestBroker(t *testing.T, password string) *mqttTestBroker {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &mqttTestBroker{t: t, listener: l, password: password, published: make(chan mqttPublished, 10)}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b.wg.Add(1)
+			go func() {
+				defer b.wg.Done()
+				b.serve(conn)
+			}()
+		}
+	}()
+	t.Cleanup(func() {
+		_ = l.Close()
+		b.wg.Wait()
+	})
+	return b
+}
+
+func (b *mqttTestBroker) URL() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *mqttTestBroker) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	var client mqttPublished
+	for {
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		cp, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		var reply packets.ControlPacket
+		switch p := cp.(type) {
+		case *packets.ConnectPacket:
+			client.ClientID, client.Username, client.Password = p.ClientIdentifier, p.Username, string(p.Password)
+			connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			if b.password != "" && client.Password != b.password {
+				connack.ReturnCode = packets.ErrRefusedBadUsernameOrPassword
+			}
+			reply = connack
+		case *packets.PublishPacket:
+			published := client
+			published.Topic, published.QoS, published.Retain, published.Payload = p.TopicName, p.Qos, p.Retain, string(p.Payload)
+			b.published <- published
+			switch p.Qos {
+			case 1:
+				puback := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				puback.MessageID = p.MessageID
+				reply = puback
+			case 2:
+				pubrec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				pubrec.MessageID = p.MessageID
+				reply = pubrec
+			}
+		case *packets.PubrelPacket:
+			pubcomp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			pubcomp.MessageID = p.MessageID
+			reply = pubcomp
+		case *packets.PingreqPacket:
+			reply = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			return
+		}
+
+		if reply != nil {
+			if err := reply.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		settings       string
+		secureSettings map[string][]byte
+		brokerPassword string
+		expPublished   mqttPublished
+		expJSON        bool
+		expInitError   string
+		expMsgError    string
+	}{
+		{
+			name:     "Default config publishes JSON",
+			settings: `{"brokerUrl": "BROKER", "topic": "grafana/alerts"}`,
+			expPublished: mqttPublished{
+				ClientID: "grafana_mqtt_uid",
+				Topic:    "grafana/alerts",
+				Payload:  "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n",
+			},
+			expJSON: true,
+		}, {
+			name: "Custom config publishes templated text",
+			settings: `{
+				"brokerUrl": "BROKER",
+				"clientId": "factory",
+				"topic": "alerts/{{ .CommonLabels.lbl1 }}",
+				"messageFormat": "text",
+				"message": "{{ len .Alerts.Firing }} alerts are firing",
+				"qos": "1",
+				"retain": true,
+				"username": "grafana"
+			}`,
+			secureSettings: map[string][]byte{"password": []byte("secret")},
+			brokerPassword: "secret",
+			expPublished: mqttPublished{
+				ClientID: "factory",
+				Username: "grafana",
+				Password: "secret",
+				Topic:    "alerts/val1",
+				QoS:      1,
+				Retain:   true,
+				Payload:  "1 alerts are firing",
+			},
+		}, {
+			name:     "Exactly once delivery",
+			settings: `{"brokerUrl": "BROKER", "topic": "grafana/alerts", "messageFormat": "text", "message": "firing", "qos": 2}`,
+			expPublished: mqttPublished{
+				ClientID: "grafana_mqtt_uid",
+				Topic:    "grafana/alerts",
+				QoS:      2,
+				Payload:  "firing",
+			},
+		}, {
+			name:           "Wrong password",
+			settings:       `{"brokerUrl": "BROKER", "topic": "grafana/alerts", "username": "grafana", "password": "wrong"}`,
+			brokerPassword: "secret",
+			expMsgError:    "failed to connect to the MQTT broker: bad user name or password",
+		}, {
+			name:         "Missing broker URL",
+			settings:     `{"topic": "grafana/alerts"}`,
+			expInitError: `failed to validate receiver "mqtt_testing" of type "mqtt": could not find broker URL in settings`,
+		}, {
+			name:         "Missing topic",
+			settings:     `{"brokerUrl": "BROKER"}`,
+			expInitError: `failed to validate receiver "mqtt_testing" of type "mqtt": could not find topic in settings`,
+		}, {
+			name:         "Invalid QoS",
+			settings:     `{"brokerUrl": "BROKER", "topic": "grafana/alerts", "qos": "3"}`,
+			expInitError: `failed to validate receiver "mqtt_testing" of type "mqtt": invalid QoS 3, must be 0, 1 or 2`,
+		}, {
+			name:         "Invalid message format",
+			settings:     `{"brokerUrl": "BROKER", "topic": "grafana/alerts", "messageFormat": "xml"}`,
+			expInitError: `failed to validate receiver "mqtt_testing" of type "mqtt": invalid message format "xml", must be "json" or "text"`,
+		}, {
+			name:         "Invalid CA certificate",
+			settings:     `{"brokerUrl": "BROKER", "topic": "grafana/alerts", "tlsCACertificate": "not a certificate"}`,
+			expInitError: `failed to validate receiver "mqtt_testing" of type "mqtt": invalid TLS configuration: failed to parse the CA certificate`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			broker := newMQTTTestBroker(t, c.brokerPassword)
+
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+			if settingsJSON.Get("brokerUrl").MustString() == "BROKER" {
+				settingsJSON.Set("brokerUrl", broker.URL())
+			}
+			secureSettings := c.secureSettings
+			if secureSettings == nil {
+				secureSettings = map[string][]byte{}
+			}
+
+			m := &NotificationChannelConfig{
+				UID:            "mqtt_uid",
+				Name:           "mqtt_testing",
+				Type:           "mqtt",
+				Settings:       settingsJSON,
+				SecureSettings: secureSettings,
+			}
+
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+			decryptFn := func(ctx context.Context, sjd map[string][]byte, key string, fallback string) string {
+				if v, ok := sjd[key]; ok {
+					return string(v)
+				}
+				return secretsService.GetDecryptedValue(ctx, sjd, key, fallback)
+			}
+			pn, err := NewMQTTNotifier(m, tmpl, decryptFn)
+			if c.expInitError != "" {
+				require.Error(t, err)
+				require.Equal(t, c.expInitError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ok, err := pn.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.Error(t, err)
+				require.False(t, ok)
+				require.Equal(t, c.expMsgError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			var published mqttPublished
+			select {
+			case published = <-broker.published:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the message was not published")
+			}
+
+			if c.expJSON {
+				var payload map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(published.Payload), &payload))
+				require.Equal(t, c.expPublished.Payload, payload["message"])
+				require.Equal(t, "firing", payload["status"])
+				require.Len(t, payload["alerts"], 1)
+				published.Payload = c.expPublished.Payload
+			}
+			require.Equal(t, c.expPublished, published)
+		})
+	}
+}
@@ -144,6 +144,7 @@ type attachment struct {
 	Footer     string              `json:"footer"`
 	FooterIcon string              `json:"footer_icon"`
 	Color      string              `json:"color,omitempty"`
+	ImageURL   string              `json:"image_url,omitempty"`
 	Ts         int64               `json:"ts,omitempty"`
 }
 
@@ -245,7 +246,7 @@ var sendSlackRequest = func(request *http.Request, logger log.Logger) error {
 func (sn *SlackNotifier) buildSlackMessage(ctx context.Context, as []*types.Alert) (*slackMessage, error) {
 	alerts := types.Alerts(as...)
 	var tmplErr error
-	tmpl, _ := TmplText(ctx, sn.tmpl, as, sn.log, &tmplErr)
+	tmpl, data := TmplText(ctx, sn.tmpl, as, sn.log, &tmplErr)
 
 	ruleURL := joinUrlPath(sn.tmpl.ExternalURL.String(), "/alerting/list", sn.log)
 
@@ -272,6 +273,11 @@ func (sn *SlackNotifier) buildSlackMessage(ctx context.Context, as []*types.Aler
 		sn.log.Warn("failed to template Slack message", "err", tmplErr.Error())
 	}
 
+	// Slack can only show one image per attachment, so the screenshot of the first alert is used
+	if imageURLs := data.Alerts.ImageURLs(); len(imageURLs) > 0 {
+		req.Attachments[0].ImageURL = imageURLs[0]
+	}
+
 	mentionsBuilder := strings.Builder{}
 	appendSpace := func() {
 		if mentionsBuilder.Len() > 0 {
@@ -106,6 +106,40 @@ func TestSlackNotifier(t *testing.T) {
 			},
 			expMsgError: nil,
 		},
+		{
+			name: "Correct config with the screenshot of the alert",
+			settings: `{
+				"url": "https://webhook.com",
+				"recipient": "#testchannel"
+			}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1", "__alertImageToken__": "abc", "__alertImageURL__": "https://www.example.com/image.png"},
+					},
+				},
+			},
+			expMsg: &slackMessage{
+				Channel:  "#testchannel",
+				Username: "Grafana",
+				Attachments: []attachment{
+					{
+						Title:      "[FIRING:1]  (val1)",
+						TitleLink:  "http://localhost/alerting/list",
+						Text:       "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n",
+						Fallback:   "[FIRING:1]  (val1)",
+						Fields:     nil,
+						Footer:     "Grafana v" + setting.BuildVersion,
+						FooterIcon: "https://grafana.com/assets/img/fav32.png",
+						Color:      "#D63232",
+						ImageURL:   "https://www.example.com/image.png",
+						Ts:         0,
+					},
+				},
+			},
+			expMsgError: nil,
+		},
 		{
 			name: "Correct config with multiple alerts and template",
 			settings: `{
@@ -54,11 +54,28 @@ func NewTeamsNotifier(model *NotificationChannelConfig, ns notifications.Webhook
 // Notify send an alert notification to Microsoft teams.
 func (tn *TeamsNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 	var tmplErr error
-	tmpl, _ := TmplText(ctx, tn.tmpl, as, tn.log, &tmplErr)
+	tmpl, data := TmplText(ctx, tn.tmpl, as, tn.log, &tmplErr)
 
 	ruleURL := joinUrlPath(tn.tmpl.ExternalURL.String(), "/alerting/list", tn.log)
 
 	title := tmpl(DefaultMessageTitleEmbed)
+	sections := []map[string]interface{}{
+		{
+			"title": "Details",
+			"text":  tmpl(tn.Message),
+		},
+	}
+	if imageURLs := data.Alerts.ImageURLs(); len(imageURLs) > 0 {
+		images := make([]map[string]interface{}, 0, len(imageURLs))
+		for _, imageURL := range imageURLs {
+			images = append(images, map[string]interface{}{"image": imageURL})
+		}
+		sections = append(sections, map[string]interface{}{
+			"title":  "Images",
+			"images": images,
+		})
+	}
+
 	body := map[string]interface{}{
 		"@type":    "MessageCard",
 		"@context": "http://schema.org/extensions",
@@ -67,12 +84,7 @@ func (tn *TeamsNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 		"summary":    title,
 		"title":      title,
 		"themeColor": getAlertStatusColor(types.Alerts(as...).Status()),
-		"sections": []map[string]interface{}{
-			{
-				"title": "Details",
-				"text":  tmpl(tn.Message),
-			},
-		},
+		"sections":   sections,
 		"potentialAction": []map[string]interface{}{
 			{
 				"@context": "http://schema.org",
@@ -62,6 +62,51 @@ func TestTeamsNotifier(t *testing.T) {
 				},
 			},
 			expMsgError: nil,
+		}, {
+			name: "Custom config with the screenshots of the alerts",
+			settings: `{
+				"url": "http://localhost",
+				"message": "{{ len .Alerts.Firing }} alerts are firing"
+			}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"__alertImageToken__": "abc", "__alertImageURL__": "https://www.example.com/image.png"},
+					},
+				}, {
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val2"},
+						Annotations: model.LabelSet{"__alertImageToken__": "abc", "__alertImageURL__": "https://www.example.com/image.png"},
+					},
+				},
+			},
+			expMsg: map[string]interface{}{
+				"@type":      "MessageCard",
+				"@context":   "http://schema.org/extensions",
+				"summary":    "[FIRING:2]  ",
+				"title":      "[FIRING:2]  ",
+				"themeColor": "#D63232",
+				"sections": []map[string]interface{}{
+					{
+						"title": "Details",
+						"text":  "2 alerts are firing",
+					},
+					{
+						"title":  "Images",
+						"images": []map[string]interface{}{{"image": "https://www.example.com/image.png"}},
+					},
+				},
+				"potentialAction": []map[string]interface{}{
+					{
+						"@context": "http://schema.org",
+						"@type":    "OpenUri",
+						"name":     "View Rule",
+						"targets":  []map[string]interface{}{{"os": "default", "uri": "http://localhost/alerting/list"}},
+					},
+				},
+			},
+			expMsgError: nil,
 		}, {
 			name: "Custom config with multiple alerts",
 			settings: `{
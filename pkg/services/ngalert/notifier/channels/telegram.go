@@ -4,17 +4,22 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"os"
+	"path/filepath"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 )
 
 var (
-	TelegramAPIURL = "https://api.telegram.org/bot%s/sendMessage"
+	TelegramAPIURL      = "https://api.telegram.org/bot%s/sendMessage"
+	TelegramPhotoAPIURL = "https://api.telegram.org/bot%s/sendPhoto"
 )
 
 // TelegramNotifier is responsible for sending
@@ -26,11 +31,12 @@ type TelegramNotifier struct {
 	Message  string
 	log      log.Logger
 	ns       notifications.WebhookSender
+	images   ImageStore
 	tmpl     *template.Template
 }
 
 // NewTelegramNotifier is the constructor for the Telegram notifier
-func NewTelegramNotifier(model *NotificationChannelConfig, ns notifications.WebhookSender, t *template.Template, fn GetDecryptedValueFn) (*TelegramNotifier, error) {
+func NewTelegramNotifier(model *NotificationChannelConfig, ns notifications.WebhookSender, images ImageStore, t *template.Template, fn GetDecryptedValueFn) (*TelegramNotifier, error) {
 	if model.Settings == nil {
 		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
 	}
@@ -64,59 +70,117 @@ func NewTelegramNotifier(model *NotificationChannelConfig, ns notifications.Webh
 		tmpl:     t,
 		log:      log.New("alerting.notifier.telegram"),
 		ns:       ns,
+		images:   images,
 	}, nil
 }
 
-// Notify send an alert notification to Telegram.
+// Notify send an alert notification to Telegram. The screenshots of the alerts are sent
+// as separate photos after the message.
 func (tn *TelegramNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 	msg, err := tn.buildTelegramMessage(ctx, as)
 	if err != nil {
 		return false, err
 	}
 
+	cmd, err := tn.newWebhookSyncCmd(TelegramAPIURL, func(w *multipart.Writer) error {
+		for k, v := range msg {
+			if err := writeField(w, k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	tn.log.Info("sending telegram notification", "chat_id", msg["chat_id"])
+	if err := tn.ns.SendWebhookSync(ctx, cmd); err != nil {
+		tn.log.Error("Failed to send webhook", "error", err, "webhook", tn.Name)
+		return false, err
+	}
+
+	// the message was sent, so failing to send an image does not fail the notification
+	sent := map[string]struct{}{}
+	_ = withStoredImages(ctx, tn.log, tn.images, func(_ int, image ngmodels.Image) error {
+		if _, ok := sent[image.Token]; ok {
+			return nil
+		}
+		sent[image.Token] = struct{}{}
+		if err := tn.sendImage(ctx, msg["chat_id"], image); err != nil {
+			tn.log.Warn("failed to send image to telegram", "token", image.Token, "err", err)
+		}
+		return nil
+	}, as...)
+
+	return true, nil
+}
+
+// sendImage sends the image by its URL, or uploads it if it was taken by this Grafana instance.
+func (tn *TelegramNotifier) sendImage(ctx context.Context, chatID string, image ngmodels.Image) error {
+	if !image.HasURL() && !hasLocalFile(image) {
+		return nil
+	}
+
+	cmd, err := tn.newWebhookSyncCmd(TelegramPhotoAPIURL, func(w *multipart.Writer) error {
+		if err := writeField(w, "chat_id", chatID); err != nil {
+			return err
+		}
+		if image.HasURL() {
+			return writeField(w, "photo", image.URL)
+		}
+
+		f, err := os.Open(image.Path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				tn.log.Warn("failed to close image", "err", err)
+			}
+		}()
+		fw, err := w.CreateFormFile("photo", filepath.Base(image.Path))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(fw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tn.ns.SendWebhookSync(ctx, cmd)
+}
+
+// newWebhookSyncCmd returns a request to the Telegram API with the multipart body written by writeFn.
+func (tn *TelegramNotifier) newWebhookSyncCmd(apiURL string, writeFn func(w *multipart.Writer) error) (*models.SendWebhookSync, error) {
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
-	defer func() {
-		if err := w.Close(); err != nil {
-			tn.log.Warn("Failed to close writer", "err", err)
-		}
-	}()
 	boundary := GetBoundary()
 	if boundary != "" {
-		err = w.SetBoundary(boundary)
-		if err != nil {
-			return false, err
+		if err := w.SetBoundary(boundary); err != nil {
+			return nil, err
 		}
 	}
 
-	for k, v := range msg {
-		if err := writeField(w, k, v); err != nil {
-			return false, err
-		}
+	if err := writeFn(w); err != nil {
+		return nil, err
 	}
 
 	// We need to close it before using so that the last part
 	// is added to the writer along with the boundary.
 	if err := w.Close(); err != nil {
-		return false, err
+		return nil, err
 	}
 
-	tn.log.Info("sending telegram notification", "chat_id", msg["chat_id"])
-	cmd := &models.SendWebhookSync{
-		Url:        fmt.Sprintf(TelegramAPIURL, tn.BotToken),
+	return &models.SendWebhookSync{
+		Url:        fmt.Sprintf(apiURL, tn.BotToken),
 		Body:       body.String(),
 		HttpMethod: "POST",
 		HttpHeader: map[string]string{
 			"Content-Type": w.FormDataContentType(),
 		},
-	}
-
-	if err := tn.ns.SendWebhookSync(ctx, cmd); err != nil {
-		tn.log.Error("Failed to send webhook", "error", err, "webhook", tn.Name)
-		return false, err
-	}
-
-	return true, nil
+	}, nil
 }
 
 func (tn *TelegramNotifier) buildTelegramMessage(ctx context.Context, as []*types.Alert) (map[string]string, error) {
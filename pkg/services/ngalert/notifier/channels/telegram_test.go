@@ -101,7 +101,7 @@ func TestTelegramNotifier(t *testing.T) {
 			webhookSender := mockNotificationService()
 			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
 			decryptFn := secretsService.GetDecryptedValue
-			pn, err := NewTelegramNotifier(m, webhookSender, tmpl, decryptFn)
+			pn, err := NewTelegramNotifier(m, webhookSender, nil, tmpl, decryptFn)
 			if c.expInitError != "" {
 				require.Error(t, err)
 				require.Equal(t, c.expInitError, err.Error())
@@ -123,3 +123,41 @@ func TestTelegramNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestTelegramNotifier_Images(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	settingsJSON, err := simplejson.NewJson([]byte(`{"bottoken": "abcdefgh0123456789", "chatid": "someid"}`))
+	require.NoError(t, err)
+	m := &NotificationChannelConfig{
+		Name:           "telegram_testing",
+		Type:           "telegram",
+		Settings:       settingsJSON,
+		SecureSettings: map[string][]byte{},
+	}
+
+	webhookSender := mockNotificationService()
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	pn, err := NewTelegramNotifier(m, webhookSender, nil, tmpl, secretsService.GetDecryptedValue)
+	require.NoError(t, err)
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1"},
+				Annotations: model.LabelSet{"__alertImageToken__": "abc", "__alertImageURL__": "https://www.example.com/image.png"},
+			},
+		},
+	}
+	ok, err := pn.Notify(context.Background(), alerts...)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// the photo is sent after the message
+	require.Equal(t, "https://api.telegram.org/botabcdefgh0123456789/sendPhoto", webhookSender.Webhook.Url)
+	require.Contains(t, webhookSender.Webhook.Body, "https://www.example.com/image.png")
+	require.Contains(t, webhookSender.Webhook.Body, "someid")
+}
@@ -29,6 +29,9 @@ type ExtendedAlert struct {
 	DashboardURL string      `json:"dashboardURL"`
 	PanelURL     string      `json:"panelURL"`
 	ValueString  string      `json:"valueString"`
+	ImageURL     string      `json:"imageURL,omitempty"`
+	// EmbeddedImage is the name of the screenshot embedded in the notification when it has no URL.
+	EmbeddedImage string `json:"embeddedImage,omitempty"`
 }
 
 type ExtendedAlerts []ExtendedAlert
@@ -65,6 +68,9 @@ func extendAlert(alert template.Alert, externalURL string, logger log.Logger) *E
 		GeneratorURL: alert.GeneratorURL,
 		Fingerprint:  alert.Fingerprint,
 	}
+	if alert.Annotations != nil {
+		extended.ImageURL = alert.Annotations[ngmodels.ImageURLAnnotation]
+	}
 
 	// fill in some grafana-specific urls
 	if len(externalURL) == 0 {
@@ -160,3 +166,20 @@ func (as ExtendedAlerts) Resolved() []ExtendedAlert {
 	}
 	return res
 }
+
+// ImageURLs returns the URLs of the screenshots of the alerts, without duplicates.
+func (as ExtendedAlerts) ImageURLs() []string {
+	res := []string{}
+	seen := map[string]struct{}{}
+	for _, a := range as {
+		if a.ImageURL == "" {
+			continue
+		}
+		if _, ok := seen[a.ImageURL]; ok {
+			continue
+		}
+		seen[a.ImageURL] = struct{}{}
+		res = append(res, a.ImageURL)
+	}
+	return res
+}
@@ -0,0 +1,140 @@
+package notifier
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// maxDeliveryLogAttempts is the number of notification attempts kept per integration.
+const maxDeliveryLogAttempts = 10
+
+// deliveryLog keeps in memory the last attempts to send notifications of each integration.
+// The attempts are kept across configuration changes as long as the integration exists.
+type deliveryLog struct {
+	mtx      sync.RWMutex
+	attempts map[string][]apimodels.NotificationAttempt
+}
+
+func newDeliveryLog() *deliveryLog {
+	return &deliveryLog{attempts: map[string][]apimodels.NotificationAttempt{}}
+}
+
+// deliveryLogKey identifies an integration of a receiver. The index is used when the integration has no UID.
+func deliveryLogKey(receiver string, integration *apimodels.PostableGrafanaReceiver, index int) string {
+	if integration.UID != "" {
+		return receiver + "/" + integration.UID
+	}
+	return receiver + "/" + strconv.Itoa(index)
+}
+
+// deliveryLogKeys returns the keys of all the integrations of the receivers.
+func deliveryLogKeys(receivers []*apimodels.PostableApiReceiver) map[string]struct{} {
+	keys := map[string]struct{}{}
+	for _, receiver := range receivers {
+		for i, integration := range receiver.GrafanaManagedReceivers {
+			keys[deliveryLogKey(receiver.Name, integration, i)] = struct{}{}
+		}
+	}
+	return keys
+}
+
+func (l *deliveryLog) record(key string, attempt apimodels.NotificationAttempt) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	attempts := append([]apimodels.NotificationAttempt{attempt}, l.attempts[key]...)
+	if len(attempts) > maxDeliveryLogAttempts {
+		attempts = attempts[:maxDeliveryLogAttempts]
+	}
+	l.attempts[key] = attempts
+}
+
+// get returns the attempts of the integration, the most recent first.
+func (l *deliveryLog) get(key string) []apimodels.NotificationAttempt {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	return append([]apimodels.NotificationAttempt{}, l.attempts[key]...)
+}
+
+// retain removes the attempts of the integrations that are not in keys.
+func (l *deliveryLog) retain(keys map[string]struct{}) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for key := range l.attempts {
+		if _, ok := keys[key]; !ok {
+			delete(l.attempts, key)
+		}
+	}
+}
+
+// recordingNotifier records each notification attempt of the wrapped notifier in the delivery log.
+type recordingNotifier struct {
+	NotificationChannel
+	key string
+	log *deliveryLog
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := time.Now()
+	retry, err := n.NotificationChannel.Notify(ctx, alerts...)
+
+	attempt := apimodels.NotificationAttempt{
+		Time:     start,
+		Duration: time.Since(start).String(),
+		Status:   apimodels.NotificationAttemptSuccess,
+		Alerts:   len(alerts),
+	}
+	if groupKey, gkErr := notify.ExtractGroupKey(ctx); gkErr == nil {
+		attempt.GroupKey = groupKey.String()
+	}
+	if err != nil {
+		attempt.Status = apimodels.NotificationAttemptFailed
+		attempt.Error = err.Error()
+	}
+	n.log.record(n.key, attempt)
+
+	return retry, err
+}
+
+// GetReceiversStatus returns the delivery status of the integrations of the receivers of the configuration.
+func (am *Alertmanager) GetReceiversStatus() apimodels.GettableReceiversStatus {
+	am.reloadConfigMtx.RLock()
+	defer am.reloadConfigMtx.RUnlock()
+
+	res := apimodels.GettableReceiversStatus{}
+	if !am.ready() {
+		return res
+	}
+
+	for _, receiver := range am.config.AlertmanagerConfig.Receivers {
+		status := apimodels.GettableReceiverStatus{
+			Name:         receiver.Name,
+			Integrations: make([]apimodels.GettableIntegrationStatus, 0, len(receiver.GrafanaManagedReceivers)),
+		}
+		for i, integration := range receiver.GrafanaManagedReceivers {
+			integrationStatus := apimodels.GettableIntegrationStatus{
+				Name:         integration.Name,
+				UID:          integration.UID,
+				Type:         integration.Type,
+				SendResolved: !integration.DisableResolveMessage,
+				Attempts:     am.deliveryLog.get(deliveryLogKey(receiver.Name, integration, i)),
+			}
+			if len(integrationStatus.Attempts) > 0 {
+				last := integrationStatus.Attempts[0]
+				integrationStatus.LastNotifyAttempt = &last.Time
+				integrationStatus.LastNotifyAttemptDuration = last.Duration
+				integrationStatus.LastNotifyAttemptStatus = last.Status
+				integrationStatus.LastNotifyAttemptError = last.Error
+			}
+			status.Integrations = append(status.Integrations, integrationStatus)
+		}
+		res = append(res, status)
+	}
+	return res
+}
@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type fakeNotificationChannel struct {
+	err error
+}
+
+func (f *fakeNotificationChannel) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	return f.err != nil, f.err
+}
+
+func (f *fakeNotificationChannel) SendResolved() bool {
+	return true
+}
+
+func TestRecordingNotifier(t *testing.T) {
+	log := newDeliveryLog()
+	channel := &fakeNotificationChannel{}
+	n := &recordingNotifier{NotificationChannel: channel, key: "receiver/uid", log: log}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+
+	retry, err := n.Notify(ctx, &types.Alert{}, &types.Alert{})
+	require.NoError(t, err)
+	require.False(t, retry)
+
+	channel.err = errors.New("unexpected status code 403")
+	retry, err = n.Notify(ctx, &types.Alert{})
+	require.EqualError(t, err, "unexpected status code 403")
+	require.True(t, retry)
+
+	attempts := log.get("receiver/uid")
+	require.Len(t, attempts, 2)
+	require.Equal(t, apimodels.NotificationAttemptFailed, attempts[0].Status)
+	require.Equal(t, "unexpected status code 403", attempts[0].Error)
+	require.Equal(t, 1, attempts[0].Alerts)
+	require.Equal(t, "{}:{alertname=\"test\"}", attempts[0].GroupKey)
+	require.Equal(t, apimodels.NotificationAttemptSuccess, attempts[1].Status)
+	require.Empty(t, attempts[1].Error)
+	require.Equal(t, 2, attempts[1].Alerts)
+	require.False(t, attempts[0].Time.Before(attempts[1].Time))
+
+	for i := 0; i < 2*maxDeliveryLogAttempts; i++ {
+		_, _ = n.Notify(ctx, &types.Alert{})
+	}
+	require.Len(t, log.get("receiver/uid"), maxDeliveryLogAttempts)
+}
+
+func TestAlertmanager_GetReceiversStatus(t *testing.T) {
+	am := setupAMTest(t)
+	require.Empty(t, am.GetReceiversStatus())
+
+	applyConfig := func(receivers string) {
+		cfg, err := Load([]byte(fmt.Sprintf(`{
+			"alertmanager_config": {
+				"route": {"receiver": "team"},
+				"receivers": %s
+			}
+		}`, receivers)))
+		require.NoError(t, err)
+		require.NoError(t, am.SaveAndApplyConfig(context.Background(), cfg))
+	}
+	applyConfig(`[{
+		"name": "team",
+		"grafana_managed_receiver_configs": [
+			{"uid": "email-uid", "name": "email", "type": "email", "settings": {"addresses": "team@example.com"}},
+			{"uid": "webhook-uid", "name": "webhook", "type": "webhook", "disableResolveMessage": true, "settings": {"url": "http://localhost"}}
+		]
+	}]`)
+
+	am.deliveryLog.record("team/webhook-uid", apimodels.NotificationAttempt{
+		Duration: "1.5s",
+		Status:   apimodels.NotificationAttemptFailed,
+		Error:    "Webhook response status 403 Forbidden",
+		GroupKey: "group",
+		Alerts:   1,
+	})
+
+	status := am.GetReceiversStatus()
+	require.Len(t, status, 1)
+	require.Equal(t, "team", status[0].Name)
+	require.Len(t, status[0].Integrations, 2)
+
+	email := status[0].Integrations[0]
+	require.Equal(t, "email", email.Type)
+	require.True(t, email.SendResolved)
+	require.Nil(t, email.LastNotifyAttempt)
+	require.Empty(t, email.Attempts)
+
+	webhook := status[0].Integrations[1]
+	require.Equal(t, "webhook-uid", webhook.UID)
+	require.False(t, webhook.SendResolved)
+	require.NotNil(t, webhook.LastNotifyAttempt)
+	require.Equal(t, "1.5s", webhook.LastNotifyAttemptDuration)
+	require.Equal(t, apimodels.NotificationAttemptFailed, webhook.LastNotifyAttemptStatus)
+	require.Equal(t, "Webhook response status 403 Forbidden", webhook.LastNotifyAttemptError)
+	require.Len(t, webhook.Attempts, 1)
+
+	t.Run("the attempts of removed integrations are forgotten", func(t *testing.T) {
+		applyConfig(`[{
+			"name": "team",
+			"grafana_managed_receiver_configs": [
+				{"uid": "email-uid", "name": "email", "type": "email", "settings": {"addresses": "team@example.com"}}
+			]
+		}]`)
+		require.Empty(t, am.deliveryLog.get("team/webhook-uid"))
+	})
+}
@@ -320,6 +320,22 @@ func (moa *MultiOrgAlertmanager) AlertmanagerFor(orgID int64) (*Alertmanager, er
 	return orgAM, nil
 }
 
+// ClusterMembers returns the name of this Grafana instance in the high availability cluster and the names of
+// all the members of the cluster, including this instance. The name is empty when high availability is not configured.
+func (moa *MultiOrgAlertmanager) ClusterMembers() (string, []string) {
+	p, ok := moa.peer.(*cluster.Peer)
+	if !ok {
+		return "", nil
+	}
+
+	peers := p.Peers()
+	members := make([]string, 0, len(peers))
+	for _, m := range peers {
+		members = append(members, m.Name())
+	}
+	return p.Name(), members
+}
+
 // NilPeer and NilChannel implements the Alertmanager clustering interface.
 type NilPeer struct{}
 
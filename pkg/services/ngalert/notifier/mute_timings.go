@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// timeMuteStage mutes the alerts of the routes that are within one of their mute timings. It replaces the
+// notify.TimeMuteStage of the upstream Alertmanager, which evaluates the time intervals in UTC only, so that
+// the time intervals are evaluated in their own location and can have date ranges.
+type timeMuteStage struct {
+	muteTimes map[string][]apimodels.TimeInterval
+}
+
+func newTimeMuteStage(muteTimes map[string][]apimodels.TimeInterval) *timeMuteStage {
+	return &timeMuteStage{muteTimes: muteTimes}
+}
+
+// Exec implements the notify.Stage interface.
+func (s *timeMuteStage) Exec(ctx context.Context, l gokitlog.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	muteTimeIntervalNames, ok := notify.MuteTimeIntervalNames(ctx)
+	if !ok {
+		return ctx, alerts, nil
+	}
+	now, ok := notify.Now(ctx)
+	if !ok {
+		return ctx, alerts, fmt.Errorf("missing now timestamp")
+	}
+
+	for _, name := range muteTimeIntervalNames {
+		timeIntervals, ok := s.muteTimes[name]
+		if !ok {
+			return ctx, alerts, fmt.Errorf("mute time %s doesn't exist in config", name)
+		}
+		for _, ti := range timeIntervals {
+			if ti.ContainsTime(now) {
+				// If the current time is inside a mute time, all alerts are removed from the pipeline.
+				level.Debug(l).Log("msg", "Notifications not sent, route is within mute time", "mute_time", name)
+				return ctx, nil, nil
+			}
+		}
+	}
+	return ctx, alerts, nil
+}
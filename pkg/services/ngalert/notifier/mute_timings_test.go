@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestTimeMuteStage(t *testing.T) {
+	var muteTimeIntervals []apimodels.MuteTimeInterval
+	require.NoError(t, json.Unmarshal([]byte(`[{
+		"name": "outside business hours in Berlin",
+		"time_intervals": [
+			{"times": [{"start_time": "00:00", "end_time": "09:00"}, {"start_time": "17:00", "end_time": "24:00"}], "location": "Europe/Berlin"},
+			{"weekdays": ["saturday", "sunday"], "location": "Europe/Berlin"}
+		]
+	}, {
+		"name": "holidays",
+		"time_intervals": [{"date_ranges": [{"start_date": "2022-12-24", "end_date": "2022-12-26"}]}]
+	}]`), &muteTimeIntervals))
+
+	am := &Alertmanager{}
+	stage := newTimeMuteStage(am.buildMuteTimesMap(muteTimeIntervals))
+	alerts := []*types.Alert{{}}
+
+	for _, tc := range []struct {
+		desc      string
+		muteTimes []string
+		now       string
+		muted     bool
+		err       string
+	}{
+		{
+			desc:      "muted before business hours in Berlin",
+			muteTimes: []string{"outside business hours in Berlin"},
+			now:       "2022-01-10T07:30:00Z",
+			muted:     true,
+		},
+		{
+			desc:      "not muted during business hours in Berlin",
+			muteTimes: []string{"outside business hours in Berlin"},
+			now:       "2022-01-10T08:30:00Z",
+			muted:     false,
+		},
+		{
+			desc:      "muted on the weekend in Berlin",
+			muteTimes: []string{"outside business hours in Berlin"},
+			now:       "2022-01-15T12:00:00Z",
+			muted:     true,
+		},
+		{
+			desc:      "muted by any of the mute timings",
+			muteTimes: []string{"outside business hours in Berlin", "holidays"},
+			now:       "2022-12-26T12:00:00Z",
+			muted:     true,
+		},
+		{
+			desc:      "undefined mute timing",
+			muteTimes: []string{"undefined"},
+			now:       "2022-01-10T12:00:00Z",
+			err:       "mute time undefined doesn't exist in config",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tc.now)
+			require.NoError(t, err)
+			ctx := notify.WithMuteTimeIntervals(context.Background(), tc.muteTimes)
+			ctx = notify.WithNow(ctx, now)
+
+			_, res, err := stage.Exec(ctx, gokitlog.NewNopLogger(), alerts...)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.muted {
+				require.Empty(t, res)
+			} else {
+				require.Equal(t, alerts, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	tmpltext "text/template"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+)
+
+const (
+	// defaultTestTemplateName is the name of the tested template file when the request has none.
+	defaultTestTemplateName = "test.tmpl"
+	// testTemplateReceiver is the receiver name in the data of the tested templates.
+	testTemplateReceiver = "TestReceiver"
+)
+
+var (
+	ErrEmptyTemplate = errors.New("the template is empty")
+)
+
+// TestTemplate renders the templates defined in the template file of c with the alerts of c, or with the alerts
+// that are currently firing if c has none. The templates of the configuration can be used by the tested template file,
+// which replaces the template file with the same name, if any. When the template file does not define templates,
+// the file itself is rendered.
+func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*apimodels.TestTemplatesResults, error) {
+	if c.Template == "" {
+		return nil, ErrEmptyTemplate
+	}
+	name := c.Name
+	if name == "" {
+		name = defaultTestTemplateName
+	}
+	if name != filepath.Base(filepath.Clean(name)) {
+		return nil, fmt.Errorf("template file name '%s' is not valid", name)
+	}
+
+	res := &apimodels.TestTemplatesResults{
+		Results: []apimodels.TestTemplatesResult{},
+		Errors:  []apimodels.TestTemplatesErrorResult{},
+	}
+
+	// Parse the template file on its own first so that syntax errors are reported with the line of the tested file.
+	definitions, err := parseTestTemplate(name, c.Template)
+	if err != nil {
+		res.Errors = append(res.Errors, newTestTemplateError(name, apimodels.TemplateInvalidError, name, err))
+		return res, nil
+	}
+
+	tmpl, err := am.testTemplateWithConfig(name, c.Template)
+	if err != nil {
+		if errors.Is(err, ErrAlertmanagerNotReady) {
+			return nil, err
+		}
+		res.Errors = append(res.Errors, newTestTemplateError(name, apimodels.TemplateInvalidError, name, err))
+		return res, nil
+	}
+
+	alerts := am.testTemplateAlerts(c.Alerts)
+	ctx = notify.WithReceiverName(ctx, testTemplateReceiver)
+	ctx = notify.WithGroupLabels(ctx, commonLabels(alerts))
+	data := channels.ExtendData(notify.GetTemplateData(ctx, tmpl, alerts, am.logger), am.logger)
+
+	if len(definitions) == 0 {
+		// the file does not define templates so it is rendered as a whole
+		definitions = []string{name}
+	}
+	for _, definition := range definitions {
+		text, err := tmpl.ExecuteTextString(fmt.Sprintf(`{{ template %q . }}`, definition), data)
+		if err != nil {
+			res.Errors = append(res.Errors, newTestTemplateError(definition, apimodels.TemplateExecutionError, name, err))
+			continue
+		}
+		res.Results = append(res.Results, apimodels.TestTemplatesResult{Name: definition, Text: text})
+	}
+	return res, nil
+}
+
+// parseTestTemplate parses the template file and returns the names of the templates it defines.
+func parseTestTemplate(name, text string) ([]string, error) {
+	tmpl, err := tmpltext.New(name).Option("missingkey=zero").Funcs(tmpltext.FuncMap(template.DefaultFuncs)).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []string
+	for _, t := range tmpl.Templates() {
+		if t.Name() != name {
+			definitions = append(definitions, t.Name())
+		}
+	}
+	sort.Strings(definitions)
+	return definitions, nil
+}
+
+// testTemplateWithConfig returns the templates of the configuration, where the template file with the given name
+// is replaced by the tested one.
+func (am *Alertmanager) testTemplateWithConfig(name, text string) (*template.Template, error) {
+	dir, err := os.MkdirTemp("", "grafana-test-template-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			am.logger.Warn("failed to remove the directory of the tested template", "dir", dir, "err", err)
+		}
+	}()
+
+	am.reloadConfigMtx.RLock()
+	if !am.ready() {
+		am.reloadConfigMtx.RUnlock()
+		return nil, ErrAlertmanagerNotReady
+	}
+	files := make(map[string]string, len(am.config.TemplateFiles))
+	for n, content := range am.config.TemplateFiles {
+		if n != name {
+			files[n] = content
+		}
+	}
+	am.reloadConfigMtx.RUnlock()
+
+	paths := make([]string, 0, len(files)+1)
+	for n, content := range files {
+		path := filepath.Join(dir, n)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	// the tested file is parsed last so that its definitions replace those of the other files
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+		return nil, err
+	}
+	return am.templateFromPaths(append(paths, path)...)
+}
+
+// testTemplateAlerts returns the alerts of the request, or the alerts that are currently firing if there are none.
+// A test alert is returned when there are no firing alerts either.
+func (am *Alertmanager) testTemplateAlerts(postableAlerts []*amv2.PostableAlert) []*types.Alert {
+	now := time.Now()
+	alerts := make([]*types.Alert, 0, len(postableAlerts))
+	for _, a := range postableAlerts {
+		alert := &types.Alert{
+			Alert: model.Alert{
+				Labels:       model.LabelSet{},
+				Annotations:  model.LabelSet{},
+				StartsAt:     time.Time(a.StartsAt),
+				EndsAt:       time.Time(a.EndsAt),
+				GeneratorURL: a.GeneratorURL.String(),
+			},
+			UpdatedAt: now,
+		}
+		for k, v := range a.Labels {
+			if len(v) == 0 || k == ngmodels.NamespaceUIDLabel {
+				continue
+			}
+			alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+		}
+		for k, v := range a.Annotations {
+			if len(v) == 0 {
+				continue
+			}
+			alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+		}
+		if alert.StartsAt.IsZero() {
+			alert.StartsAt = now
+		}
+		alerts = append(alerts, alert)
+	}
+	if len(alerts) > 0 {
+		return alerts
+	}
+
+	pending := am.alerts.GetPending()
+	defer pending.Close()
+	for a := range pending.Next() {
+		if !a.Resolved() {
+			alerts = append(alerts, a)
+		}
+	}
+	if len(alerts) > 0 {
+		sort.Slice(alerts, func(i, j int) bool {
+			return alerts[i].Labels.Before(alerts[j].Labels)
+		})
+		return alerts
+	}
+
+	testAlert := newTestAlert(apimodels.TestReceiversConfigBodyParams{}, now, now)
+	return []*types.Alert{&testAlert}
+}
+
+// commonLabels returns the labels that all alerts have with the same value.
+func commonLabels(alerts []*types.Alert) model.LabelSet {
+	if len(alerts) == 0 {
+		return model.LabelSet{}
+	}
+	common := alerts[0].Labels.Clone()
+	for _, a := range alerts[1:] {
+		for k, v := range common {
+			if a.Labels[k] != v {
+				delete(common, k)
+			}
+		}
+	}
+	return common
+}
+
+// templateErrorLine matches the location that text/template adds to its errors,
+// for example "template: test.tmpl:3: unexpected EOF" or "template: test.tmpl:3:12: executing ...".
+var templateErrorLine = regexp.MustCompile(`^template: (.+?):(\d+)(?::\d+)?: `)
+
+func newTestTemplateError(name string, kind apimodels.TemplateErrorKind, file string, err error) apimodels.TestTemplatesErrorResult {
+	res := apimodels.TestTemplatesErrorResult{
+		Name:    name,
+		Kind:    kind,
+		Message: err.Error(),
+	}
+	// the line is only meaningful for the errors in the tested file, not in the other templates of the configuration
+	if m := templateErrorLine.FindStringSubmatch(err.Error()); m != nil && m[1] == file {
+		res.Line, _ = strconv.Atoi(m[2])
+	}
+	return res
+}
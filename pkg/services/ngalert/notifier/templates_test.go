@@ -0,0 +1,182 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+const testTemplatesConfig = `{
+	"template_files": {
+		"custom.tmpl": "{{ define \"custom.title\" }}{{ len .Alerts.Firing }} firing{{ end }}"
+	},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "grafana-default-email"
+		},
+		"receivers": [{
+			"name": "grafana-default-email",
+			"grafana_managed_receiver_configs": [{
+				"uid": "",
+				"name": "email receiver",
+				"type": "email",
+				"settings": {
+					"addresses": "<example@email.com>"
+				}
+			}]
+		}]
+	}
+}`
+
+func TestAlertmanager_TestTemplate(t *testing.T) {
+	am := setupAMTest(t)
+	am.Settings.AppURL = "http://localhost:3000/"
+	// the alerts put by the test are sent to the email receiver of the configuration
+	am.NotificationService = notifications.MockNotificationService()
+
+	cfg, err := Load([]byte(testTemplatesConfig))
+	require.NoError(t, err)
+	require.NoError(t, am.SaveAndApplyConfig(context.Background(), cfg))
+
+	alerts := []*amv2.PostableAlert{
+		{
+			Alert:       amv2.Alert{Labels: amv2.LabelSet{"alertname": "HighCPU", "instance": "a"}},
+			Annotations: amv2.LabelSet{"summary": "CPU is high"},
+		}, {
+			Alert:  amv2.Alert{Labels: amv2.LabelSet{"alertname": "HighCPU", "instance": "b"}},
+			EndsAt: strfmt.DateTime(time.Now().Add(-time.Minute)),
+		},
+	}
+
+	cases := []struct {
+		name      string
+		body      apimodels.TestTemplatesConfigBodyParams
+		expResult *apimodels.TestTemplatesResults
+		expError  error
+	}{
+		{
+			name: "renders the defined templates with the alerts of the request",
+			body: apimodels.TestTemplatesConfigBodyParams{
+				Alerts: alerts,
+				Template: `{{ define "test.alerts" }}{{ range .Alerts }}{{ .Labels.instance }}:{{ .Status }} {{ end }}{{ end }}
+{{ define "test.common" }}{{ .CommonLabels.alertname }} for {{ .Receiver }}{{ end }}`,
+			},
+			expResult: &apimodels.TestTemplatesResults{
+				Results: []apimodels.TestTemplatesResult{
+					{Name: "test.alerts", Text: "a:firing b:resolved "},
+					{Name: "test.common", Text: "HighCPU for TestReceiver"},
+				},
+				Errors: []apimodels.TestTemplatesErrorResult{},
+			},
+		}, {
+			name: "renders the file when it does not define templates",
+			body: apimodels.TestTemplatesConfigBodyParams{
+				Alerts:   alerts,
+				Name:     "message",
+				Template: `{{ template "custom.title" . }}: {{ (index .Alerts 0).Annotations.summary }}`,
+			},
+			expResult: &apimodels.TestTemplatesResults{
+				Results: []apimodels.TestTemplatesResult{
+					{Name: "message", Text: "1 firing: CPU is high"},
+				},
+				Errors: []apimodels.TestTemplatesErrorResult{},
+			},
+		}, {
+			name: "replaces the template file with the same name",
+			body: apimodels.TestTemplatesConfigBodyParams{
+				Alerts:   alerts,
+				Name:     "custom.tmpl",
+				Template: `{{ define "custom.title" }}{{ len .Alerts.Resolved }} resolved{{ end }}`,
+			},
+			expResult: &apimodels.TestTemplatesResults{
+				Results: []apimodels.TestTemplatesResult{
+					{Name: "custom.title", Text: "1 resolved"},
+				},
+				Errors: []apimodels.TestTemplatesErrorResult{},
+			},
+		}, {
+			name: "uses a test alert when there are no alerts",
+			body: apimodels.TestTemplatesConfigBodyParams{
+				Template: `{{ range .Alerts }}{{ .Labels.alertname }}{{ end }}`,
+			},
+			expResult: &apimodels.TestTemplatesResults{
+				Results: []apimodels.TestTemplatesResult{
+					{Name: "test.tmpl", Text: "TestAlert"},
+				},
+				Errors: []apimodels.TestTemplatesErrorResult{},
+			},
+		}, {
+			name: "returns the line of syntax errors",
+			body: apimodels.TestTemplatesConfigBodyParams{
+				Name:     "broken.tmpl",
+				Template: "{{ define \"broken\" }}\n{{ range .Alerts }}\n{{ .Labels }\n{{ end }}{{ end }}",
+			},
+			expResult: &apimodels.TestTemplatesResults{
+				Results: []apimodels.TestTemplatesResult{},
+				Errors: []apimodels.TestTemplatesErrorResult{
+					{
+						Name:    "broken.tmpl",
+						Kind:    apimodels.TemplateInvalidError,
+						Line:    3,
+						Message: `template: broken.tmpl:3: unexpected "}" in operand`,
+					},
+				},
+			},
+		}, {
+			name: "returns the errors of the templates that fail to execute",
+			body: apimodels.TestTemplatesConfigBodyParams{
+				Alerts:   alerts,
+				Template: "{{ define \"ok\" }}ok{{ end }}\n{{ define \"failing\" }}\n{{ template \"missing\" . }}{{ end }}",
+			},
+			expResult: &apimodels.TestTemplatesResults{
+				Results: []apimodels.TestTemplatesResult{
+					{Name: "ok", Text: "ok"},
+				},
+				Errors: []apimodels.TestTemplatesErrorResult{
+					{
+						Name:    "failing",
+						Kind:    apimodels.TemplateExecutionError,
+						Line:    3,
+						Message: `template: test.tmpl:3:12: executing "failing" at <{{template "missing" .}}>: template "missing" not defined`,
+					},
+				},
+			},
+		}, {
+			name:     "returns an error when the template is empty",
+			body:     apimodels.TestTemplatesConfigBodyParams{Name: "empty.tmpl"},
+			expError: ErrEmptyTemplate,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := am.TestTemplate(context.Background(), c.body)
+			if c.expError != nil {
+				require.ErrorIs(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expResult, res)
+		})
+	}
+
+	t.Run("uses the alerts that are currently firing", func(t *testing.T) {
+		require.NoError(t, am.PutAlerts(apimodels.PostableAlerts{PostableAlerts: []amv2.PostableAlert{
+			{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "Firing"}}},
+			{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "Resolved"}}, EndsAt: strfmt.DateTime(time.Now().Add(-time.Minute))},
+		}}))
+
+		res, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+			Template: `{{ range .Alerts }}{{ .Labels.alertname }} {{ end }}`,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []apimodels.TestTemplatesResult{{Name: "test.tmpl", Text: "Firing "}}, res.Results)
+	})
+}
@@ -52,6 +52,18 @@ func (f *FakeConfigStore) SaveAlertmanagerConfiguration(_ context.Context, cmd *
 	return nil
 }
 
+func (f *FakeConfigStore) GetImage(_ context.Context, _ string) (*models.Image, error) {
+	return nil, models.ErrImageNotFound
+}
+
+func (f *FakeConfigStore) SaveImage(_ context.Context, _ *models.Image) error {
+	return nil
+}
+
+func (f *FakeConfigStore) DeleteExpiredImages(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
 func (f *FakeConfigStore) SaveAlertmanagerConfigurationWithCallback(_ context.Context, cmd *models.SaveAlertmanagerConfigurationCmd, callback store.SaveCallback) error {
 	f.configs[cmd.OrgID] = &models.AlertConfiguration{
 		AlertmanagerConfiguration: cmd.AlertmanagerConfiguration,
@@ -27,6 +27,7 @@ const (
 
 // stateToPostableAlert converts a state to a model that is accepted by Alertmanager. Annotations and Labels are copied from the state.
 // - if state has at least one result, a new label '__value_string__' is added to the label set
+// - if state has an image, its token and URL are added as '__alertImageToken__' and '__alertImageURL__'
 // - the alert's GeneratorURL is constructed to point to the alert edit page
 // - if evaluation state is either NoData or Error, the resulting set of labels is changed:
 //   - original alert name (label: model.AlertNameLabel) is backed up to OriginalAlertName
@@ -39,6 +40,13 @@ func stateToPostableAlert(alertState *state.State, appURL *url.URL) *models.Post
 		nA["__value_string__"] = alertState.LastEvaluationString
 	}
 
+	if alertState.Image != nil {
+		nA[ngModels.ImageTokenAnnotation] = alertState.Image.Token
+		if alertState.Image.HasURL() {
+			nA[ngModels.ImageURLAnnotation] = alertState.Image.URL
+		}
+	}
+
 	var urlStr string
 	if uid := nL[ngModels.RuleUIDLabel]; len(uid) > 0 && appURL != nil {
 		u := *appURL
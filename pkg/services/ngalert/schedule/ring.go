@@ -0,0 +1,95 @@
+package schedule
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ringTokensPerMember is the number of tokens every member of the cluster owns on the ring.
+// More tokens spread the alert rules more evenly across members.
+const ringTokensPerMember = 128
+
+// ClusterMembership provides the members of the high availability cluster
+// across which the evaluation of alert rules is sharded.
+type ClusterMembership interface {
+	// ClusterMembers returns the name of this Grafana instance and the names of all the members
+	// of the cluster, including this instance. The name is empty when there is no cluster.
+	ClusterMembers() (string, []string)
+}
+
+// ruleRing is a consistent hash ring that assigns each rule group to exactly one member of the cluster, so that
+// the rules of a group are evaluated together. When a member joins or leaves the cluster only the groups next to
+// its tokens move to another member.
+type ruleRing struct {
+	self string
+	// membersKey identifies the set of members the ring was built with.
+	membersKey string
+	tokens     []uint64
+	owners     map[uint64]string
+}
+
+func newRuleRing(self string, members []string) *ruleRing {
+	sorted := make([]string, len(members))
+	copy(sorted, members)
+	sort.Strings(sorted)
+
+	r := &ruleRing{
+		self:       self,
+		membersKey: ringMembersKey(members),
+		tokens:     make([]uint64, 0, len(sorted)*ringTokensPerMember),
+		owners:     make(map[uint64]string, len(sorted)*ringTokensPerMember),
+	}
+	for _, member := range sorted {
+		for i := 0; i < ringTokensPerMember; i++ {
+			token := hashRingKey(fmt.Sprintf("%s-%d", member, i))
+			if _, ok := r.owners[token]; ok {
+				continue
+			}
+			r.owners[token] = member
+			r.tokens = append(r.tokens, token)
+		}
+	}
+	sort.Slice(r.tokens, func(i, j int) bool { return r.tokens[i] < r.tokens[j] })
+	return r
+}
+
+// owner returns the member of the cluster that evaluates the rules of the group.
+// It returns an empty string if the ring has no members.
+func (r *ruleRing) owner(key models.AlertRuleGroupKey) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := hashRingKey(fmt.Sprintf("%d-%s-%s", key.OrgID, key.NamespaceUID, key.RuleGroup))
+	idx := sort.Search(len(r.tokens), func(i int) bool { return r.tokens[i] >= h })
+	if idx == len(r.tokens) {
+		idx = 0
+	}
+	return r.owners[r.tokens[idx]]
+}
+
+// owns returns true if the rules of the group must be evaluated by this instance.
+// An empty ring owns all rule groups.
+func (r *ruleRing) owns(key models.AlertRuleGroupKey) bool {
+	owner := r.owner(key)
+	return owner == "" || owner == r.self
+}
+
+// ringMembersKey returns a key that identifies a set of members regardless of their order.
+func ringMembersKey(members []string) string {
+	sorted := make([]string, len(members))
+	copy(sorted, members)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// hashRingKey hashes tokens and rule groups onto the ring. A cryptographic hash is used because the
+// tokens of a member only differ by a suffix, and they still must be spread evenly across the ring.
+func hashRingKey(s string) uint64 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint64(sum[:8])
+}
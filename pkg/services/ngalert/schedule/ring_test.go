@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRuleRing(t *testing.T) {
+	keys := make([]models.AlertRuleGroupKey, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, models.AlertRuleGroupKey{OrgID: int64(i%3 + 1), NamespaceUID: fmt.Sprintf("folder-%d", i%7), RuleGroup: fmt.Sprintf("group-%d", i)})
+	}
+
+	t.Run("each rule group is owned by exactly one member", func(t *testing.T) {
+		members := []string{"grafana-0", "grafana-1", "grafana-2"}
+		rings := make([]*ruleRing, 0, len(members))
+		for _, m := range members {
+			rings = append(rings, newRuleRing(m, members))
+		}
+
+		owned := map[string]int{}
+		for _, key := range keys {
+			owners := 0
+			for _, r := range rings {
+				if r.owns(key) {
+					owners++
+					owned[r.self]++
+				}
+			}
+			require.Equal(t, 1, owners, key.String())
+		}
+
+		// the rule groups are spread across all the members.
+		for _, m := range members {
+			require.Greater(t, owned[m], len(keys)/6, m)
+		}
+	})
+
+	t.Run("the order of the members does not change the owner", func(t *testing.T) {
+		a := newRuleRing("grafana-0", []string{"grafana-0", "grafana-1", "grafana-2"})
+		b := newRuleRing("grafana-0", []string{"grafana-2", "grafana-0", "grafana-1"})
+		require.Equal(t, a.membersKey, b.membersKey)
+		for _, key := range keys {
+			require.Equal(t, a.owner(key), b.owner(key))
+		}
+	})
+
+	t.Run("only the rule groups of a new member move", func(t *testing.T) {
+		before := newRuleRing("grafana-0", []string{"grafana-0", "grafana-1"})
+		after := newRuleRing("grafana-0", []string{"grafana-0", "grafana-1", "grafana-2"})
+		for _, key := range keys {
+			if owner := after.owner(key); owner != "grafana-2" {
+				require.Equal(t, before.owner(key), owner)
+			}
+		}
+	})
+
+	t.Run("empty ring owns all rule groups", func(t *testing.T) {
+		r := newRuleRing("grafana-0", nil)
+		for _, key := range keys {
+			require.True(t, r.owns(key))
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"sync"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 
 	"github.com/benbjohnson/clock"
 	"golang.org/x/sync/errgroup"
@@ -85,6 +87,9 @@ type schedule struct {
 
 	stateManager *state.Manager
 
+	// recordingWriter writes the results of recording rules.
+	recordingWriter writer.Writer
+
 	appURL *url.URL
 
 	multiOrgNotifier *notifier.MultiOrgAlertmanager
@@ -98,6 +103,15 @@ type schedule struct {
 	adminConfigPollInterval time.Duration
 	disabledOrgs            map[int64]struct{}
 	minRuleInterval         time.Duration
+
+	// membership is used to shard the evaluation of alert rules across the members
+	// of the high availability cluster. When nil, this instance evaluates all alert rules.
+	membership ClusterMembership
+	ringMtx    sync.RWMutex
+	ring       *ruleRing
+	// releasedRules are the alert rules evaluated by other members of the cluster.
+	// It is only accessed from schedulePeriodic.
+	releasedRules map[models.AlertRuleKey]struct{}
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -118,12 +132,20 @@ type SchedulerCfg struct {
 	AdminConfigPollInterval time.Duration
 	DisabledOrgs            map[int64]struct{}
 	MinRuleInterval         time.Duration
+	RecordingWriter         writer.Writer
+	// ClusterMembership enables sharding the evaluation of alert rules across the members of the cluster.
+	ClusterMembership ClusterMembership
 }
 
 // NewScheduler returns a new schedule.
 func NewScheduler(cfg SchedulerCfg, expressionService *expr.Service, appURL *url.URL, stateManager *state.Manager) *schedule {
 	ticker := alerting.NewTicker(cfg.C.Now(), time.Second*0, cfg.C, int64(cfg.BaseInterval.Seconds()))
 
+	recordingWriter := cfg.RecordingWriter
+	if recordingWriter == nil {
+		recordingWriter = writer.NoopWriter{}
+	}
+
 	sch := schedule{
 		registry:                alertRuleRegistry{alertRuleInfo: make(map[models.AlertRuleKey]*alertRuleInfo)},
 		maxAttempts:             cfg.MaxAttempts,
@@ -149,6 +171,9 @@ func NewScheduler(cfg SchedulerCfg, expressionService *expr.Service, appURL *url
 		adminConfigPollInterval: cfg.AdminConfigPollInterval,
 		disabledOrgs:            cfg.DisabledOrgs,
 		minRuleInterval:         cfg.MinRuleInterval,
+		recordingWriter:         recordingWriter,
+		membership:              cfg.ClusterMembership,
+		releasedRules:           map[models.AlertRuleKey]struct{}{},
 	}
 	return &sch
 }
@@ -380,6 +405,10 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 			alertRules := sch.getAlertRules(ctx, disabledOrgs)
 			sch.log.Debug("alert rules fetched", "count", len(alertRules), "disabled_orgs", disabledOrgs)
 
+			sch.updateRing()
+			releasedRules := make(map[models.AlertRuleKey]struct{})
+			schedulableRules := 0
+
 			// registeredDefinitions is a map used for finding deleted alert rules
 			// initially it is assigned to all known alert rules from the previous cycle
 			// each alert rule found also in this cycle is removed
@@ -392,22 +421,71 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 				version  int64
 			}
 
-			readyToRun := make([]readyToRunItem, 0)
+			// the rules of a group are evaluated one after the other, in their order in the group,
+			// at the interval of the group and with the same evaluation time.
+			type readyToRunGroup struct {
+				key   models.AlertRuleGroupKey
+				items []readyToRunItem
+			}
+
+			// the interval of a group is the one of its first rule. Rules of the same group
+			// have the same interval unless they were created before groups had one.
+			groupIntervals := make(map[models.AlertRuleGroupKey]int64)
+			readyGroups := make(map[models.AlertRuleGroupKey]*readyToRunGroup)
+			readyToRun := make([]*readyToRunGroup, 0)
+
+			sort.SliceStable(alertRules, func(i, j int) bool {
+				return alertRules[i].RuleGroupIndex < alertRules[j].RuleGroupIndex
+			})
 			for _, item := range alertRules {
+				// paused rules are not evaluated. They are left in registeredDefinitions
+				// so that their routines are stopped and their states are cleared.
+				if item.IsPaused {
+					continue
+				}
 				key := item.GetKey()
+				groupKey := item.GetGroupKey()
+
+				// rules of groups owned by another member of the cluster are not evaluated by this instance.
+				// They are removed from registeredDefinitions so that their alerts are not resolved.
+				if !sch.ownsGroup(groupKey) {
+					if _, ok := sch.releasedRules[key]; !ok {
+						sch.releaseAlertRule(key)
+					}
+					releasedRules[key] = struct{}{}
+					delete(registeredDefinitions, key)
+					continue
+				}
+				_, acquired := sch.releasedRules[key]
+				schedulableRules++
+
 				itemVersion := item.Version
 				ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
+				if interval, ok := groupIntervals[groupKey]; ok {
+					if item.IntervalSeconds != interval {
+						sch.log.Debug("interval adjusted to the one of the rule group", "rule_interval_seconds", item.IntervalSeconds, "group_interval_seconds", interval, "key", key, "group", groupKey)
+					}
+					item.IntervalSeconds = interval
+				}
+
 				// enforce minimum evaluation interval
 				if item.IntervalSeconds < int64(sch.minRuleInterval.Seconds()) {
 					sch.log.Debug("interval adjusted", "rule_interval_seconds", item.IntervalSeconds, "min_interval_seconds", sch.minRuleInterval.Seconds(), "key", key)
 					item.IntervalSeconds = int64(sch.minRuleInterval.Seconds())
 				}
 
+				groupIntervals[groupKey] = item.IntervalSeconds
+
 				invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
 				if newRoutine && !invalidInterval {
 					dispatcherGroup.Go(func() error {
+						// the rule was evaluated by another member of the cluster until now,
+						// so its state in the cache is out of date.
+						if acquired {
+							sch.stateManager.WarmRule(ruleInfo.ctx, key)
+						}
 						return sch.ruleRoutine(ruleInfo.ctx, key, ruleInfo.evalCh, ruleInfo.updateCh)
 					})
 				}
@@ -421,25 +499,35 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 
 				itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
 				if item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
-					readyToRun = append(readyToRun, readyToRunItem{key: key, ruleInfo: ruleInfo, version: itemVersion})
+					group, ok := readyGroups[groupKey]
+					if !ok {
+						group = &readyToRunGroup{key: groupKey}
+						readyGroups[groupKey] = group
+						readyToRun = append(readyToRun, group)
+					}
+					group.items = append(group.items, readyToRunItem{key: key, ruleInfo: ruleInfo, version: itemVersion})
 				}
 
 				// remove the alert rule from the registered alert rules
 				delete(registeredDefinitions, key)
 			}
 
+			// the evaluation of the groups is spread over the base interval
 			var step int64 = 0
 			if len(readyToRun) > 0 {
 				step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
 			}
 
 			for i := range readyToRun {
-				item := readyToRun[i]
+				group := readyToRun[i]
 
 				time.AfterFunc(time.Duration(int64(i)*step), func() {
-					success := item.ruleInfo.eval(tick, item.version)
-					if !success {
-						sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", "uid", item.key.UID, "org", item.key.OrgID, "time", tick)
+					for _, item := range group.items {
+						// wait for the evaluation of the rule before evaluating the next one
+						success := item.ruleInfo.evalAndWait(tick, item.version)
+						if !success {
+							sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", "uid", item.key.UID, "org", item.key.OrgID, "group", group.key, "time", tick)
+						}
 					}
 				})
 			}
@@ -448,6 +536,8 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 			for key := range registeredDefinitions {
 				sch.DeleteAlertRule(key)
 			}
+			sch.releasedRules = releasedRules
+			sch.metrics.SchedulableRules.Set(float64(schedulableRules))
 
 			sch.metrics.SchedulePeriodicDuration.Observe(time.Since(start).Seconds())
 		case <-ctx.Done():
@@ -545,6 +635,21 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 		logger := logger.New("version", alertRule.Version, "attempt", attempt, "now", evalCtx.now)
 		start := sch.clock.Now()
 
+		if alertRule.IsRecordingRule() {
+			written, err := sch.evaluateRecordingRule(ctx, alertRule, evalCtx.now)
+			dur := sch.clock.Now().Sub(start)
+			evalTotal.Inc()
+			evalDuration.Observe(dur.Seconds())
+			sch.stateManager.ProcessRecordingResult(alertRule, evalCtx.now, dur, written, err)
+			if err != nil {
+				evalTotalFailures.Inc()
+				logger.Error("failed to evaluate recording rule", "duration", dur, "err", err)
+				return err
+			}
+			logger.Debug("recording rule evaluated", "series", written, "duration", dur)
+			return nil
+		}
+
 		condition := models.Condition{
 			Condition: alertRule.Condition,
 			OrgID:     alertRule.OrgID,
@@ -609,6 +714,9 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 				return nil
 			}
 			if evalRunning {
+				if ctx.done != nil {
+					close(ctx.done)
+				}
 				continue
 			}
 
@@ -617,6 +725,9 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 				defer func() {
 					evalRunning = false
 					sch.evalApplied(key, ctx.now)
+					if ctx.done != nil {
+						close(ctx.done)
+					}
 				}()
 
 				err := retryIfError(func(attempt int64) error {
@@ -636,13 +747,72 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 				}
 			}()
 		case <-grafanaCtx.Done():
-			clearState()
+			if currentRule == nil || sch.ownsGroup(currentRule.GetGroupKey()) {
+				clearState()
+			} else {
+				// the rule is now evaluated by another member of the cluster that takes over its alerts.
+				sch.stateManager.RemoveByRuleUID(key.OrgID, key.UID)
+			}
 			logger.Debug("stopping alert rule routine")
 			return nil
 		}
 	}
 }
 
+// updateRing rebuilds the ring used to shard the evaluation of alert rules when the members of the cluster change.
+func (sch *schedule) updateRing() {
+	if sch.membership == nil {
+		return
+	}
+	self, members := sch.membership.ClusterMembers()
+	if self == "" {
+		return
+	}
+
+	sch.ringMtx.Lock()
+	defer sch.ringMtx.Unlock()
+	if sch.ring != nil && sch.ring.membersKey == ringMembersKey(members) {
+		return
+	}
+	sch.ring = newRuleRing(self, members)
+	sch.log.Info("cluster members changed, alert rules are redistributed", "self", self, "members", sch.ring.membersKey)
+}
+
+// ownsGroup returns true if the rules of the group are evaluated by this instance.
+func (sch *schedule) ownsGroup(key models.AlertRuleGroupKey) bool {
+	sch.ringMtx.RLock()
+	defer sch.ringMtx.RUnlock()
+	return sch.ring == nil || sch.ring.owns(key)
+}
+
+// releaseAlertRule stops the evaluation of a rule that is now evaluated by another member of the cluster.
+// Unlike DeleteAlertRule, the alerts of the rule are not resolved and its state is left in the database for the new owner.
+func (sch *schedule) releaseAlertRule(key models.AlertRuleKey) {
+	if ruleInfo, ok := sch.registry.del(key); ok {
+		ruleInfo.stop()
+	}
+	sch.stateManager.RemoveByRuleUID(key.OrgID, key.UID)
+}
+
+// evaluateRecordingRule executes the queries and expressions of a recording rule and writes
+// the results of the query or expression referenced by the rule. It returns the number of series written.
+func (sch *schedule) evaluateRecordingRule(ctx context.Context, alertRule *models.AlertRule, now time.Time) (int, error) {
+	resp, err := sch.evaluator.QueriesAndExpressionsEval(alertRule.OrgID, alertRule.Data, now, sch.expressionService)
+	if err != nil {
+		return 0, err
+	}
+
+	res, ok := resp.Responses[alertRule.Record.From]
+	if !ok {
+		return 0, fmt.Errorf("no results found for query or expression %s", alertRule.Record.From)
+	}
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to execute query or expression %s: %w", alertRule.Record.From, res.Error)
+	}
+
+	return sch.recordingWriter.Write(ctx, alertRule.OrgID, alertRule.Record.Metric, now, res.Frames, alertRule.Labels)
+}
+
 func (sch *schedule) saveAlertStates(ctx context.Context, states []*state.State) {
 	sch.log.Debug("saving alert states", "count", len(states))
 	for _, s := range states {
@@ -765,6 +935,28 @@ func (a *alertRuleInfo) eval(t time.Time, version int64) bool {
 	}
 }
 
+// evalAndWait signals the rule evaluation routine to perform the evaluation of the rule and waits until the evaluation
+// is done. Returns false if the loop is stopped before the evaluation is done.
+func (a *alertRuleInfo) evalAndWait(t time.Time, version int64) bool {
+	done := make(chan struct{})
+	select {
+	case a.evalCh <- &evalContext{
+		now:     t,
+		version: version,
+		done:    done,
+	}:
+	case <-a.ctx.Done():
+		return false
+	}
+
+	select {
+	case <-done:
+		return true
+	case <-a.ctx.Done():
+		return false
+	}
+}
+
 // update signals the rule evaluation routine to update the internal state. Does nothing if the loop is stopped
 func (a *alertRuleInfo) update() bool {
 	select {
@@ -778,6 +970,8 @@ func (a *alertRuleInfo) update() bool {
 type evalContext struct {
 	now     time.Time
 	version int64
+	// done is closed when the evaluation is done, if set.
+	done chan struct{}
 }
 
 // overrideCfg is only used on tests.
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests"
 
 	"github.com/benbjohnson/clock"
@@ -105,7 +106,7 @@ func TestWarmStateCache(t *testing.T) {
 		Metrics:                 testMetrics.GetSchedulerMetrics(),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, testMetrics.GetStateMetrics(), nil, dbstore, dbstore, ng.SQLStore)
+	st := state.NewManager(schedCfg.Logger, testMetrics.GetStateMetrics(), nil, dbstore, dbstore, nil, nil, ng.SQLStore)
 	st.Warm(ctx)
 
 	t.Run("instance cache has expected entries", func(t *testing.T) {
@@ -119,6 +120,20 @@ func TestWarmStateCache(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("warming a single rule restores its entries", func(t *testing.T) {
+		st.ResetCache()
+		st.WarmRule(ctx, rule.GetKey())
+		for _, entry := range expectedEntries {
+			cacheEntry, err := st.Get(entry.OrgID, entry.AlertRuleUID, entry.CacheId)
+			require.NoError(t, err)
+
+			if diff := cmp.Diff(entry, cacheEntry, cmpopts.IgnoreFields(state.State{}, "Results")); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				t.FailNow()
+			}
+		}
+	})
 }
 
 func TestAlertingTicker(t *testing.T) {
@@ -157,7 +172,7 @@ func TestAlertingTicker(t *testing.T) {
 			disabledOrgID: {},
 		},
 	}
-	st := state.NewManager(schedCfg.Logger, testMetrics.GetStateMetrics(), nil, dbstore, dbstore, ng.SQLStore)
+	st := state.NewManager(schedCfg.Logger, testMetrics.GetStateMetrics(), nil, dbstore, dbstore, nil, nil, ng.SQLStore)
 	appUrl := &url.URL{
 		Scheme: "http",
 		Host:   "localhost",
@@ -237,6 +252,38 @@ func TestAlertingTicker(t *testing.T) {
 		tick := advanceClock(t, mockedClock)
 		assertEvalRun(t, evalAppliedCh, tick, expectedAlertRulesEvaluated...)
 	})
+
+	setPaused := func(rule *models.AlertRule, paused bool) {
+		q := models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID}
+		require.NoError(t, dbstore.GetAlertRuleByUID(ctx, &q))
+		updated := *q.Result
+		updated.IsPaused = paused
+		require.NoError(t, dbstore.UpsertAlertRules(ctx, []store.UpsertRule{{Existing: q.Result, New: updated}}))
+	}
+
+	// pause the alert rule with one second interval under main org
+	setPaused(alerts[2], true)
+	t.Logf("alert rule: %v paused", alerts[2].GetKey())
+
+	expectedAlertRulesEvaluated = []models.AlertRuleKey{alerts[1].GetKey()}
+	t.Run(fmt.Sprintf("on 9th tick alert rules: %s should be evaluated", concatenate(expectedAlertRulesEvaluated)), func(t *testing.T) {
+		tick := advanceClock(t, mockedClock)
+		assertEvalRun(t, evalAppliedCh, tick, expectedAlertRulesEvaluated...)
+	})
+	expectedAlertRulesStopped = []models.AlertRuleKey{alerts[2].GetKey()}
+	t.Run(fmt.Sprintf("on 9th tick alert rules: %s should be stopped", concatenate(expectedAlertRulesStopped)), func(t *testing.T) {
+		assertStopRun(t, stopAppliedCh, expectedAlertRulesStopped...)
+	})
+
+	// resume the alert rule
+	setPaused(alerts[2], false)
+	t.Logf("alert rule: %v resumed", alerts[2].GetKey())
+
+	expectedAlertRulesEvaluated = []models.AlertRuleKey{alerts[2].GetKey()}
+	t.Run(fmt.Sprintf("on 10th tick alert rules: %s should be evaluated", concatenate(expectedAlertRulesEvaluated)), func(t *testing.T) {
+		tick := advanceClock(t, mockedClock)
+		assertEvalRun(t, evalAppliedCh, tick, expectedAlertRulesEvaluated...)
+	})
 }
 
 func assertEvalRun(t *testing.T, ch <-chan evalAppliedInfo, tick time.Time, keys ...models.AlertRuleKey) {
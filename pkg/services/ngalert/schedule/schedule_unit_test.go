@@ -988,6 +988,193 @@ func TestSchedule_DeleteAlertRule(t *testing.T) {
 	})
 }
 
+type fakeClusterMembership struct {
+	mtx     sync.Mutex
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembership) ClusterMembers() (string, []string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.self, f.members
+}
+
+func (f *fakeClusterMembership) setMembers(members ...string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.members = members
+}
+
+func TestSchedule_sharding(t *testing.T) {
+	ruleStore := newFakeRuleStore(t)
+	rules := make([]*models.AlertRule, 0, 10)
+	keys := make([]models.AlertRuleKey, 0, 10)
+	for i := 0; i < 10; i++ {
+		rule := CreateTestAlertRule(t, ruleStore, 1, 1, eval.Normal)
+		rules = append(rules, rule)
+		keys = append(keys, rule.GetKey())
+	}
+
+	sch, mockedClock := setupScheduler(t, ruleStore, &FakeInstanceStore{}, newFakeAdminConfigStore(t), nil)
+	membership := &fakeClusterMembership{self: "grafana-0", members: []string{"grafana-0", "grafana-1"}}
+	sch.membership = membership
+	evalAppliedCh := make(chan models.AlertRuleKey, 100)
+	sch.evalAppliedFunc = func(key models.AlertRuleKey, _ time.Time) {
+		evalAppliedCh <- key
+	}
+
+	ring := newRuleRing("grafana-0", []string{"grafana-0", "grafana-1"})
+	owned := map[models.AlertRuleKey]struct{}{}
+	for _, rule := range rules {
+		if ring.owns(rule.GetGroupKey()) {
+			owned[rule.GetKey()] = struct{}{}
+		}
+	}
+	require.NotEmpty(t, owned)
+	require.Less(t, len(owned), len(keys))
+
+	all := make(map[models.AlertRuleKey]struct{}, len(keys))
+	for _, key := range keys {
+		all[key] = struct{}{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		_ = sch.Run(ctx)
+	}()
+
+	// tick advances the clock and returns the rules that were evaluated.
+	tick := func(t *testing.T, expected int) map[models.AlertRuleKey]struct{} {
+		t.Helper()
+		mockedClock.Add(time.Second)
+		evaluated := map[models.AlertRuleKey]struct{}{}
+		timeout := time.After(5 * time.Second)
+		for len(evaluated) < expected {
+			select {
+			case key := <-evalAppliedCh:
+				evaluated[key] = struct{}{}
+			case <-timeout:
+				t.Fatalf("expected %d evaluations but got %d", expected, len(evaluated))
+			}
+		}
+		return evaluated
+	}
+
+	t.Run("only the rules owned by the instance are evaluated", func(t *testing.T) {
+		require.Equal(t, owned, tick(t, len(owned)))
+	})
+
+	t.Run("rules of a member that left the cluster are taken over", func(t *testing.T) {
+		membership.setMembers("grafana-0")
+		require.Equal(t, all, tick(t, len(all)))
+	})
+
+	t.Run("rules of a member that joined the cluster are released", func(t *testing.T) {
+		membership.setMembers("grafana-0", "grafana-1")
+		require.Equal(t, owned, tick(t, len(owned)))
+		for _, key := range keys {
+			_, ok := owned[key]
+			require.Equal(t, ok, sch.registry.exists(key))
+		}
+	})
+}
+
+func TestSchedule_ruleGroups(t *testing.T) {
+	ruleStore := newFakeRuleStore(t)
+	rules := make([]apimodels.PostableExtendedRuleNode, 0, 5)
+	for i := 0; i < 5; i++ {
+		rules = append(rules, apimodels.PostableExtendedRuleNode{
+			ApiRuleNode: &apimodels.ApiRuleNode{},
+			GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+				Title:     fmt.Sprintf("rule %d", i),
+				Condition: "A",
+				Data: []models.AlertQuery{
+					{
+						DatasourceUID: "-100",
+						Model:         json.RawMessage(`{"datasourceUid": "-100", "type":"math", "expression":"2 + 2 > 1"}`),
+						RefID:         "A",
+					},
+				},
+			},
+		})
+	}
+	require.NoError(t, ruleStore.UpdateRuleGroup(context.Background(), store.UpdateRuleGroupCmd{
+		OrgID:        1,
+		NamespaceUID: "namespace",
+		RuleGroupConfig: apimodels.PostableRuleGroupConfig{
+			Name:     "group",
+			Interval: model.Duration(2 * time.Second),
+			Rules:    rules,
+		},
+	}))
+	q := models.ListRuleGroupAlertRulesQuery{OrgID: 1, NamespaceUID: "namespace", RuleGroup: "group"}
+	require.NoError(t, ruleStore.GetRuleGroupAlertRules(context.Background(), &q))
+	require.Len(t, q.Result, len(rules))
+	expected := make([]models.AlertRuleKey, 0, len(q.Result))
+	for _, r := range q.Result {
+		expected = append(expected, r.GetKey())
+	}
+	// the interval of the group is the one of its first rule
+	q.Result[3].IntervalSeconds = 1
+
+	sch, mockedClock := setupScheduler(t, ruleStore, &FakeInstanceStore{}, newFakeAdminConfigStore(t), nil)
+	type evaluation struct {
+		key models.AlertRuleKey
+		now time.Time
+	}
+	evalAppliedCh := make(chan evaluation, 100)
+	sch.evalAppliedFunc = func(key models.AlertRuleKey, now time.Time) {
+		evalAppliedCh <- evaluation{key: key, now: now}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		_ = sch.Run(ctx)
+	}()
+
+	t.Run("the rules of a group are evaluated in order with the same evaluation time", func(t *testing.T) {
+		mockedClock.Add(2 * time.Second)
+		var evaluated []models.AlertRuleKey
+		var times []time.Time
+		timeout := time.After(5 * time.Second)
+		for len(evaluated) < len(expected) {
+			select {
+			case e := <-evalAppliedCh:
+				evaluated = append(evaluated, e.key)
+				times = append(times, e.now)
+			case <-timeout:
+				t.Fatalf("expected %d evaluations but got %d", len(expected), len(evaluated))
+			}
+		}
+		require.Equal(t, expected, evaluated)
+		for _, now := range times {
+			require.Equal(t, times[0], now)
+		}
+	})
+
+	t.Run("the rules of a group are evaluated at the interval of the group", func(t *testing.T) {
+		mockedClock.Add(time.Second)
+		select {
+		case e := <-evalAppliedCh:
+			t.Fatalf("unexpected evaluation of rule %s", e.key)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
 func generateRuleKey() models.AlertRuleKey {
 	return models.AlertRuleKey{
 		OrgID: rand.Int63(),
@@ -1033,7 +1220,7 @@ func setupScheduler(t *testing.T, rs store.RuleStore, is store.InstanceStore, ac
 		Metrics:                 m.GetSchedulerMetrics(),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, mockstore.NewSQLStoreMock())
+	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, nil, nil, mockstore.NewSQLStoreMock())
 	appUrl := &url.URL{
 		Scheme: "http",
 		Host:   "localhost",
@@ -198,6 +198,9 @@ func (f *fakeRuleStore) GetNamespaces(_ context.Context, _ int64, _ *models2.Sig
 func (f *fakeRuleStore) GetNamespaceByTitle(_ context.Context, _ string, _ int64, _ *models2.SignedInUser, _ bool) (*models2.Folder, error) {
 	return nil, nil
 }
+func (f *fakeRuleStore) GetNamespaceByUID(_ context.Context, _ string, _ int64, _ *models2.SignedInUser, _ bool) (*models2.Folder, error) {
+	return nil, nil
+}
 func (f *fakeRuleStore) GetOrgRuleGroups(_ context.Context, q *models.ListOrgRuleGroupsQuery) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -240,7 +243,7 @@ func (f *fakeRuleStore) UpdateRuleGroup(_ context.Context, cmd store.UpdateRuleG
 	}
 
 	rules := []*models.AlertRule{}
-	for _, r := range cmd.RuleGroupConfig.Rules {
+	for i, r := range cmd.RuleGroupConfig.Rules {
 		// TODO: Not sure why this is not being set properly, where is the code that sets this?
 		for i := range r.GrafanaManagedAlert.Data {
 			r.GrafanaManagedAlert.Data[i].DatasourceUID = "-100"
@@ -255,6 +258,7 @@ func (f *fakeRuleStore) UpdateRuleGroup(_ context.Context, cmd store.UpdateRuleG
 			IntervalSeconds: int64(time.Duration(cmd.RuleGroupConfig.Interval).Seconds()),
 			NamespaceUID:    cmd.NamespaceUID,
 			RuleGroup:       cmd.RuleGroupConfig.Name,
+			RuleGroupIndex:  i + 1,
 			NoDataState:     models.NoDataState(r.GrafanaManagedAlert.NoDataState),
 			ExecErrState:    models.ExecutionErrorState(r.GrafanaManagedAlert.ExecErrState),
 			Version:         1,
@@ -262,6 +266,7 @@ func (f *fakeRuleStore) UpdateRuleGroup(_ context.Context, cmd store.UpdateRuleG
 
 		if r.ApiRuleNode != nil {
 			new.For = time.Duration(r.ApiRuleNode.For)
+			new.KeepFiringFor = time.Duration(r.ApiRuleNode.KeepFiringFor)
 			new.Annotations = r.ApiRuleNode.Annotations
 			new.Labels = r.ApiRuleNode.Labels
 		}
@@ -313,6 +318,33 @@ func (f *FakeInstanceStore) DeleteAlertInstance(_ context.Context, _ int64, _, _
 	return nil
 }
 
+type FakeStateHistoryStore struct {
+	mtx     sync.Mutex
+	Entries []models.AlertStateHistoryEntry
+}
+
+func (f *FakeStateHistoryStore) SaveAlertStateHistory(_ context.Context, entries ...models.AlertStateHistoryEntry) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Entries = append(f.Entries, entries...)
+	return nil
+}
+
+func (f *FakeStateHistoryStore) ListAlertStateHistory(_ context.Context, _ *models.ListAlertStateHistoryQuery) error {
+	return nil
+}
+
+func (f *FakeStateHistoryStore) DeleteAlertStateHistoryBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+// Len returns the number of recorded state changes.
+func (f *FakeStateHistoryStore) Len() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return len(f.Entries)
+}
+
 func newFakeAdminConfigStore(t *testing.T) *fakeAdminConfigStore {
 	t.Helper()
 	return &fakeAdminConfigStore{configs: map[int64]*models.AdminConfiguration{}}
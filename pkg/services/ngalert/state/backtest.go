@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// Timeline is the history of the states of an alert instance computed by a backtest.
+type Timeline struct {
+	Labels  data.Labels
+	Periods []Period
+}
+
+// Period is a time range in which an alert instance stays in the same state.
+type Period struct {
+	State eval.State
+	// Resolved is true if the period starts with the resolution of a firing alert.
+	Resolved bool
+	// Start is the time of the evaluation at which the alert instance entered the state.
+	Start time.Time
+	// End is the time of the evaluation at which the alert instance left the state or was no longer returned by
+	// the evaluation, or the time of the last evaluation if the alert instance was still in the state.
+	End time.Time
+}
+
+// EvaluateFunc evaluates the condition of a rule at the given time.
+type EvaluateFunc func(now time.Time) (eval.Results, error)
+
+// Backtest evaluates the rule every interval of the rule from the given time to the given time, and computes the
+// states of its alert instances as the scheduler would. Nothing is saved and no notification is sent, so it can be
+// used to tune the condition and the For duration of a rule on past data.
+func Backtest(ctx context.Context, logger log.Logger, externalURL *url.URL, alertRule *ngModels.AlertRule, from, to time.Time, evaluate EvaluateFunc) ([]Timeline, error) {
+	st := &Manager{
+		cache:          newCache(logger, nil, externalURL),
+		recordingCache: newRecordingCache(),
+		imageBackoff:   newImageBackoff(),
+		ResendDelay:    ResendDelay,
+		log:            logger,
+		dryRun:         true,
+	}
+
+	interval := time.Duration(alertRule.IntervalSeconds) * time.Second
+	timelines := make(map[string]*Timeline)
+	// open contains the timelines whose last period has not ended yet
+	open := make(map[string]bool)
+	var last time.Time
+	for now := from; !now.After(to); now = now.Add(interval) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		results, err := evaluate(now)
+		if err != nil {
+			return nil, err
+		}
+		last = now
+
+		seen := make(map[string]bool, len(results))
+		for _, s := range st.processEvalResults(ctx, now, alertRule, results) {
+			seen[s.CacheId] = true
+			t, ok := timelines[s.CacheId]
+			if !ok {
+				t = &Timeline{Labels: s.Labels.Copy()}
+				timelines[s.CacheId] = t
+			}
+			if open[s.CacheId] {
+				p := &t.Periods[len(t.Periods)-1]
+				if p.State == s.State && !s.Resolved {
+					continue
+				}
+				p.End = now
+			}
+			t.Periods = append(t.Periods, Period{State: s.State, Resolved: s.Resolved, Start: now})
+			open[s.CacheId] = true
+		}
+		for id := range open {
+			if !seen[id] {
+				t := timelines[id]
+				t.Periods[len(t.Periods)-1].End = now
+				delete(open, id)
+			}
+		}
+	}
+	for id := range open {
+		t := timelines[id]
+		t.Periods[len(t.Periods)-1].End = last
+	}
+
+	result := make([]Timeline, 0, len(timelines))
+	for _, t := range timelines {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Labels.String() < result[j].Labels.String()
+	})
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestBacktest(t *testing.T) {
+	from := time.Unix(1000, 0)
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		IntervalSeconds: 10,
+		For:             20 * time.Second,
+		NoDataState:     models.NoData,
+		ExecErrState:    models.ErrorErrState,
+	}
+	at := func(i int) time.Time {
+		return from.Add(time.Duration(i) * 10 * time.Second)
+	}
+	// the states of instance a at each evaluation, instance b is only returned by the second evaluation
+	statesOfA := []eval.State{eval.Normal, eval.Alerting, eval.Alerting, eval.Alerting, eval.Alerting, eval.Normal, eval.Normal}
+	evaluate := func(now time.Time) (eval.Results, error) {
+		i := int(now.Sub(from) / (10 * time.Second))
+		results := eval.Results{{Instance: data.Labels{"instance": "a"}, State: statesOfA[i], EvaluatedAt: now}}
+		if i == 1 {
+			results = append(results, eval.Result{Instance: data.Labels{"instance": "b"}, State: eval.Normal, EvaluatedAt: now})
+		}
+		return results, nil
+	}
+
+	timelines, err := state.Backtest(context.Background(), log.New("test_backtest"), nil, rule, from, at(6), evaluate)
+	require.NoError(t, err)
+	require.Len(t, timelines, 2)
+
+	require.Equal(t, "a", timelines[0].Labels["instance"])
+	require.Equal(t, "test_title", timelines[0].Labels["alertname"])
+	require.Equal(t, []state.Period{
+		{State: eval.Normal, Start: at(0), End: at(1)},
+		{State: eval.Pending, Start: at(1), End: at(4)},
+		{State: eval.Alerting, Start: at(4), End: at(5)},
+		{State: eval.Normal, Resolved: true, Start: at(5), End: at(6)},
+	}, timelines[0].Periods)
+
+	require.Equal(t, "b", timelines[1].Labels["instance"])
+	require.Equal(t, []state.Period{
+		{State: eval.Normal, Start: at(1), End: at(2)},
+	}, timelines[1].Periods)
+
+	t.Run("returns the error of an evaluation", func(t *testing.T) {
+		failure := errors.New("invalid condition")
+		_, err := state.Backtest(context.Background(), log.New("test_backtest"), nil, rule, from, at(6), func(now time.Time) (eval.Results, error) {
+			return nil, failure
+		})
+		require.ErrorIs(t, err, failure)
+	})
+}
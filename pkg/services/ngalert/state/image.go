@@ -0,0 +1,66 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// minImageBackoff is how long to wait before taking a screenshot for a rule again after it failed.
+	minImageBackoff = time.Minute
+	// maxImageBackoff is the longest wait between the attempts, as the wait doubles with each failure.
+	maxImageBackoff = 30 * time.Minute
+)
+
+type imageFailure struct {
+	retryAt time.Time
+	backoff time.Duration
+}
+
+// imageBackoff keeps the rules whose screenshots failed, so that a failing image renderer does not slow down
+// every evaluation of the rules that are firing.
+type imageBackoff struct {
+	failures map[ngModels.AlertRuleKey]*imageFailure
+	mtx      sync.Mutex
+}
+
+func newImageBackoff() *imageBackoff {
+	return &imageBackoff{
+		failures: make(map[ngModels.AlertRuleKey]*imageFailure),
+	}
+}
+
+// ready returns true if a screenshot can be taken for the rule at the given time.
+func (b *imageBackoff) ready(key ngModels.AlertRuleKey, now time.Time) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	f, ok := b.failures[key]
+	return !ok || !now.Before(f.retryAt)
+}
+
+// failed records a failed screenshot for the rule and returns how long to wait before the next attempt.
+func (b *imageBackoff) failed(key ngModels.AlertRuleKey, now time.Time) time.Duration {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	f, ok := b.failures[key]
+	if !ok {
+		f = &imageFailure{backoff: minImageBackoff}
+		b.failures[key] = f
+	} else {
+		f.backoff *= 2
+		if f.backoff > maxImageBackoff {
+			f.backoff = maxImageBackoff
+		}
+	}
+	f.retryAt = now.Add(f.backoff)
+	return f.backoff
+}
+
+// reset forgets the failed screenshots of the rule.
+func (b *imageBackoff) reset(key ngModels.AlertRuleKey) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	delete(b.failures, key)
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -14,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -25,26 +27,40 @@ type Manager struct {
 	log     log.Logger
 	metrics *metrics.State
 
-	cache       *cache
-	quit        chan struct{}
-	ResendDelay time.Duration
+	cache          *cache
+	recordingCache *recordingCache
+	quit           chan struct{}
+	ResendDelay    time.Duration
 
 	ruleStore     store.RuleStore
 	instanceStore store.InstanceStore
+	historyStore  store.StateHistoryStore
+	imageService  image.ImageService
+	imageBackoff  *imageBackoff
 	sqlStore      sqlstore.Store
+
+	// dryRun is set when the states are only computed, as when a rule is backtested, so that no annotation,
+	// state history or alert instance is saved.
+	dryRun bool
 }
 
+// NewManager creates a new state manager. If historyStore is nil, the changes of state are not recorded in the state history.
+// If imageService is nil, no screenshots are taken when the state of an alert changes.
 func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL, ruleStore store.RuleStore,
-	instanceStore store.InstanceStore, sqlStore sqlstore.Store) *Manager {
+	instanceStore store.InstanceStore, historyStore store.StateHistoryStore, imageService image.ImageService, sqlStore sqlstore.Store) *Manager {
 	manager := &Manager{
-		cache:         newCache(logger, metrics, externalURL),
-		quit:          make(chan struct{}),
-		ResendDelay:   ResendDelay, // TODO: make this configurable
-		log:           logger,
-		metrics:       metrics,
-		ruleStore:     ruleStore,
-		instanceStore: instanceStore,
-		sqlStore:      sqlStore,
+		cache:          newCache(logger, metrics, externalURL),
+		recordingCache: newRecordingCache(),
+		quit:           make(chan struct{}),
+		ResendDelay:    ResendDelay, // TODO: make this configurable
+		log:            logger,
+		metrics:        metrics,
+		ruleStore:      ruleStore,
+		instanceStore:  instanceStore,
+		historyStore:   historyStore,
+		imageService:   imageService,
+		imageBackoff:   newImageBackoff(),
+		sqlStore:       sqlStore,
 	}
 	go manager.recordMetrics()
 	return manager
@@ -92,25 +108,12 @@ func (st *Manager) Warm(ctx context.Context) {
 				st.log.Error("rule not found for instance, ignoring", "rule", entry.RuleUID)
 				continue
 			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheId, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("error getting cacheId for entry", "msg", err.Error())
-			}
-			stateForEntry := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheId:              cacheId,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
+			if ruleForEntry.IsPaused {
+				st.log.Debug("rule is paused, ignoring instance", "rule", entry.RuleUID)
+				continue
 			}
-			states = append(states, stateForEntry)
+
+			states = append(states, st.stateFromInstance(entry, ruleForEntry))
 		}
 	}
 
@@ -119,6 +122,51 @@ func (st *Manager) Warm(ctx context.Context) {
 	}
 }
 
+// WarmRule replaces the cached state of an alert rule with the state stored in the database.
+// It is used when this instance takes over the evaluation of a rule from another member of the cluster.
+func (st *Manager) WarmRule(ctx context.Context, key ngModels.AlertRuleKey) {
+	st.RemoveByRuleUID(key.OrgID, key.UID)
+
+	ruleQuery := ngModels.GetAlertRuleByUIDQuery{OrgID: key.OrgID, UID: key.UID}
+	if err := st.ruleStore.GetAlertRuleByUID(ctx, &ruleQuery); err != nil {
+		st.log.Error("unable to fetch alert rule", "uid", key.UID, "org", key.OrgID, "msg", err.Error())
+		return
+	}
+
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: key.OrgID,
+		RuleUID:   key.UID,
+	}
+	if err := st.instanceStore.ListAlertInstances(ctx, &cmd); err != nil {
+		st.log.Error("unable to fetch previous state", "uid", key.UID, "org", key.OrgID, "msg", err.Error())
+		return
+	}
+
+	for _, entry := range cmd.Result {
+		st.set(st.stateFromInstance(entry, ruleQuery.Result))
+	}
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.ListAlertInstancesQueryResult, alertRule *ngModels.AlertRule) *State {
+	lbs := map[string]string(entry.Labels)
+	cacheId, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("error getting cacheId for entry", "msg", err.Error())
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheId:              cacheId,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          alertRule.Annotations,
+	}
+}
+
 func (st *Manager) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
 	return st.cache.getOrCreate(ctx, alertRule, result)
 }
@@ -134,30 +182,81 @@ func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) (*State, error
 // ResetCache is used to ensure a clean cache on startup.
 func (st *Manager) ResetCache() {
 	st.cache.reset()
+	st.recordingCache.reset()
 }
 
 // RemoveByRuleUID deletes all entries in the state manager that match the given rule UID.
 func (st *Manager) RemoveByRuleUID(orgID int64, ruleUID string) {
 	st.cache.removeByRuleUID(orgID, ruleUID)
+	st.recordingCache.remove(orgID, ruleUID)
+	st.imageBackoff.reset(ngModels.AlertRuleKey{OrgID: orgID, UID: ruleUID})
 }
 
 func (st *Manager) ProcessEvalResults(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results) []*State {
+	return st.processEvalResults(ctx, time.Now(), alertRule, results)
+}
+
+// processEvalResults sets the states of the alert instances of the rule from the results of an evaluation.
+// The states that were not updated for two intervals of the rule at the given time are removed, unless the
+// rule keeps the last state of its instances for the results.
+func (st *Manager) processEvalResults(ctx context.Context, now time.Time, alertRule *ngModels.AlertRule, results eval.Results) []*State {
 	st.log.Debug("state manager processing evaluation results", "uid", alertRule.UID, "resultCount", len(results))
 	var states []*State
 	processedResults := make(map[string]*State, len(results))
+	// All the alerts of a rule share the same panel, so at most one screenshot is taken per evaluation.
+	var (
+		img      *ngModels.Image
+		imgTaken bool
+	)
+	getImage := func(evaluatedAt time.Time) *ngModels.Image {
+		if !imgTaken {
+			imgTaken = true
+			img = st.newImage(ctx, alertRule, evaluatedAt)
+		}
+		return img
+	}
+	if keepsLastState(alertRule, results) {
+		if cached := st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID); len(cached) > 0 {
+			// The result has no labels, so every instance of the rule keeps its last state. No instance is
+			// stale, as the evaluation did not return the series of the rule.
+			for _, s := range cached {
+				states = append(states, st.updateState(ctx, alertRule, s, results[0], getImage))
+			}
+			return states
+		}
+	}
 	for _, result := range results {
-		s := st.setNextState(ctx, alertRule, result)
+		s := st.setNextState(ctx, alertRule, result, getImage)
 		states = append(states, s)
 		processedResults[s.CacheId] = s
 	}
-	st.staleResultsHandler(ctx, alertRule, processedResults)
+	st.staleResultsHandler(ctx, now, alertRule, processedResults)
 	return states
 }
 
+// keepsLastState returns true if the results are a single NoData or Error result without labels, for which the
+// rule keeps the last state of its instances.
+func keepsLastState(alertRule *ngModels.AlertRule, results eval.Results) bool {
+	if len(results) != 1 || len(results[0].Instance) > 0 {
+		return false
+	}
+	switch results[0].State {
+	case eval.NoData:
+		return alertRule.NoDataState == ngModels.KeepLast
+	case eval.Error:
+		return alertRule.ExecErrState == ngModels.KeepLastErrState
+	default:
+		return false
+	}
+}
+
 // Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
-	currentState := st.getOrCreate(ctx, alertRule, result)
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, getImage func(time.Time) *ngModels.Image) *State {
+	return st.updateState(ctx, alertRule, st.getOrCreate(ctx, alertRule, result), result, getImage)
+}
 
+// updateState sets the state of an alert instance from the result of an evaluation.
+func (st *Manager) updateState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, getImage func(time.Time) *ngModels.Image) *State {
 	currentState.LastEvaluationTime = result.EvaluatedAt
 	currentState.EvaluationDuration = result.EvaluationDuration
 	currentState.Results = append(currentState.Results, Evaluation{
@@ -186,13 +285,47 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
 
+	if shouldTakeImage(currentState, oldState) {
+		if img := getImage(result.EvaluatedAt); img != nil {
+			currentState.Image = img
+		}
+	}
+
 	st.set(currentState)
-	if oldState != currentState.State {
+	if oldState != currentState.State && !st.dryRun {
 		go st.createAlertAnnotation(ctx, currentState.State, alertRule, result, oldState)
+		st.recordStateHistory(ctx, alertRule, currentState, result, oldState)
 	}
 	return currentState
 }
 
+// shouldTakeImage returns true if the alert started firing, was resolved, or is firing without a screenshot.
+func shouldTakeImage(s *State, oldState eval.State) bool {
+	if s.Resolved {
+		return true
+	}
+	return s.State == eval.Alerting && (oldState != eval.Alerting || s.Image == nil)
+}
+
+// newImage takes a screenshot of the panel of the alert rule. It returns nil if the rule is not linked
+// to a panel or the screenshot could not be taken, as notifications are sent without an image then.
+// After a screenshot failed, no screenshot is taken for the rule until the backoff of the rule elapsed.
+func (st *Manager) newImage(ctx context.Context, alertRule *ngModels.AlertRule, evaluatedAt time.Time) *ngModels.Image {
+	if st.imageService == nil || !st.imageBackoff.ready(alertRule.GetKey(), evaluatedAt) {
+		return nil
+	}
+	img, err := st.imageService.NewImage(ctx, alertRule)
+	if err != nil {
+		if !errors.Is(err, image.ErrNoDashboard) && !errors.Is(err, image.ErrNoPanel) {
+			backoff := st.imageBackoff.failed(alertRule.GetKey(), evaluatedAt)
+			st.log.Warn("failed to take an image", "alertRuleUID", alertRule.UID, "retryIn", backoff, "error", err)
+		}
+		return nil
+	}
+	st.imageBackoff.reset(alertRule.GetKey())
+	return img
+}
+
 func (st *Manager) GetAll(orgID int64) []*State {
 	return st.cache.getAll(orgID)
 }
@@ -282,13 +415,42 @@ func (st *Manager) createAlertAnnotation(ctx context.Context, new eval.State, al
 	}
 }
 
-func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.AlertRule, states map[string]*State) {
+// recordStateHistory saves the change of state of an alert instance in the state history.
+func (st *Manager) recordStateHistory(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, oldState eval.State) {
+	if st.historyStore == nil {
+		return
+	}
+
+	entry := ngModels.AlertStateHistoryEntry{
+		OrgID:         alertRule.OrgID,
+		RuleUID:       alertRule.UID,
+		Labels:        ngModels.InstanceLabels(currentState.Labels.Copy()),
+		PreviousState: ngModels.InstanceStateType(oldState.String()),
+		State:         ngModels.InstanceStateType(currentState.State.String()),
+		Values:        NewEvaluationValues(result.Values),
+		Timestamp:     result.EvaluatedAt,
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+
+	go func() {
+		if err := st.historyStore.SaveAlertStateHistory(ctx, entry); err != nil {
+			st.log.Error("error saving alert state history", "alertRuleUID", alertRule.UID, "error", err.Error())
+		}
+	}()
+}
+
+func (st *Manager) staleResultsHandler(ctx context.Context, now time.Time, alertRule *ngModels.AlertRule, states map[string]*State) {
 	allStates := st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID)
 	for _, s := range allStates {
 		_, ok := states[s.CacheId]
-		if !ok && isItStale(s.LastEvaluationTime, alertRule.IntervalSeconds) {
+		if !ok && isItStale(now, s.LastEvaluationTime, alertRule.IntervalSeconds) {
 			st.log.Debug("removing stale state entry", "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID, "cacheID", s.CacheId)
 			st.cache.deleteEntry(s.OrgID, s.AlertRuleUID, s.CacheId)
+			if st.dryRun {
+				continue
+			}
 			ilbs := ngModels.InstanceLabels(s.Labels)
 			_, labelsHash, err := ilbs.StringAndHash()
 			if err != nil {
@@ -302,6 +464,6 @@ func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.
 	}
 }
 
-func isItStale(lastEval time.Time, intervalSeconds int64) bool {
-	return lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).Before(time.Now())
+func isItStale(now time.Time, lastEval time.Time, intervalSeconds int64) bool {
+	return lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).Before(now)
 }
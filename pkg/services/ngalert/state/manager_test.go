@@ -1482,7 +1482,7 @@ func TestProcessEvalResults(t *testing.T) {
 
 	for _, tc := range testCases {
 		ss := mockstore.NewSQLStoreMock()
-		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, ss)
+		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, nil, nil, ss)
 		t.Run(tc.desc, func(t *testing.T) {
 			fakeAnnoRepo := schedule.NewFakeAnnotationsRepo()
 			annotations.SetRepository(fakeAnnoRepo)
@@ -1592,7 +1592,7 @@ func TestStaleResultsHandler(t *testing.T) {
 	for _, tc := range testCases {
 		ctx := context.Background()
 		sqlStore := mockstore.NewSQLStoreMock()
-		st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, sqlStore)
+		st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, nil, nil, sqlStore)
 		st.Warm(ctx)
 		existingStatesForRule := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
 
@@ -1612,3 +1612,334 @@ func TestStaleResultsHandler(t *testing.T) {
 		assert.Equal(t, tc.finalStateCount, len(existingStatesForRule))
 	}
 }
+
+func TestStateHistory(t *testing.T) {
+	evaluationTime := time.Unix(1000, 0)
+	rule := &models.AlertRule{
+		OrgID:           1,
+		UID:             "test_alert_rule_uid",
+		Title:           "test_title",
+		NamespaceUID:    "test_namespace_uid",
+		Labels:          map[string]string{"label": "test"},
+		IntervalSeconds: 10,
+		ExecErrState:    models.ErrorErrState,
+	}
+	value := 5.0
+	results := []eval.Results{
+		{eval.Result{Instance: data.Labels{"instance": "a"}, State: eval.Normal, EvaluatedAt: evaluationTime}},
+		{eval.Result{Instance: data.Labels{"instance": "a"}, State: eval.Normal, EvaluatedAt: evaluationTime.Add(10 * time.Second)}},
+		{eval.Result{
+			Instance:    data.Labels{"instance": "a"},
+			State:       eval.Alerting,
+			EvaluatedAt: evaluationTime.Add(20 * time.Second),
+			Values:      map[string]eval.NumberValueCapture{"B": {Var: "B", Value: &value}},
+		}},
+		{eval.Result{
+			Instance:    data.Labels{"instance": "a"},
+			State:       eval.Error,
+			Error:       errors.New("query failed"),
+			EvaluatedAt: evaluationTime.Add(30 * time.Second),
+		}},
+	}
+
+	fakeAnnoRepo := schedule.NewFakeAnnotationsRepo()
+	annotations.SetRepository(fakeAnnoRepo)
+	historyStore := &schedule.FakeStateHistoryStore{}
+	st := state.NewManager(log.New("test_state_history"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, historyStore, nil, mockstore.NewSQLStoreMock())
+	for _, res := range results {
+		_ = st.ProcessEvalResults(context.Background(), rule, res)
+	}
+
+	require.Eventuallyf(t, func() bool {
+		return historyStore.Len() == 2
+	}, time.Second, 10*time.Millisecond, "expected 2 state changes to be recorded")
+	// The state history does not depend on annotations but they are created for the same changes.
+	require.Eventuallyf(t, func() bool {
+		return fakeAnnoRepo.Len() == 2
+	}, time.Second, 10*time.Millisecond, "expected 2 annotations to be created")
+
+	entries := historyStore.Entries
+	if entries[0].Timestamp.After(entries[1].Timestamp) {
+		entries[0], entries[1] = entries[1], entries[0]
+	}
+	require.Equal(t, models.AlertStateHistoryEntry{
+		OrgID:         1,
+		RuleUID:       "test_alert_rule_uid",
+		Labels:        models.InstanceLabels{"__alert_rule_uid__": "test_alert_rule_uid", "__alert_rule_namespace_uid__": "test_namespace_uid", "alertname": "test_title", "label": "test", "instance": "a"},
+		PreviousState: models.InstanceStateNormal,
+		State:         models.InstanceStateFiring,
+		Values:        map[string]*float64{"B": &value},
+		Timestamp:     evaluationTime.Add(20 * time.Second),
+	}, entries[0])
+	require.Equal(t, models.InstanceStateFiring, entries[1].PreviousState)
+	require.Equal(t, models.InstanceStateError, entries[1].State)
+	require.Equal(t, "query failed", entries[1].Error)
+}
+
+type fakeImageService struct {
+	calls int
+	err   error
+}
+
+func (f *fakeImageService) NewImage(_ context.Context, _ *models.AlertRule) (*models.Image, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.Image{Token: fmt.Sprintf("image-%d", f.calls)}, nil
+}
+
+func TestProcessEvalResults_Images(t *testing.T) {
+	evaluationTime := time.Now()
+	rule := &models.AlertRule{
+		OrgID:           1,
+		UID:             "test_alert_rule_uid",
+		Title:           "test_title",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+	}
+	results := func(offset time.Duration, a, b eval.State) eval.Results {
+		return eval.Results{
+			eval.Result{Instance: data.Labels{"instance": "a"}, State: a, EvaluatedAt: evaluationTime.Add(offset)},
+			eval.Result{Instance: data.Labels{"instance": "b"}, State: b, EvaluatedAt: evaluationTime.Add(offset)},
+		}
+	}
+
+	fakeAnnoRepo := schedule.NewFakeAnnotationsRepo()
+	annotations.SetRepository(fakeAnnoRepo)
+	images := &fakeImageService{}
+	st := state.NewManager(log.New("test_images"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, nil, images, mockstore.NewSQLStoreMock())
+
+	states := st.ProcessEvalResults(context.Background(), rule, results(0, eval.Normal, eval.Normal))
+	require.Equal(t, 0, images.calls)
+	require.Nil(t, states[0].Image)
+
+	// one screenshot is taken for all the alerts that start firing
+	states = st.ProcessEvalResults(context.Background(), rule, results(10*time.Second, eval.Alerting, eval.Alerting))
+	require.Equal(t, 1, images.calls)
+	require.Equal(t, "image-1", states[0].Image.Token)
+	require.Equal(t, "image-1", states[1].Image.Token)
+
+	// no screenshot is taken while the alerts keep firing
+	states = st.ProcessEvalResults(context.Background(), rule, results(20*time.Second, eval.Alerting, eval.Alerting))
+	require.Equal(t, 1, images.calls)
+	require.Equal(t, "image-1", states[1].Image.Token)
+
+	// a new screenshot is taken for the alerts that are resolved
+	states = st.ProcessEvalResults(context.Background(), rule, results(30*time.Second, eval.Normal, eval.Alerting))
+	require.Equal(t, 2, images.calls)
+	require.True(t, states[0].Resolved)
+	require.Equal(t, "image-2", states[0].Image.Token)
+	require.Equal(t, "image-1", states[1].Image.Token)
+
+	require.Eventually(t, func() bool {
+		return fakeAnnoRepo.Len() == 3
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestProcessEvalResults_ImageBackoff(t *testing.T) {
+	evaluationTime := time.Now()
+	rule := &models.AlertRule{
+		OrgID:           1,
+		UID:             "test_alert_rule_uid",
+		Title:           "test_title",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+	}
+	results := func(offset time.Duration) eval.Results {
+		return eval.Results{
+			eval.Result{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: evaluationTime.Add(offset)},
+		}
+	}
+
+	annotations.SetRepository(schedule.NewFakeAnnotationsRepo())
+	images := &fakeImageService{err: errors.New("renderer is unavailable")}
+	st := state.NewManager(log.New("test_images"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, nil, images, mockstore.NewSQLStoreMock())
+
+	states := st.ProcessEvalResults(context.Background(), rule, results(0))
+	require.Equal(t, 1, images.calls)
+	require.Nil(t, states[0].Image)
+
+	// the screenshot is not taken again until a minute after it failed
+	st.ProcessEvalResults(context.Background(), rule, results(50*time.Second))
+	require.Equal(t, 1, images.calls)
+	st.ProcessEvalResults(context.Background(), rule, results(time.Minute))
+	require.Equal(t, 2, images.calls)
+
+	// the wait doubles after each failure
+	st.ProcessEvalResults(context.Background(), rule, results(2*time.Minute+50*time.Second))
+	require.Equal(t, 2, images.calls)
+	st.ProcessEvalResults(context.Background(), rule, results(3*time.Minute))
+	require.Equal(t, 3, images.calls)
+
+	// the screenshot is attached once it can be taken again
+	images.err = nil
+	st.ProcessEvalResults(context.Background(), rule, results(6*time.Minute+50*time.Second))
+	require.Equal(t, 3, images.calls)
+	states = st.ProcessEvalResults(context.Background(), rule, results(7*time.Minute))
+	require.Equal(t, 4, images.calls)
+	require.Equal(t, "image-4", states[0].Image.Token)
+}
+
+func TestProcessEvalResults_KeepFiringFor(t *testing.T) {
+	evaluationTime := time.Now()
+	rule := &models.AlertRule{
+		OrgID:           1,
+		UID:             "test_alert_rule_uid",
+		Title:           "test_title",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		KeepFiringFor:   20 * time.Second,
+	}
+	results := func(offset time.Duration, s eval.State) eval.Results {
+		return eval.Results{
+			eval.Result{Instance: data.Labels{"instance": "a"}, State: s, EvaluatedAt: evaluationTime.Add(offset)},
+		}
+	}
+
+	annotations.SetRepository(schedule.NewFakeAnnotationsRepo())
+	st := state.NewManager(log.New("test_keep_firing_for"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, nil, nil, mockstore.NewSQLStoreMock())
+
+	states := st.ProcessEvalResults(context.Background(), rule, results(0, eval.Alerting))
+	require.Equal(t, eval.Alerting, states[0].State)
+	startsAt := states[0].StartsAt
+
+	// the alert keeps firing while the condition is not met for less than the keep firing for duration
+	states = st.ProcessEvalResults(context.Background(), rule, results(10*time.Second, eval.Normal))
+	require.Equal(t, eval.Alerting, states[0].State)
+	require.False(t, states[0].Resolved)
+	require.Equal(t, startsAt, states[0].StartsAt)
+	states = st.ProcessEvalResults(context.Background(), rule, results(20*time.Second, eval.Normal))
+	require.Equal(t, eval.Alerting, states[0].State)
+
+	// the keep firing for duration starts over when the condition is met again
+	states = st.ProcessEvalResults(context.Background(), rule, results(30*time.Second, eval.Alerting))
+	require.Equal(t, eval.Alerting, states[0].State)
+	require.Equal(t, startsAt, states[0].StartsAt)
+	states = st.ProcessEvalResults(context.Background(), rule, results(40*time.Second, eval.Normal))
+	require.Equal(t, eval.Alerting, states[0].State)
+	states = st.ProcessEvalResults(context.Background(), rule, results(50*time.Second, eval.Normal))
+	require.Equal(t, eval.Alerting, states[0].State)
+
+	// the alert is resolved once the condition has not been met for the keep firing for duration
+	states = st.ProcessEvalResults(context.Background(), rule, results(60*time.Second, eval.Normal))
+	require.Equal(t, eval.Normal, states[0].State)
+	require.True(t, states[0].Resolved)
+}
+
+func TestProcessEvalResults_KeepLast(t *testing.T) {
+	evaluationTime := time.Now()
+	// the series of the rule are returned as instances a and b
+	results := func(offset time.Duration, s eval.State) eval.Results {
+		return eval.Results{
+			eval.Result{Instance: data.Labels{"instance": "a"}, State: s, EvaluatedAt: evaluationTime.Add(offset)},
+			eval.Result{Instance: data.Labels{"instance": "b"}, State: eval.Normal, EvaluatedAt: evaluationTime.Add(offset)},
+		}
+	}
+	// NoData and Error results have no labels, as the evaluation returns no series
+	labelLessResult := func(offset time.Duration, s eval.State) eval.Results {
+		return eval.Results{
+			eval.Result{Instance: data.Labels{}, State: s, EvaluatedAt: evaluationTime.Add(offset)},
+		}
+	}
+	statesByInstance := func(states []*state.State) map[string]eval.State {
+		byInstance := make(map[string]eval.State, len(states))
+		for _, s := range states {
+			byInstance[s.Labels["instance"]] = s.State
+		}
+		return byInstance
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		result   eval.State
+		previous []eval.State
+		expected eval.State
+	}{
+		{
+			desc:     "no data keeps a firing alert firing",
+			result:   eval.NoData,
+			previous: []eval.State{eval.Alerting, eval.Alerting, eval.Alerting},
+			expected: eval.Alerting,
+		},
+		{
+			desc:     "error keeps a firing alert firing",
+			result:   eval.Error,
+			previous: []eval.State{eval.Alerting, eval.Alerting, eval.Alerting},
+			expected: eval.Alerting,
+		},
+		{
+			desc:     "no data keeps a normal instance normal",
+			result:   eval.NoData,
+			previous: []eval.State{eval.Normal},
+			expected: eval.Normal,
+		},
+		{
+			desc:     "error keeps a normal instance normal",
+			result:   eval.Error,
+			previous: []eval.State{eval.Normal},
+			expected: eval.Normal,
+		},
+		{
+			desc:     "no data keeps a pending alert pending during the for duration",
+			result:   eval.NoData,
+			previous: []eval.State{eval.Alerting},
+			expected: eval.Pending,
+		},
+		{
+			desc:     "error fires a pending alert after the for duration",
+			result:   eval.Error,
+			previous: []eval.State{eval.Alerting, eval.Alerting},
+			expected: eval.Alerting,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			rule := &models.AlertRule{
+				OrgID:           1,
+				UID:             "test_alert_rule_uid",
+				Title:           "test_title",
+				NamespaceUID:    "test_namespace_uid",
+				IntervalSeconds: 10,
+				For:             15 * time.Second,
+				NoDataState:     models.KeepLast,
+				ExecErrState:    models.KeepLastErrState,
+			}
+			annotations.SetRepository(schedule.NewFakeAnnotationsRepo())
+			st := state.NewManager(log.New("test_keep_last"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, nil, nil, mockstore.NewSQLStoreMock())
+
+			var offset time.Duration
+			for _, s := range tc.previous {
+				st.ProcessEvalResults(context.Background(), rule, results(offset, s))
+				offset += 10 * time.Second
+			}
+			states := st.ProcessEvalResults(context.Background(), rule, labelLessResult(offset, tc.result))
+			require.Equal(t, map[string]eval.State{"a": tc.expected, "b": eval.Normal}, statesByInstance(states))
+		})
+	}
+
+	t.Run("keeps the instances of the rule until the series are returned again", func(t *testing.T) {
+		rule := &models.AlertRule{
+			OrgID:           1,
+			UID:             "test_alert_rule_uid",
+			Title:           "test_title",
+			NamespaceUID:    "test_namespace_uid",
+			IntervalSeconds: 10,
+			NoDataState:     models.KeepLast,
+			ExecErrState:    models.KeepLastErrState,
+		}
+		annotations.SetRepository(schedule.NewFakeAnnotationsRepo())
+		st := state.NewManager(log.New("test_keep_last"), testMetrics.GetStateMetrics(), nil, nil, &schedule.FakeInstanceStore{}, nil, nil, mockstore.NewSQLStoreMock())
+
+		st.ProcessEvalResults(context.Background(), rule, results(0, eval.Alerting))
+		for offset := 10 * time.Second; offset <= time.Minute; offset += 10 * time.Second {
+			st.ProcessEvalResults(context.Background(), rule, labelLessResult(offset, eval.NoData))
+		}
+
+		states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Equal(t, map[string]eval.State{"a": eval.Alerting, "b": eval.Normal}, statesByInstance(states))
+
+		states = st.ProcessEvalResults(context.Background(), rule, results(70*time.Second, eval.Normal))
+		require.Equal(t, map[string]eval.State{"a": eval.Normal, "b": eval.Normal}, statesByInstance(states))
+		require.True(t, states[0].Resolved || states[1].Resolved)
+	})
+}
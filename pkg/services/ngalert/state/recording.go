@@ -0,0 +1,92 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RecordingRuleHealth is the health of the last evaluation of a recording rule.
+type RecordingRuleHealth string
+
+const (
+	RecordingRuleHealthUnknown RecordingRuleHealth = "unknown"
+	RecordingRuleHealthOK      RecordingRuleHealth = "ok"
+	RecordingRuleHealthError   RecordingRuleHealth = "error"
+)
+
+// RecordingRuleState is the state of a recording rule. Unlike alert rules, recording
+// rules have a single state per rule rather than one per series.
+type RecordingRuleState struct {
+	AlertRuleUID       string
+	OrgID              int64
+	Health             RecordingRuleHealth
+	LastEvaluationTime time.Time
+	EvaluationDuration time.Duration
+	// SeriesWritten is the number of series written by the last evaluation.
+	SeriesWritten int
+	Error         error
+}
+
+type recordingCache struct {
+	states map[int64]map[string]*RecordingRuleState // orgID > alertRuleUID > state
+	mtx    sync.RWMutex
+}
+
+func newRecordingCache() *recordingCache {
+	return &recordingCache{
+		states: make(map[int64]map[string]*RecordingRuleState),
+	}
+}
+
+func (c *recordingCache) set(s *RecordingRuleState) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.states[s.OrgID]; !ok {
+		c.states[s.OrgID] = make(map[string]*RecordingRuleState)
+	}
+	c.states[s.OrgID][s.AlertRuleUID] = s
+}
+
+func (c *recordingCache) get(orgID int64, alertRuleUID string) (*RecordingRuleState, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	s, ok := c.states[orgID][alertRuleUID]
+	return s, ok
+}
+
+func (c *recordingCache) remove(orgID int64, alertRuleUID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.states[orgID], alertRuleUID)
+}
+
+func (c *recordingCache) reset() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.states = make(map[int64]map[string]*RecordingRuleState)
+}
+
+// ProcessRecordingResult stores the outcome of the evaluation of a recording rule.
+func (st *Manager) ProcessRecordingResult(alertRule *ngModels.AlertRule, evaluatedAt time.Time, duration time.Duration, seriesWritten int, err error) *RecordingRuleState {
+	s := &RecordingRuleState{
+		AlertRuleUID:       alertRule.UID,
+		OrgID:              alertRule.OrgID,
+		Health:             RecordingRuleHealthOK,
+		LastEvaluationTime: evaluatedAt,
+		EvaluationDuration: duration,
+		SeriesWritten:      seriesWritten,
+		Error:              err,
+	}
+	if err != nil {
+		s.Health = RecordingRuleHealthError
+	}
+	st.recordingCache.set(s)
+	return s
+}
+
+// GetRecordingRuleState returns the state of the recording rule, if it has been evaluated.
+func (st *Manager) GetRecordingRuleState(orgID int64, alertRuleUID string) (*RecordingRuleState, bool) {
+	return st.recordingCache.get(orgID, alertRuleUID)
+}
@@ -27,6 +27,11 @@ type State struct {
 	Annotations          map[string]string
 	Labels               data.Labels
 	Error                error
+	// KeepFiringSince is the time of the first evaluation in which the condition of the firing alert was no longer met.
+	// The alert keeps firing until the KeepFiringFor duration of the rule has elapsed since then.
+	KeepFiringSince time.Time
+	// Image is the screenshot of the panel of the alert rule taken when the state last changed.
+	Image *ngModels.Image
 }
 
 type Evaluation struct {
@@ -50,6 +55,17 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 func (a *State) resultNormal(alertRule *ngModels.AlertRule, result eval.Result) {
 	a.Error = result.Error // should be nil since state is not error
 
+	if a.State == eval.Alerting && alertRule.KeepFiringFor > 0 {
+		if a.KeepFiringSince.IsZero() {
+			a.KeepFiringSince = result.EvaluatedAt
+		}
+		if result.EvaluatedAt.Sub(a.KeepFiringSince) < alertRule.KeepFiringFor {
+			a.setEndsAt(alertRule, result)
+			return
+		}
+	}
+	a.KeepFiringSince = time.Time{}
+
 	if a.State != eval.Normal {
 		a.EndsAt = result.EvaluatedAt
 		a.StartsAt = result.EvaluatedAt
@@ -59,6 +75,7 @@ func (a *State) resultNormal(alertRule *ngModels.AlertRule, result eval.Result)
 
 func (a *State) resultAlerting(alertRule *ngModels.AlertRule, result eval.Result) {
 	a.Error = result.Error // should be nil since the state is not an error
+	a.KeepFiringSince = time.Time{}
 
 	switch a.State {
 	case eval.Alerting:
@@ -84,6 +101,11 @@ func (a *State) resultAlerting(alertRule *ngModels.AlertRule, result eval.Result
 func (a *State) resultError(alertRule *ngModels.AlertRule, result eval.Result) {
 	a.Error = result.Error
 
+	if alertRule.ExecErrState == ngModels.KeepLastErrState {
+		a.resultKeepLast(alertRule, result)
+		return
+	}
+
 	if a.StartsAt.IsZero() {
 		a.StartsAt = result.EvaluatedAt
 	}
@@ -115,6 +137,11 @@ func (a *State) resultError(alertRule *ngModels.AlertRule, result eval.Result) {
 func (a *State) resultNoData(alertRule *ngModels.AlertRule, result eval.Result) {
 	a.Error = result.Error
 
+	if alertRule.NoDataState == ngModels.KeepLast {
+		a.resultKeepLast(alertRule, result)
+		return
+	}
+
 	if a.StartsAt.IsZero() {
 		a.StartsAt = result.EvaluatedAt
 	}
@@ -130,6 +157,24 @@ func (a *State) resultNoData(alertRule *ngModels.AlertRule, result eval.Result)
 	}
 }
 
+// resultKeepLast handles a NoData or Error result of a rule that keeps the last state of its instances, as if the
+// evaluation had the same result as the previous one: firing alerts keep firing, pending alerts start firing once
+// the For duration of the rule has elapsed, and the other instances keep their state.
+func (a *State) resultKeepLast(alertRule *ngModels.AlertRule, result eval.Result) {
+	switch a.State {
+	case eval.Alerting:
+		a.setEndsAt(alertRule, result)
+	case eval.Pending:
+		if result.EvaluatedAt.Sub(a.StartsAt) > alertRule.For {
+			a.State = eval.Alerting
+			a.StartsAt = result.EvaluatedAt
+		}
+		a.setEndsAt(alertRule, result)
+	case eval.NoData, eval.Error:
+		a.setEndsAt(alertRule, result)
+	}
+}
+
 func (a *State) NeedsSending(resendDelay time.Duration) bool {
 	if a.State == eval.Pending || a.State == eval.Normal && !a.Resolved {
 		return false
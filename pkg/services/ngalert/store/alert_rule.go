@@ -50,6 +50,7 @@ type RuleStore interface {
 	GetRuleGroupAlertRules(ctx context.Context, query *ngmodels.ListRuleGroupAlertRulesQuery) error
 	GetNamespaces(context.Context, int64, *models.SignedInUser) (map[string]*models.Folder, error)
 	GetNamespaceByTitle(context.Context, string, int64, *models.SignedInUser, bool) (*models.Folder, error)
+	GetNamespaceByUID(context.Context, string, int64, *models.SignedInUser, bool) (*models.Folder, error)
 	GetOrgRuleGroups(ctx context.Context, query *ngmodels.ListOrgRuleGroupsQuery) error
 	UpsertAlertRules(ctx context.Context, rule []UpsertRule) error
 	UpdateRuleGroup(ctx context.Context, cmd UpdateRuleGroupCmd) error
@@ -211,27 +212,7 @@ func (st DBstore) UpsertAlertRules(ctx context.Context, rules []UpsertRule) erro
 				}
 				r.New.UID = uid
 
-				if r.New.IntervalSeconds == 0 {
-					r.New.IntervalSeconds = int64(st.DefaultInterval.Seconds())
-				}
-
-				r.New.Version = 1
-
-				if r.New.NoDataState == "" {
-					// set default no data state
-					r.New.NoDataState = ngmodels.NoData
-				}
-
-				if r.New.ExecErrState == "" {
-					// set default error state
-					r.New.ExecErrState = ngmodels.AlertingErrState
-				}
-
-				if err := st.validateAlertRule(r.New); err != nil {
-					return err
-				}
-
-				if err := (&r.New).PreSave(TimeNow); err != nil {
+				if err := st.prepareNewAlertRule(&r.New); err != nil {
 					return err
 				}
 
@@ -281,24 +262,7 @@ func (st DBstore) UpsertAlertRules(ctx context.Context, rules []UpsertRule) erro
 				parentVersion = r.Existing.Version
 			}
 
-			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleOrgID:        r.New.OrgID,
-				RuleUID:          r.New.UID,
-				RuleNamespaceUID: r.New.NamespaceUID,
-				RuleGroup:        r.New.RuleGroup,
-				ParentVersion:    parentVersion,
-				Version:          r.New.Version,
-				Created:          r.New.Updated,
-				Condition:        r.New.Condition,
-				Title:            r.New.Title,
-				Data:             r.New.Data,
-				IntervalSeconds:  r.New.IntervalSeconds,
-				NoDataState:      r.New.NoDataState,
-				ExecErrState:     r.New.ExecErrState,
-				For:              r.New.For,
-				Annotations:      r.New.Annotations,
-				Labels:           r.New.Labels,
-			})
+			ruleVersions = append(ruleVersions, newAlertRuleVersion(r.New, parentVersion))
 		}
 
 		if len(newRules) > 0 {
@@ -317,6 +281,92 @@ func (st DBstore) UpsertAlertRules(ctx context.Context, rules []UpsertRule) erro
 	})
 }
 
+// InsertAlertRules is a handler for creating alert rules. Unlike UpsertAlertRules, it keeps the UID
+// of the rules if it is set, so that rules can be created with a known UID, e.g. from provisioning files.
+func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRule) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		newRules := make([]ngmodels.AlertRule, 0, len(rules))
+		ruleVersions := make([]ngmodels.AlertRuleVersion, 0, len(rules))
+		for _, r := range rules {
+			if r.UID == "" {
+				uid, err := GenerateNewAlertRuleUID(sess, r.OrgID, r.Title)
+				if err != nil {
+					return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.Title, err)
+				}
+				r.UID = uid
+			}
+
+			if err := st.prepareNewAlertRule(&r); err != nil {
+				return err
+			}
+
+			newRules = append(newRules, r)
+			ruleVersions = append(ruleVersions, newAlertRuleVersion(r, 0))
+		}
+
+		if len(newRules) > 0 {
+			if _, err := sess.Insert(&newRules); err != nil {
+				return fmt.Errorf("failed to create new rules: %w", err)
+			}
+			if _, err := sess.Insert(&ruleVersions); err != nil {
+				return fmt.Errorf("failed to create new rule versions: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// prepareNewAlertRule sets the defaults of a rule that is about to be created and validates it.
+func (st DBstore) prepareNewAlertRule(rule *ngmodels.AlertRule) error {
+	if rule.IntervalSeconds == 0 {
+		rule.IntervalSeconds = int64(st.DefaultInterval.Seconds())
+	}
+
+	rule.Version = 1
+
+	if rule.NoDataState == "" {
+		// set default no data state
+		rule.NoDataState = ngmodels.NoData
+	}
+
+	if rule.ExecErrState == "" {
+		// set default error state
+		rule.ExecErrState = ngmodels.AlertingErrState
+	}
+
+	if err := st.validateAlertRule(*rule); err != nil {
+		return err
+	}
+
+	return rule.PreSave(TimeNow)
+}
+
+func newAlertRuleVersion(rule ngmodels.AlertRule, parentVersion int64) ngmodels.AlertRuleVersion {
+	return ngmodels.AlertRuleVersion{
+		RuleOrgID:        rule.OrgID,
+		RuleUID:          rule.UID,
+		RuleNamespaceUID: rule.NamespaceUID,
+		RuleGroup:        rule.RuleGroup,
+		RuleGroupIndex:   rule.RuleGroupIndex,
+		ParentVersion:    parentVersion,
+		Version:          rule.Version,
+		Created:          rule.Updated,
+		Condition:        rule.Condition,
+		Title:            rule.Title,
+		Data:             rule.Data,
+		IntervalSeconds:  rule.IntervalSeconds,
+		NoDataState:      rule.NoDataState,
+		ExecErrState:     rule.ExecErrState,
+		For:              rule.For,
+		KeepFiringFor:    rule.KeepFiringFor,
+		Annotations:      rule.Annotations,
+		Labels:           rule.Labels,
+		Record:           rule.Record,
+		IsPaused:         rule.IsPaused,
+	}
+}
+
 // GetOrgAlertRules is a handler for retrieving alert rules of specific organisation.
 func (st DBstore) GetOrgAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) error {
 	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
@@ -342,7 +392,7 @@ func (st DBstore) GetOrgAlertRules(ctx context.Context, query *ngmodels.ListAler
 			}
 		}
 
-		q = fmt.Sprintf("%s ORDER BY id ASC", q)
+		q = fmt.Sprintf("%s ORDER BY rule_group_index ASC, id ASC", q)
 
 		if err := sess.SQL(q, params...).Find(&alertRules); err != nil {
 			return err
@@ -358,7 +408,7 @@ func (st DBstore) GetNamespaceAlertRules(ctx context.Context, query *ngmodels.Li
 	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		alertRules := make([]*ngmodels.AlertRule, 0)
 		// TODO rewrite using group by namespace_uid, rule_group
-		q := "SELECT * FROM alert_rule WHERE org_id = ? and namespace_uid = ?"
+		q := "SELECT * FROM alert_rule WHERE org_id = ? and namespace_uid = ? ORDER BY rule_group_index ASC, id ASC"
 		if err := sess.SQL(q, query.OrgID, query.NamespaceUID).Find(&alertRules); err != nil {
 			return err
 		}
@@ -383,6 +433,8 @@ func (st DBstore) GetRuleGroupAlertRules(ctx context.Context, query *ngmodels.Li
 			}
 		}
 
+		q = fmt.Sprintf("%s ORDER BY rule_group_index ASC, id ASC", q)
+
 		alertRules := make([]*ngmodels.AlertRule, 0)
 		if err := sess.SQL(q, args...).Find(&alertRules); err != nil {
 			return err
@@ -426,27 +478,52 @@ func (st DBstore) GetNamespaceByTitle(ctx context.Context, namespace string, org
 	}
 
 	if withCanSave {
-		g := guardian.New(ctx, folder.Id, orgID, user)
-		if canSave, err := g.CanSave(); err != nil || !canSave {
-			if err != nil {
-				st.Logger.Error("checking can save permission has failed", "userId", user.UserId, "username", user.Login, "namespace", namespace, "orgId", orgID, "error", err)
-			}
-			return nil, ngmodels.ErrCannotEditNamespace
+		if err := st.checkCanSaveNamespace(ctx, folder, orgID, user); err != nil {
+			return nil, err
 		}
 	}
 
 	return folder, nil
 }
 
+// GetNamespaceByUID is a handler for retrieving a namespace by its UID.
+func (st DBstore) GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user *models.SignedInUser, withCanSave bool) (*models.Folder, error) {
+	s := dashboards.NewFolderService(orgID, user, st.SQLStore)
+	folder, err := s.GetFolderByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if withCanSave {
+		if err := st.checkCanSaveNamespace(ctx, folder, orgID, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return folder, nil
+}
+
+func (st DBstore) checkCanSaveNamespace(ctx context.Context, folder *models.Folder, orgID int64, user *models.SignedInUser) error {
+	g := guardian.New(ctx, folder.Id, orgID, user)
+	if canSave, err := g.CanSave(); err != nil || !canSave {
+		if err != nil {
+			st.Logger.Error("checking can save permission has failed", "userId", user.UserId, "username", user.Login, "namespace", folder.Title, "orgId", orgID, "error", err)
+		}
+		return ngmodels.ErrCannotEditNamespace
+	}
+	return nil
+}
+
 // GetAlertRulesForScheduling returns alert rule info (identifier, interval, version state)
 // that is useful for it's scheduling.
 func (st DBstore) GetAlertRulesForScheduling(ctx context.Context, query *ngmodels.ListAlertRulesQuery) error {
 	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		alerts := make([]*ngmodels.AlertRule, 0)
-		q := "SELECT uid, org_id, interval_seconds, version FROM alert_rule"
+		q := "SELECT uid, org_id, namespace_uid, rule_group, rule_group_index, interval_seconds, version, is_paused FROM alert_rule"
 		if len(query.ExcludeOrgs) > 0 {
 			q = fmt.Sprintf("%s WHERE org_id NOT IN (%s)", q, strings.Join(strings.Split(strings.Trim(fmt.Sprint(query.ExcludeOrgs), "[]"), " "), ","))
 		}
+		q = fmt.Sprintf("%s ORDER BY rule_group_index ASC, id ASC", q)
 		if err := sess.SQL(q).Find(&alerts); err != nil {
 			return err
 		}
@@ -507,6 +584,19 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 		return fmt.Errorf("%w: cannot have Panel ID without a Dashboard UID", ngmodels.ErrAlertRuleFailedValidation)
 	}
 
+	if alertRule.For < 0 || alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: for and keep_firing_for durations cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if alertRule.Record != nil {
+		if alertRule.Record.Metric == "" {
+			return fmt.Errorf("%w: recording rule has no metric name", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if alertRule.Record.From == "" {
+			return fmt.Errorf("%w: recording rule has no query or expression to record from", ngmodels.ErrAlertRuleFailedValidation)
+		}
+	}
+
 	return nil
 }
 
@@ -530,7 +620,7 @@ func (st DBstore) UpdateRuleGroup(ctx context.Context, cmd UpdateRuleGroupCmd) e
 		}
 
 		upsertRules := make([]UpsertRule, 0)
-		for _, r := range cmd.RuleGroupConfig.Rules {
+		for i, r := range cmd.RuleGroupConfig.Rules {
 			if r.GrafanaManagedAlert == nil {
 				continue
 			}
@@ -544,12 +634,15 @@ func (st DBstore) UpdateRuleGroup(ctx context.Context, cmd UpdateRuleGroupCmd) e
 				IntervalSeconds: int64(time.Duration(cmd.RuleGroupConfig.Interval).Seconds()),
 				NamespaceUID:    cmd.NamespaceUID,
 				RuleGroup:       ruleGroup,
+				RuleGroupIndex:  i + 1,
 				NoDataState:     ngmodels.NoDataState(r.GrafanaManagedAlert.NoDataState),
 				ExecErrState:    ngmodels.ExecutionErrorState(r.GrafanaManagedAlert.ExecErrState),
+				Record:          r.GrafanaManagedAlert.Record,
 			}
 
 			if r.ApiRuleNode != nil {
 				newAlertRule.For = time.Duration(r.ApiRuleNode.For)
+				newAlertRule.KeepFiringFor = time.Duration(r.ApiRuleNode.KeepFiringFor)
 				newAlertRule.Annotations = r.ApiRuleNode.Annotations
 				newAlertRule.Labels = r.ApiRuleNode.Labels
 			}
@@ -572,9 +665,14 @@ func (st DBstore) UpdateRuleGroup(ctx context.Context, cmd UpdateRuleGroupCmd) e
 
 			if existingGroupRule, ok := existingGroupRulesUIDs[r.GrafanaManagedAlert.UID]; ok {
 				upsertRule.Existing = &existingGroupRule
+				// keep the rule paused or running unless the request changes it
+				upsertRule.New.IsPaused = existingGroupRule.IsPaused
 				// remove the rule from existingGroupRulesUIDs
 				delete(existingGroupRulesUIDs, r.GrafanaManagedAlert.UID)
 			}
+			if r.GrafanaManagedAlert.IsPaused != nil {
+				upsertRule.New.IsPaused = *r.GrafanaManagedAlert.IsPaused
+			}
 			upsertRules = append(upsertRules, upsertRule)
 		}
 
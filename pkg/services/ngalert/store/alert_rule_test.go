@@ -0,0 +1,56 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestRecordingRuleRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	rule := models.AlertRule{
+		OrgID:           1,
+		Title:           "recording rule",
+		Condition:       "A",
+		NamespaceUID:    "namespace",
+		RuleGroup:       "recording",
+		IntervalSeconds: baseIntervalSeconds,
+		Data: []models.AlertQuery{
+			{
+				RefID:             "A",
+				DatasourceUID:     "-100",
+				Model:             json.RawMessage(`{"datasourceUid": "-100", "type": "math", "expression": "2 + 2"}`),
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(time.Hour)},
+			},
+		},
+		Record: &models.Record{Metric: "grafana_test_metric", From: "A"},
+	}
+	require.NoError(t, dbstore.UpsertAlertRules(ctx, []store.UpsertRule{{New: rule}}))
+
+	q := models.ListRuleGroupAlertRulesQuery{OrgID: 1, NamespaceUID: "namespace", RuleGroup: "recording"}
+	require.NoError(t, dbstore.GetRuleGroupAlertRules(ctx, &q))
+	require.Len(t, q.Result, 1)
+	require.Equal(t, rule.Record, q.Result[0].Record)
+	require.True(t, q.Result[0].IsRecordingRule())
+
+	byUID := models.GetAlertRuleByUIDQuery{OrgID: 1, UID: q.Result[0].UID}
+	require.NoError(t, dbstore.GetAlertRuleByUID(ctx, &byUID))
+	require.Equal(t, rule.Record, byUID.Result.Record)
+
+	var versions []models.AlertRuleVersion
+	require.NoError(t, dbstore.SQLStore.NewSession(ctx).Where("rule_uid = ?", byUID.Result.UID).Find(&versions))
+	require.Len(t, versions, 1)
+	require.Equal(t, rule.Record, versions[0].Record)
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type ImageStore interface {
+	// GetImage returns the image with the token, or models.ErrImageNotFound
+	// if it does not exist or has expired.
+	GetImage(ctx context.Context, token string) (*models.Image, error)
+	// SaveImage saves the image and sets its ID and, if it has none, its token.
+	SaveImage(ctx context.Context, image *models.Image) error
+	// DeleteExpiredImages deletes the images that have expired and returns how many were deleted.
+	DeleteExpiredImages(ctx context.Context) (int64, error)
+}
+
+func (st DBstore) GetImage(ctx context.Context, token string) (*models.Image, error) {
+	var image models.Image
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		exists, err := sess.Where("token = ? AND expires_at > ?", token, time.Now().UTC()).Get(&image)
+		if err != nil {
+			return fmt.Errorf("failed to get image: %w", err)
+		}
+		if !exists {
+			return models.ErrImageNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (st DBstore) SaveImage(ctx context.Context, image *models.Image) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if image.Token == "" {
+			image.Token = util.GenerateShortUID()
+		}
+		if _, err := sess.Insert(image); err != nil {
+			return fmt.Errorf("failed to save image: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteExpiredImages(ctx context.Context) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_image WHERE expires_at <= ?", time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
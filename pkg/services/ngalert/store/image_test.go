@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestImageStore(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now().UTC()
+	image := models.Image{
+		Path:      "/tmp/image.png",
+		URL:       "https://www.example.com/image.png",
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+	require.NoError(t, dbstore.SaveImage(ctx, &image))
+	require.NotEmpty(t, image.Token)
+	require.NotZero(t, image.ID)
+
+	expired := models.Image{
+		Token:     "expired",
+		Path:      "/tmp/expired.png",
+		CreatedAt: now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	}
+	require.NoError(t, dbstore.SaveImage(ctx, &expired))
+	require.Equal(t, "expired", expired.Token)
+
+	t.Run("gets an image by its token", func(t *testing.T) {
+		result, err := dbstore.GetImage(ctx, image.Token)
+		require.NoError(t, err)
+		require.Equal(t, image.Path, result.Path)
+		require.Equal(t, image.URL, result.URL)
+	})
+
+	t.Run("does not get unknown or expired images", func(t *testing.T) {
+		_, err := dbstore.GetImage(ctx, "unknown")
+		require.ErrorIs(t, err, models.ErrImageNotFound)
+		_, err = dbstore.GetImage(ctx, expired.Token)
+		require.ErrorIs(t, err, models.ErrImageNotFound)
+	})
+
+	t.Run("deletes expired images", func(t *testing.T) {
+		deleted, err := dbstore.DeleteExpiredImages(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		_, err = dbstore.GetImage(ctx, image.Token)
+		require.NoError(t, err)
+	})
+}
@@ -80,4 +80,35 @@ func TestProvisioningStore(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, models.ProvenanceFile, p)
 	})
+	t.Run("Store returns all provenances of a type in an org", func(t *testing.T) {
+		cp1 := models.ProvisionableResource{Type: models.ResourceTypeContactPoint, ID: "cp1", OrgID: 4}
+		cp2 := models.ProvisionableResource{Type: models.ResourceTypeContactPoint, ID: "cp2", OrgID: 4}
+		otherOrg := models.ProvisionableResource{Type: models.ResourceTypeContactPoint, ID: "cp3", OrgID: 5}
+		otherType := models.ProvisionableResource{Type: models.ResourceTypeTemplate, ID: "cp4", OrgID: 4}
+		for _, o := range []models.ProvisionableResource{cp1, cp2, otherOrg, otherType} {
+			require.NoError(t, dbstore.SetProvenance(context.Background(), o, models.ProvenanceFile))
+		}
+		require.NoError(t, dbstore.SetProvenance(context.Background(), cp2, models.ProvenanceApi))
+
+		p, err := dbstore.GetProvenances(context.Background(), 4, models.ResourceTypeContactPoint)
+		require.NoError(t, err)
+		require.Equal(t, map[string]models.Provenance{
+			"cp1": models.ProvenanceFile,
+			"cp2": models.ProvenanceApi,
+		}, p)
+	})
+
+	t.Run("Store deletes provenance", func(t *testing.T) {
+		rule := models.AlertRule{
+			UID:   "abc",
+			OrgID: 1,
+		}
+		require.NoError(t, dbstore.SetProvenance(context.Background(), &rule, models.ProvenanceFile))
+
+		require.NoError(t, dbstore.DeleteProvenance(context.Background(), &rule))
+
+		p, err := dbstore.GetProvenance(context.Background(), &rule)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, p)
+	})
 }
@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// DefaultStateHistoryLimit is the maximum number of entries returned by
+// ListAlertStateHistory if the query has no limit.
+const DefaultStateHistoryLimit = 1000
+
+// stateHistoryPageSize is the minimum number of entries read at once by ListAlertStateHistory
+// if the query has label matchers.
+const stateHistoryPageSize = 100
+
+// maxStateHistoryScanned is the maximum number of entries read by ListAlertStateHistory
+// to find the entries matching the label matchers of the query.
+const maxStateHistoryScanned = 10 * DefaultStateHistoryLimit
+
+type StateHistoryStore interface {
+	SaveAlertStateHistory(ctx context.Context, entries ...models.AlertStateHistoryEntry) error
+	ListAlertStateHistory(ctx context.Context, query *models.ListAlertStateHistoryQuery) error
+	DeleteAlertStateHistoryBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// alertStateHistory is the database representation of models.AlertStateHistoryEntry.
+type alertStateHistory struct {
+	ID            int64                    `xorm:"pk autoincr 'id'"`
+	OrgID         int64                    `xorm:"org_id"`
+	RuleUID       string                   `xorm:"rule_uid"`
+	Labels        models.InstanceLabels    `xorm:"labels"`
+	LabelsHash    string                   `xorm:"labels_hash"`
+	PreviousState models.InstanceStateType `xorm:"previous_state"`
+	CurrentState  models.InstanceStateType `xorm:"current_state"`
+	EvalValues    string                   `xorm:"eval_values"`
+	EvalError     string                   `xorm:"eval_error"`
+	Epoch         int64                    `xorm:"epoch"`
+}
+
+// SaveAlertStateHistory saves changes of the state of alert instances.
+func (st DBstore) SaveAlertStateHistory(ctx context.Context, entries ...models.AlertStateHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		for _, entry := range entries {
+			labelTupleJSON, labelsHash, err := entry.Labels.StringAndHash()
+			if err != nil {
+				return err
+			}
+			values, err := marshalEvaluationValues(entry.Values)
+			if err != nil {
+				return fmt.Errorf("failed to marshal evaluation values: %w", err)
+			}
+			_, err = sess.Exec(`INSERT INTO alert_state_history
+				(org_id, rule_uid, labels, labels_hash, previous_state, current_state, eval_values, eval_error, epoch)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				entry.OrgID, entry.RuleUID, labelTupleJSON, labelsHash, entry.PreviousState, entry.State, values, entry.Error, entry.Timestamp.UnixNano()/int64(time.Millisecond))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListAlertStateHistory returns the state history of the alert instances of a rule,
+// most recent first. Entries are filtered by time range and label matchers.
+func (st DBstore) ListAlertStateHistory(ctx context.Context, query *models.ListAlertStateHistoryQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		s := strings.Builder{}
+		params := make([]interface{}, 0)
+
+		addToQuery := func(stmt string, p ...interface{}) {
+			s.WriteString(stmt)
+			params = append(params, p...)
+		}
+
+		addToQuery("SELECT * FROM alert_state_history WHERE org_id = ? AND rule_uid = ?", query.OrgID, query.RuleUID)
+		if !query.From.IsZero() {
+			addToQuery(" AND epoch >= ?", query.From.UnixNano()/int64(time.Millisecond))
+		}
+		if !query.To.IsZero() {
+			addToQuery(" AND epoch <= ?", query.To.UnixNano()/int64(time.Millisecond))
+		}
+		addToQuery(" ORDER BY epoch DESC, id DESC")
+
+		limit := query.Limit
+		if limit <= 0 {
+			limit = DefaultStateHistoryLimit
+		}
+
+		// Label matchers are applied after reading the entries as labels are stored as JSON,
+		// so the entries are read in pages until enough of them match, or too many were read.
+		pageSize := limit
+		if len(query.Matchers) > 0 && pageSize < stateHistoryPageSize {
+			pageSize = stateHistoryPageSize
+		}
+		result := make([]*models.AlertStateHistoryEntry, 0)
+		for offset := 0; offset < maxStateHistoryScanned; offset += pageSize {
+			rows := make([]*alertStateHistory, 0)
+			if err := sess.SQL(s.String()+st.SQLStore.Dialect.LimitOffset(int64(pageSize), int64(offset)), params...).Find(&rows); err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				if !matchesLabels(query, row.Labels) {
+					continue
+				}
+				entry, err := row.toEntry()
+				if err != nil {
+					return err
+				}
+				result = append(result, entry)
+				if len(result) == limit {
+					break
+				}
+			}
+			if len(result) == limit || len(rows) < pageSize {
+				break
+			}
+		}
+		query.Result = result
+		return nil
+	})
+}
+
+// DeleteAlertStateHistoryBefore deletes the state history entries older than before
+// and returns the number of deleted entries.
+func (st DBstore) DeleteAlertStateHistoryBefore(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_state_history WHERE epoch < ?", before.UnixNano()/int64(time.Millisecond))
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
+func matchesLabels(query *models.ListAlertStateHistoryQuery, labels models.InstanceLabels) bool {
+	for _, m := range query.Matchers {
+		if !m.Matches(labels[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// marshalEvaluationValues marshals the values as strings as JSON does not support NaN and infinity.
+func marshalEvaluationValues(values map[string]*float64) (string, error) {
+	m := make(map[string]*string, len(values))
+	for refID, v := range values {
+		if v == nil {
+			m[refID] = nil
+			continue
+		}
+		s := strconv.FormatFloat(*v, 'g', -1, 64)
+		m[refID] = &s
+	}
+	b, err := json.Marshal(m)
+	return string(b), err
+}
+
+func unmarshalEvaluationValues(s string) (map[string]*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	m := map[string]*string{}
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil, err
+	}
+	values := make(map[string]*float64, len(m))
+	for refID, v := range m {
+		if v == nil {
+			values[refID] = nil
+			continue
+		}
+		f, err := strconv.ParseFloat(*v, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[refID] = &f
+	}
+	return values, nil
+}
+
+func (h alertStateHistory) toEntry() (*models.AlertStateHistoryEntry, error) {
+	values, err := unmarshalEvaluationValues(h.EvalValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal evaluation values of state history entry %d: %w", h.ID, err)
+	}
+	return &models.AlertStateHistoryEntry{
+		ID:            h.ID,
+		OrgID:         h.OrgID,
+		RuleUID:       h.RuleUID,
+		Labels:        h.Labels,
+		PreviousState: h.PreviousState,
+		State:         h.CurrentState,
+		Values:        values,
+		Error:         h.EvalError,
+		Timestamp:     time.Unix(0, h.Epoch*int64(time.Millisecond)),
+	}, nil
+}
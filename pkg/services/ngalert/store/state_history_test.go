@@ -0,0 +1,131 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestAlertStateHistory(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+
+	start := time.Unix(1000, 0)
+	value := 42.5
+	nan := math.NaN()
+	entries := []models.AlertStateHistoryEntry{
+		{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"host": "a"},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStatePending,
+			Values:        map[string]*float64{"B": &value, "C": &nan},
+			Timestamp:     start,
+		},
+		{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"host": "b"},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStateError,
+			Error:         "failed to execute query",
+			Timestamp:     start.Add(time.Minute),
+		},
+		{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"host": "a"},
+			PreviousState: models.InstanceStatePending,
+			State:         models.InstanceStateFiring,
+			Timestamp:     start.Add(2 * time.Minute),
+		},
+	}
+	require.NoError(t, dbstore.SaveAlertStateHistory(ctx, entries...))
+
+	t.Run("lists the history of a rule, most recent first", func(t *testing.T) {
+		q := &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 3)
+		require.Equal(t, models.InstanceStateFiring, q.Result[0].State)
+		require.Equal(t, models.InstanceStatePending, q.Result[0].PreviousState)
+		require.Equal(t, start.Add(2*time.Minute).Unix(), q.Result[0].Timestamp.Unix())
+		require.Equal(t, "failed to execute query", q.Result[1].Error)
+		require.Equal(t, models.InstanceLabels{"host": "a"}, q.Result[2].Labels)
+		require.Equal(t, value, *q.Result[2].Values["B"])
+		require.True(t, math.IsNaN(*q.Result[2].Values["C"]))
+	})
+
+	t.Run("filters by time range, labels and limit", func(t *testing.T) {
+		q := &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID, From: start.Add(30 * time.Second), To: start.Add(90 * time.Second)}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, models.InstanceStateError, q.Result[0].State)
+
+		m, err := labels.NewMatcher(labels.MatchEqual, "host", "a")
+		require.NoError(t, err)
+		q = &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID, Matchers: labels.Matchers{m}, Limit: 1}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, models.InstanceStateFiring, q.Result[0].State)
+
+		q = &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: "other"}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Empty(t, q.Result)
+	})
+
+	t.Run("deletes history older than the retention", func(t *testing.T) {
+		affected, err := dbstore.DeleteAlertStateHistoryBefore(ctx, start.Add(90*time.Second))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), affected)
+
+		q := &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+	})
+
+	t.Run("reads the entries until enough of them match the labels", func(t *testing.T) {
+		other := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+		entries := make([]models.AlertStateHistoryEntry, 0, 250)
+		for i := 0; i < 250; i++ {
+			host := "b"
+			if i%100 == 0 {
+				host = "a"
+			}
+			entries = append(entries, models.AlertStateHistoryEntry{
+				OrgID:         other.OrgID,
+				RuleUID:       other.UID,
+				Labels:        models.InstanceLabels{"host": host},
+				PreviousState: models.InstanceStateNormal,
+				State:         models.InstanceStateFiring,
+				Timestamp:     start.Add(time.Duration(i) * time.Second),
+			})
+		}
+		require.NoError(t, dbstore.SaveAlertStateHistory(ctx, entries...))
+
+		m, err := labels.NewMatcher(labels.MatchEqual, "host", "a")
+		require.NoError(t, err)
+		q := &models.ListAlertStateHistoryQuery{OrgID: other.OrgID, RuleUID: other.UID, Matchers: labels.Matchers{m}, Limit: 3}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 3)
+		require.Equal(t, start.Add(200*time.Second).Unix(), q.Result[0].Timestamp.Unix())
+		require.Equal(t, start.Unix(), q.Result[2].Timestamp.Unix())
+
+		q = &models.ListAlertStateHistoryQuery{OrgID: other.OrgID, RuleUID: other.UID, Limit: 3}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 3)
+	})
+}
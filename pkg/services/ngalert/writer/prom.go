@@ -0,0 +1,205 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ErrInvalidMetricName is returned when the metric name of a recording rule is not a valid Prometheus metric name.
+var ErrInvalidMetricName = errors.New("invalid metric name")
+
+// PrometheusWriter writes series to a Prometheus remote write endpoint.
+type PrometheusWriter struct {
+	url      string
+	user     string
+	password string
+	client   *http.Client
+	metrics  *metrics.RemoteWriter
+	logger   log.Logger
+}
+
+// NewPrometheusWriter returns a writer that sends series to the remote write endpoint in the settings.
+func NewPrometheusWriter(cfg setting.RecordingRuleSettings, m *metrics.RemoteWriter, l log.Logger) *PrometheusWriter {
+	return &PrometheusWriter{
+		url:      cfg.URL,
+		user:     cfg.BasicAuthUsername,
+		password: cfg.BasicAuthPassword,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		metrics:  m,
+		logger:   l,
+	}
+}
+
+// Write converts the frames to series and sends them in a single remote write request.
+// It returns the number of series that were written.
+func (w *PrometheusWriter) Write(ctx context.Context, orgID int64, name string, t time.Time, frames data.Frames, extraLabels map[string]string) (int, error) {
+	series, err := FramesToTimeSeries(name, t, frames, extraLabels)
+	if err != nil {
+		return 0, err
+	}
+	if len(series) == 0 {
+		return 0, nil
+	}
+
+	body, err := remotewrite.TimeSeriesToBytes(series)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.user != "" {
+		req.SetBasicAuth(w.user, w.password)
+	}
+
+	org := fmt.Sprint(orgID)
+	start := time.Now()
+	resp, err := w.client.Do(req)
+	w.metrics.WriteDuration.WithLabelValues(org).Observe(time.Since(start).Seconds())
+	if err != nil {
+		w.metrics.WritesTotal.WithLabelValues(org, "error").Inc()
+		return 0, fmt.Errorf("failed to send remote write request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			w.logger.Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	w.metrics.WritesTotal.WithLabelValues(org, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return 0, fmt.Errorf("unexpected response status code %d from remote write endpoint: %s", resp.StatusCode, string(msg))
+	}
+
+	w.logger.Debug("recording rule results written", "org", orgID, "metric", name, "series", len(series))
+	return len(series), nil
+}
+
+// FramesToTimeSeries converts the numeric fields of the frames to series named after the metric.
+// Frames with a time field produce a sample per row, frames without one (e.g. the output of
+// reduce and math expressions) produce a single sample at time t.
+func FramesToTimeSeries(name string, t time.Time, frames data.Frames, extraLabels map[string]string) ([]prompb.TimeSeries, error) {
+	if !model.IsValidMetricName(model.LabelValue(name)) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMetricName, name)
+	}
+
+	result := make([]prompb.TimeSeries, 0, len(frames))
+	for _, frame := range frames {
+		timeIdx := -1
+		for i, field := range frame.Fields {
+			if field.Type().Time() {
+				timeIdx = i
+				break
+			}
+		}
+
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+
+			samples := make([]prompb.Sample, 0, field.Len())
+			for i := 0; i < field.Len(); i++ {
+				v, ok := field.ConcreteAt(i)
+				if !ok {
+					continue
+				}
+				value, ok := toFloat(v)
+				if !ok {
+					continue
+				}
+				ts := t
+				if timeIdx >= 0 {
+					tv, ok := frame.Fields[timeIdx].ConcreteAt(i)
+					if !ok {
+						continue
+					}
+					ts = tv.(time.Time)
+				}
+				samples = append(samples, prompb.Sample{Value: value, Timestamp: ts.UnixNano() / int64(time.Millisecond)})
+			}
+			if len(samples) == 0 {
+				continue
+			}
+
+			result = append(result, prompb.TimeSeries{
+				Labels:  makeLabels(name, field.Labels, extraLabels),
+				Samples: samples,
+			})
+		}
+	}
+	return result, nil
+}
+
+// makeLabels merges the labels of a series with the extra labels and the metric name.
+// Labels with names that are not valid in Prometheus are dropped.
+func makeLabels(name string, seriesLabels data.Labels, extraLabels map[string]string) []prompb.Label {
+	merged := make(map[string]string, len(seriesLabels)+len(extraLabels)+1)
+	for k, v := range seriesLabels {
+		merged[k] = v
+	}
+	for k, v := range extraLabels {
+		merged[k] = v
+	}
+	merged[model.MetricNameLabel] = name
+
+	result := make([]prompb.Label, 0, len(merged))
+	for k, v := range merged {
+		if !model.LabelName(k).IsValid() {
+			continue
+		}
+		result = append(result, prompb.Label{Name: k, Value: v})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case int8:
+		return float64(val), true
+	case int16:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint8:
+		return float64(val), true
+	case uint16:
+		return float64(val), true
+	case uint32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	}
+	return 0, false
+}
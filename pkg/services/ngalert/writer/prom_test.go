@@ -0,0 +1,119 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestFramesToTimeSeries(t *testing.T) {
+	now := time.Unix(1000, 0)
+	value := 3.5
+
+	t.Run("number frames are written at the evaluation time", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("", data.NewField("", data.Labels{"instance": "a"}, []*float64{&value})),
+			data.NewFrame("", data.NewField("", data.Labels{"instance": "b"}, []*float64{nil})),
+		}
+
+		series, err := FramesToTimeSeries("job:requests:rate5m", now, frames, map[string]string{"team": "ops"})
+		require.NoError(t, err)
+		require.Equal(t, []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "job:requests:rate5m"},
+					{Name: "instance", Value: "a"},
+					{Name: "team", Value: "ops"},
+				},
+				Samples: []prompb.Sample{{Value: 3.5, Timestamp: 1000000}},
+			},
+		}, series)
+	})
+
+	t.Run("time series frames are written at the time of each row", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{time.Unix(10, 0), time.Unix(20, 0)}),
+				data.NewField("value", data.Labels{"instance": "a"}, []float64{1, 2}),
+			),
+		}
+
+		series, err := FramesToTimeSeries("requests", now, frames, nil)
+		require.NoError(t, err)
+		require.Len(t, series, 1)
+		require.Equal(t, []prompb.Sample{{Value: 1, Timestamp: 10000}, {Value: 2, Timestamp: 20000}}, series[0].Samples)
+	})
+
+	t.Run("invalid metric name returns an error", func(t *testing.T) {
+		_, err := FramesToTimeSeries("1-invalid", now, nil, nil)
+		require.ErrorIs(t, err, ErrInvalidMetricName)
+	})
+}
+
+func TestPrometheusWriter_Write(t *testing.T) {
+	value := 1.0
+	frames := data.Frames{data.NewFrame("", data.NewField("", data.Labels{"instance": "a"}, []*float64{&value}))}
+
+	t.Run("sends a remote write request", func(t *testing.T) {
+		var received prompb.WriteRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			require.True(t, ok)
+			require.Equal(t, "user", user)
+			require.Equal(t, "password", password)
+			require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+
+			compressed, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			b, err := snappy.Decode(nil, compressed)
+			require.NoError(t, err)
+			require.NoError(t, proto.Unmarshal(b, &received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		w := newTestWriter(server.URL)
+		n, err := w.Write(context.Background(), 1, "metric", time.Unix(1, 0), frames, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.Len(t, received.Timeseries, 1)
+		require.Equal(t, "metric", received.Timeseries[0].Labels[0].Value)
+	})
+
+	t.Run("returns an error on unexpected status code", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		w := newTestWriter(server.URL)
+		_, err := w.Write(context.Background(), 1, "metric", time.Unix(1, 0), frames, nil)
+		require.Error(t, err)
+	})
+}
+
+func newTestWriter(url string) *PrometheusWriter {
+	cfg := setting.RecordingRuleSettings{
+		Enabled:           true,
+		URL:               url,
+		BasicAuthUsername: "user",
+		BasicAuthPassword: "password",
+		Timeout:           time.Second,
+	}
+	m := metrics.NewNGAlert(prometheus.NewRegistry()).GetRemoteWriterMetrics()
+	return NewPrometheusWriter(cfg, m, log.New("test"))
+}
@@ -0,0 +1,23 @@
+// Package writer writes the results of recording rules as time series.
+package writer
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Writer writes the results of a recording rule evaluation.
+type Writer interface {
+	// Write writes the numeric values of the frames as series with the given metric name.
+	// Extra labels are added to every series and take precedence over the labels of the frames.
+	Write(ctx context.Context, orgID int64, name string, t time.Time, frames data.Frames, extraLabels map[string]string) (int, error)
+}
+
+// NoopWriter discards all results. It is used when recording rules are disabled.
+type NoopWriter struct{}
+
+func (w NoopWriter) Write(_ context.Context, _ int64, _ string, _ time.Time, _ data.Frames, _ map[string]string) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,565 @@
+package alerting
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/alertmanager/config"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Provision alert rules, contact points, notification policies, mute timings and templates from the
+// configuration files in the given directory.
+func Provision(ctx context.Context, configDirectory string, cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, secretsService secrets.Service) error {
+	dbStore := &store.DBstore{
+		BaseInterval:    cfg.UnifiedAlerting.BaseInterval,
+		DefaultInterval: cfg.UnifiedAlerting.DefaultAlertForDuration,
+		SQLStore:        sqlStore,
+		Logger:          log.New("alerting.provisioning.store"),
+	}
+
+	ap := newAlertingProvisioner(dbStore, dbStore, dbStore, &dashboardFolderService{service: dashboards.NewProvisioningService(sqlStore)}, secretsService, cfg.UnifiedAlerting.DefaultConfiguration)
+	return ap.applyChanges(ctx, configDirectory)
+}
+
+// ruleStore is the part of the alert rule store used by the provisioner.
+type ruleStore interface {
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) error
+	InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRule) error
+	UpsertAlertRules(ctx context.Context, rules []store.UpsertRule) error
+	DeleteAlertRuleByUID(ctx context.Context, orgID int64, ruleUID string) error
+	GetOrgs(ctx context.Context) ([]int64, error)
+}
+
+// amConfigStore is the part of the Alertmanager configuration store used by the provisioner.
+type amConfigStore interface {
+	GetLatestAlertmanagerConfiguration(ctx context.Context, query *ngmodels.GetLatestAlertmanagerConfigurationQuery) error
+	SaveAlertmanagerConfiguration(ctx context.Context, cmd *ngmodels.SaveAlertmanagerConfigurationCmd) error
+}
+
+// folderService returns the UID of the folder with the given title, creating it if it does not exist.
+type folderService interface {
+	GetOrCreateFolder(ctx context.Context, orgID int64, title string) (string, error)
+}
+
+// AlertingProvisioner is responsible for provisioning alerting resources from configuration files.
+type AlertingProvisioner struct {
+	log             log.Logger
+	cfgProvider     *configReader
+	ruleStore       ruleStore
+	amStore         amConfigStore
+	provenanceStore store.ProvisioningStore
+	folders         folderService
+	secrets         secrets.Service
+	defaultConfig   string
+}
+
+func newAlertingProvisioner(ruleStore ruleStore, amStore amConfigStore, provenanceStore store.ProvisioningStore, folders folderService, secretsService secrets.Service, defaultConfig string) *AlertingProvisioner {
+	logger := log.New("provisioning.alerting")
+	return &AlertingProvisioner{
+		log:             logger,
+		cfgProvider:     &configReader{log: logger},
+		ruleStore:       ruleStore,
+		amStore:         amStore,
+		provenanceStore: provenanceStore,
+		folders:         folders,
+		secrets:         secretsService,
+		defaultConfig:   defaultConfig,
+	}
+}
+
+func (ap *AlertingProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := ap.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := ap.applyRuleChanges(ctx, cfg); err != nil {
+			return err
+		}
+	}
+	if err := ap.deleteRemovedRules(ctx, configs); err != nil {
+		return err
+	}
+
+	return ap.applyAlertmanagerChanges(ctx, configs)
+}
+
+func (ap *AlertingProvisioner) applyRuleChanges(ctx context.Context, cfg *alertingConfig) error {
+	for _, d := range cfg.DeleteRules {
+		if err := ap.deleteRule(ctx, d.OrgID, d.UID); err != nil {
+			return err
+		}
+	}
+
+	for _, group := range cfg.Groups {
+		folderUID, err := ap.folders.GetOrCreateFolder(ctx, group.OrgID, group.Folder)
+		if err != nil {
+			return fmt.Errorf("failed to get folder %q of rule group %q: %w", group.Folder, group.Name, err)
+		}
+
+		var inserts []ngmodels.AlertRule
+		var upserts []store.UpsertRule
+		for _, rule := range group.Rules {
+			rule.NamespaceUID = folderUID
+			query := &ngmodels.GetAlertRuleByUIDQuery{UID: rule.UID, OrgID: rule.OrgID}
+			err := ap.ruleStore.GetAlertRuleByUID(ctx, query)
+			switch {
+			case errors.Is(err, ngmodels.ErrAlertRuleNotFound):
+				inserts = append(inserts, rule)
+			case err != nil:
+				return err
+			default:
+				// the store keeps the folder and group of existing rules, so pass the ones of
+				// the file to move the rule if it was changed
+				existing := *query.Result
+				existing.NamespaceUID = rule.NamespaceUID
+				existing.RuleGroup = rule.RuleGroup
+				upserts = append(upserts, store.UpsertRule{Existing: &existing, New: rule})
+			}
+		}
+
+		ap.log.Debug("Provisioning rule group", "name", group.Name, "folder", group.Folder, "org", group.OrgID, "new", len(inserts), "updated", len(upserts))
+		if len(inserts) > 0 {
+			if err := ap.ruleStore.InsertAlertRules(ctx, inserts); err != nil {
+				return fmt.Errorf("failed to provision rule group %q: %w", group.Name, err)
+			}
+		}
+		if len(upserts) > 0 {
+			if err := ap.ruleStore.UpsertAlertRules(ctx, upserts); err != nil {
+				return fmt.Errorf("failed to provision rule group %q: %w", group.Name, err)
+			}
+		}
+
+		for i := range group.Rules {
+			if err := ap.provenanceStore.SetProvenance(ctx, &group.Rules[i], ngmodels.ProvenanceFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteRemovedRules deletes the rules that were provisioned from files but are no longer in any of them,
+// like the dashboards whose files are removed.
+func (ap *AlertingProvisioner) deleteRemovedRules(ctx context.Context, configs []*alertingConfig) error {
+	provisioned := make(map[int64]map[string]struct{})
+	for _, cfg := range configs {
+		for _, group := range cfg.Groups {
+			for _, rule := range group.Rules {
+				if provisioned[rule.OrgID] == nil {
+					provisioned[rule.OrgID] = make(map[string]struct{})
+				}
+				provisioned[rule.OrgID][rule.UID] = struct{}{}
+			}
+		}
+	}
+
+	orgIDs, err := ap.ruleStore.GetOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, orgID := range orgIDs {
+		provenances, err := ap.provenanceStore.GetProvenances(ctx, orgID, (&ngmodels.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for uid, p := range provenances {
+			if _, ok := provisioned[orgID][uid]; ok || p != ngmodels.ProvenanceFile {
+				continue
+			}
+			if err := ap.deleteRule(ctx, orgID, uid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteRule deletes the rule and its provenance, if it exists.
+func (ap *AlertingProvisioner) deleteRule(ctx context.Context, orgID int64, uid string) error {
+	query := &ngmodels.GetAlertRuleByUIDQuery{UID: uid, OrgID: orgID}
+	if err := ap.ruleStore.GetAlertRuleByUID(ctx, query); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ap.provenanceStore.DeleteProvenance(ctx, &ngmodels.AlertRule{UID: uid, OrgID: orgID})
+		}
+		return err
+	}
+	ap.log.Debug("Deleting alert rule", "uid", uid, "org", orgID)
+	if err := ap.ruleStore.DeleteAlertRuleByUID(ctx, orgID, uid); err != nil {
+		return fmt.Errorf("failed to delete alert rule %q: %w", uid, err)
+	}
+	return ap.provenanceStore.DeleteProvenance(ctx, query.Result)
+}
+
+// applyAlertmanagerChanges applies the changes of all files to the Alertmanager configuration of every
+// organization they target. The configuration of an organization is only saved if all its changes are valid.
+func (ap *AlertingProvisioner) applyAlertmanagerChanges(ctx context.Context, configs []*alertingConfig) error {
+	orgIDs := make(map[int64]struct{})
+	for _, cfg := range configs {
+		for _, cp := range cfg.ContactPoints {
+			orgIDs[cp.OrgID] = struct{}{}
+		}
+		for _, p := range cfg.Policies {
+			orgIDs[p.OrgID] = struct{}{}
+		}
+		for _, orgID := range cfg.ResetPolicies {
+			orgIDs[orgID] = struct{}{}
+		}
+		for _, mt := range cfg.MuteTimes {
+			orgIDs[mt.OrgID] = struct{}{}
+		}
+		for _, t := range cfg.Templates {
+			orgIDs[t.OrgID] = struct{}{}
+		}
+		for _, deleted := range [][]*deleteResource{cfg.DeleteContactPoints, cfg.DeleteMuteTimes, cfg.DeleteTemplates} {
+			for _, d := range deleted {
+				orgIDs[d.OrgID] = struct{}{}
+			}
+		}
+	}
+
+	sorted := make([]int64, 0, len(orgIDs))
+	for orgID := range orgIDs {
+		sorted = append(sorted, orgID)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for _, orgID := range sorted {
+		if err := ap.applyOrgAlertmanagerChanges(ctx, orgID, configs); err != nil {
+			return fmt.Errorf("failed to provision Alertmanager configuration of organization %d: %w", orgID, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) applyOrgAlertmanagerChanges(ctx context.Context, orgID int64, configs []*alertingConfig) error {
+	amCfg, raw, err := ap.getAlertmanagerConfig(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	var provisioned, unprovisioned []ngmodels.ProvisionableResource
+	resource := func(resourceType, id string) ngmodels.ProvisionableResource {
+		return ngmodels.ProvisionableResource{Type: resourceType, ID: id, OrgID: orgID}
+	}
+
+	for _, cfg := range configs {
+		for _, d := range cfg.DeleteContactPoints {
+			if d.OrgID != orgID {
+				continue
+			}
+			removeReceiver(amCfg, d.Name)
+			unprovisioned = append(unprovisioned, resource(ngmodels.ResourceTypeContactPoint, d.Name))
+		}
+		for _, d := range cfg.DeleteMuteTimes {
+			if d.OrgID != orgID {
+				continue
+			}
+			removeMuteTiming(amCfg, d.Name)
+			unprovisioned = append(unprovisioned, resource(ngmodels.ResourceTypeMuteTiming, d.Name))
+		}
+		for _, d := range cfg.DeleteTemplates {
+			if d.OrgID != orgID {
+				continue
+			}
+			delete(amCfg.TemplateFiles, d.Name)
+			unprovisioned = append(unprovisioned, resource(ngmodels.ResourceTypeTemplate, d.Name))
+		}
+		for _, resetOrgID := range cfg.ResetPolicies {
+			if resetOrgID != orgID {
+				continue
+			}
+			defaultCfg, err := notifier.Load([]byte(ap.defaultConfig))
+			if err != nil {
+				return fmt.Errorf("failed to parse default Alertmanager configuration: %w", err)
+			}
+			amCfg.AlertmanagerConfig.Route = defaultCfg.AlertmanagerConfig.Route
+			unprovisioned = append(unprovisioned, resource(ngmodels.ResourceTypeNotificationPolicy, ngmodels.NotificationPolicyResourceID))
+		}
+
+		for _, cp := range cfg.ContactPoints {
+			if cp.OrgID != orgID {
+				continue
+			}
+			if err := ap.upsertReceiver(ctx, amCfg, cp); err != nil {
+				return err
+			}
+			provisioned = append(provisioned, resource(ngmodels.ResourceTypeContactPoint, cp.Name))
+		}
+		for _, mt := range cfg.MuteTimes {
+			if mt.OrgID != orgID {
+				continue
+			}
+			removeMuteTiming(amCfg, mt.MuteTiming.Name)
+			amCfg.AlertmanagerConfig.MuteTimeIntervals = append(amCfg.AlertmanagerConfig.MuteTimeIntervals, mt.MuteTiming)
+			provisioned = append(provisioned, resource(ngmodels.ResourceTypeMuteTiming, mt.MuteTiming.Name))
+		}
+		for _, t := range cfg.Templates {
+			if t.OrgID != orgID {
+				continue
+			}
+			if amCfg.TemplateFiles == nil {
+				amCfg.TemplateFiles = make(map[string]string)
+			}
+			amCfg.TemplateFiles[t.Name] = t.Template
+			provisioned = append(provisioned, resource(ngmodels.ResourceTypeTemplate, t.Name))
+		}
+		for _, p := range cfg.Policies {
+			if p.OrgID != orgID {
+				continue
+			}
+			policy := p.Policy
+			amCfg.AlertmanagerConfig.Route = &policy
+			provisioned = append(provisioned, resource(ngmodels.ResourceTypeNotificationPolicy, ngmodels.NotificationPolicyResourceID))
+		}
+	}
+
+	if err := validateAlertmanagerConfig(amCfg); err != nil {
+		return err
+	}
+
+	newRaw, err := json.Marshal(amCfg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize Alertmanager configuration: %w", err)
+	}
+	if string(newRaw) != raw {
+		ap.log.Debug("Saving provisioned Alertmanager configuration", "org", orgID)
+		cmd := &ngmodels.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(newRaw),
+			ConfigurationVersion:      fmt.Sprintf("v%d", ngmodels.AlertConfigurationVersion),
+			OrgID:                     orgID,
+		}
+		if err := ap.amStore.SaveAlertmanagerConfiguration(ctx, cmd); err != nil {
+			return err
+		}
+	}
+
+	for i := range unprovisioned {
+		if err := ap.provenanceStore.DeleteProvenance(ctx, &unprovisioned[i]); err != nil {
+			return err
+		}
+	}
+	for i := range provisioned {
+		if err := ap.provenanceStore.SetProvenance(ctx, &provisioned[i], ngmodels.ProvenanceFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAlertmanagerConfig returns the latest Alertmanager configuration of the organization, or the default
+// configuration if there is none, together with its serialized form.
+func (ap *AlertingProvisioner) getAlertmanagerConfig(ctx context.Context, orgID int64) (*apimodels.PostableUserConfig, string, error) {
+	raw := ap.defaultConfig
+	query := &ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID}
+	if err := ap.amStore.GetLatestAlertmanagerConfiguration(ctx, query); err != nil {
+		if !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return nil, "", err
+		}
+	} else {
+		raw = query.Result.AlertmanagerConfiguration
+	}
+
+	cfg, err := notifier.Load([]byte(raw))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse Alertmanager configuration: %w", err)
+	}
+
+	// re-serialize the configuration so that it can be compared with the provisioned one
+	normalized, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to serialize Alertmanager configuration: %w", err)
+	}
+	return cfg, string(normalized), nil
+}
+
+// upsertReceiver replaces the receiver with the name of the contact point, or adds it if it does not exist.
+// Secure settings are encrypted, but the stored value is kept if it did not change so that provisioning
+// unchanged files does not change the configuration.
+func (ap *AlertingProvisioner) upsertReceiver(ctx context.Context, amCfg *apimodels.PostableUserConfig, cp *contactPoint) error {
+	var existing *apimodels.PostableApiReceiver
+	for _, r := range amCfg.AlertmanagerConfig.Receivers {
+		if r.Name == cp.Name {
+			existing = r
+			break
+		}
+	}
+
+	existingByUID := make(map[string]*apimodels.PostableGrafanaReceiver)
+	if existing != nil {
+		for _, gr := range existing.GrafanaManagedReceivers {
+			existingByUID[gr.UID] = gr
+		}
+	}
+
+	receivers := make([]*apimodels.PostableGrafanaReceiver, 0, len(cp.Receivers))
+	for _, recv := range cp.Receivers {
+		gr := *recv
+		if gr.Settings == nil || gr.Settings.Interface() == nil {
+			gr.Settings = simplejson.New()
+		}
+
+		secureSettings := make(map[string]string, len(recv.SecureSettings))
+		for k, v := range recv.SecureSettings {
+			if current, ok := existingByUID[gr.UID]; ok {
+				if stored, ok := current.SecureSettings[k]; ok && ap.decryptsTo(ctx, stored, v) {
+					secureSettings[k] = stored
+					continue
+				}
+			}
+			encrypted, err := ap.secrets.Encrypt(ctx, []byte(v), secrets.WithoutScope())
+			if err != nil {
+				return fmt.Errorf("failed to encrypt secure settings of contact point %q: %w", cp.Name, err)
+			}
+			secureSettings[k] = base64.StdEncoding.EncodeToString(encrypted)
+		}
+		if len(secureSettings) > 0 {
+			gr.SecureSettings = secureSettings
+		} else {
+			gr.SecureSettings = nil
+		}
+		receivers = append(receivers, &gr)
+	}
+
+	if existing != nil {
+		existing.GrafanaManagedReceivers = receivers
+		return nil
+	}
+
+	amCfg.AlertmanagerConfig.Receivers = append(amCfg.AlertmanagerConfig.Receivers, &apimodels.PostableApiReceiver{
+		Receiver:                 config.Receiver{Name: cp.Name},
+		PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{GrafanaManagedReceivers: receivers},
+	})
+	return nil
+}
+
+func (ap *AlertingProvisioner) decryptsTo(ctx context.Context, stored, value string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return false
+	}
+	decrypted, err := ap.secrets.Decrypt(ctx, decoded)
+	if err != nil {
+		return false
+	}
+	return string(decrypted) == value
+}
+
+func removeReceiver(amCfg *apimodels.PostableUserConfig, name string) {
+	receivers := amCfg.AlertmanagerConfig.Receivers[:0]
+	for _, r := range amCfg.AlertmanagerConfig.Receivers {
+		if r.Name != name {
+			receivers = append(receivers, r)
+		}
+	}
+	amCfg.AlertmanagerConfig.Receivers = receivers
+}
+
+func removeMuteTiming(amCfg *apimodels.PostableUserConfig, name string) {
+	muteTimings := amCfg.AlertmanagerConfig.MuteTimeIntervals[:0]
+	for _, mt := range amCfg.AlertmanagerConfig.MuteTimeIntervals {
+		if mt.Name != name {
+			muteTimings = append(muteTimings, mt)
+		}
+	}
+	amCfg.AlertmanagerConfig.MuteTimeIntervals = muteTimings
+}
+
+// validateAlertmanagerConfig checks that the notification policies only reference existing contact points
+// and mute timings, and that the UIDs of the receivers are unique.
+func validateAlertmanagerConfig(amCfg *apimodels.PostableUserConfig) error {
+	receivers := make(map[string]struct{}, len(amCfg.AlertmanagerConfig.Receivers))
+	uids := make(map[string]struct{})
+	for _, r := range amCfg.AlertmanagerConfig.Receivers {
+		receivers[r.Name] = struct{}{}
+		for _, gr := range r.GrafanaManagedReceivers {
+			if _, ok := uids[gr.UID]; ok {
+				return fmt.Errorf("receiver UID %q of contact point %q is not unique", gr.UID, r.Name)
+			}
+			uids[gr.UID] = struct{}{}
+		}
+	}
+
+	muteTimings := make(map[string]struct{}, len(amCfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range amCfg.AlertmanagerConfig.MuteTimeIntervals {
+		muteTimings[mt.Name] = struct{}{}
+	}
+
+	if amCfg.AlertmanagerConfig.Route == nil {
+		return errors.New("the configuration has no notification policies")
+	}
+	return validateRoute(amCfg.AlertmanagerConfig.Route, receivers, muteTimings)
+}
+
+func validateRoute(route *apimodels.Route, receivers, muteTimings map[string]struct{}) error {
+	if route.Receiver != "" {
+		if _, ok := receivers[route.Receiver]; !ok {
+			return fmt.Errorf("notification policy references contact point %q that does not exist", route.Receiver)
+		}
+	}
+	for _, name := range route.MuteTimeIntervals {
+		if _, ok := muteTimings[name]; !ok {
+			return fmt.Errorf("notification policy references mute timing %q that does not exist", name)
+		}
+	}
+	for _, r := range route.Routes {
+		if err := validateRoute(r, receivers, muteTimings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dashboardFolderService gets and creates folders like the dashboards provisioner.
+type dashboardFolderService struct {
+	service dashboards.DashboardProvisioningService
+}
+
+func (s *dashboardFolderService) GetOrCreateFolder(ctx context.Context, orgID int64, title string) (string, error) {
+	cmd := &models.GetDashboardQuery{Slug: models.SlugifyTitle(title), OrgId: orgID}
+	err := bus.Dispatch(ctx, cmd)
+
+	if err != nil && !errors.Is(err, models.ErrDashboardNotFound) {
+		return "", err
+	}
+
+	// folder not found. create one.
+	if errors.Is(err, models.ErrDashboardNotFound) {
+		dash := &dashboards.SaveDashboardDTO{}
+		dash.Dashboard = models.NewDashboardFolder(title)
+		dash.Dashboard.IsFolder = true
+		dash.Overwrite = true
+		dash.OrgId = orgID
+		dbDash, err := s.service.SaveFolderForProvisionedDashboards(ctx, dash)
+		if err != nil {
+			return "", err
+		}
+
+		return dbDash.Uid, nil
+	}
+
+	if !cmd.Result.IsFolder {
+		return "", fmt.Errorf("got invalid response. expected folder, found dashboard")
+	}
+
+	return cmd.Result.Uid, nil
+}
@@ -0,0 +1,158 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeFolderService struct {
+	folders map[string]string
+}
+
+func (f *fakeFolderService) GetOrCreateFolder(_ context.Context, _ int64, title string) (string, error) {
+	if uid, ok := f.folders[title]; ok {
+		return uid, nil
+	}
+	uid := "folder-" + title
+	f.folders[title] = uid
+	return uid, nil
+}
+
+func TestAlertingProvisioner(t *testing.T) {
+	setup := func(t *testing.T) (*AlertingProvisioner, *store.DBstore) {
+		t.Helper()
+		dbStore := &store.DBstore{
+			BaseInterval:    10 * time.Second,
+			DefaultInterval: time.Minute,
+			SQLStore:        setupTestDB(t),
+			Logger:          log.New("test"),
+		}
+		ap := newAlertingProvisioner(dbStore, dbStore, dbStore, &fakeFolderService{folders: map[string]string{}}, fakes.NewFakeSecretsService(), setting.GetAlertmanagerDefaultConfiguration())
+		return ap, dbStore
+	}
+
+	getConfig := func(t *testing.T, dbStore *store.DBstore) *ngmodels.AlertConfiguration {
+		t.Helper()
+		query := &ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: 1}
+		require.NoError(t, dbStore.GetLatestAlertmanagerConfiguration(context.Background(), query))
+		return query.Result
+	}
+
+	t.Run("provisions rules and the Alertmanager configuration", func(t *testing.T) {
+		ap, dbStore := setup(t)
+		ctx := context.Background()
+
+		require.NoError(t, ap.applyChanges(ctx, correctProperties))
+
+		query := &ngmodels.GetAlertRuleByUIDQuery{UID: "my-rule", OrgID: 1}
+		require.NoError(t, dbStore.GetAlertRuleByUID(ctx, query))
+		require.Equal(t, "My rule", query.Result.Title)
+		require.Equal(t, "folder-my-folder", query.Result.NamespaceUID)
+		require.Equal(t, "my-group", query.Result.RuleGroup)
+		require.Equal(t, int64(60), query.Result.IntervalSeconds)
+		provenance, err := dbStore.GetProvenance(ctx, query.Result)
+		require.NoError(t, err)
+		require.Equal(t, ngmodels.ProvenanceFile, provenance)
+
+		amCfg, err := notifier.Load([]byte(getConfig(t, dbStore).AlertmanagerConfiguration))
+		require.NoError(t, err)
+		require.Equal(t, "ops", amCfg.AlertmanagerConfig.Route.Receiver)
+		require.Len(t, amCfg.AlertmanagerConfig.MuteTimeIntervals, 1)
+		require.Contains(t, amCfg.TemplateFiles, "my-template")
+		var receiverNames []string
+		for _, r := range amCfg.AlertmanagerConfig.Receivers {
+			receiverNames = append(receiverNames, r.Name)
+		}
+		require.Contains(t, receiverNames, "ops")
+
+		for _, resourceType := range []string{ngmodels.ResourceTypeContactPoint, ngmodels.ResourceTypeNotificationPolicy, ngmodels.ResourceTypeMuteTiming, ngmodels.ResourceTypeTemplate} {
+			provenances, err := dbStore.GetProvenances(ctx, 1, resourceType)
+			require.NoError(t, err)
+			require.Len(t, provenances, 1, resourceType)
+		}
+	})
+
+	t.Run("provisioning the same files again does not save a new Alertmanager configuration", func(t *testing.T) {
+		ap, dbStore := setup(t)
+		ctx := context.Background()
+
+		require.NoError(t, ap.applyChanges(ctx, correctProperties))
+		cfg := getConfig(t, dbStore)
+		query := &ngmodels.GetAlertRuleByUIDQuery{UID: "my-rule", OrgID: 1}
+		require.NoError(t, dbStore.GetAlertRuleByUID(ctx, query))
+		version := query.Result.Version
+
+		require.NoError(t, ap.applyChanges(ctx, correctProperties))
+		require.Equal(t, cfg.ID, getConfig(t, dbStore).ID)
+		require.NoError(t, dbStore.GetAlertRuleByUID(ctx, query))
+		require.Equal(t, version+1, query.Result.Version)
+	})
+
+	t.Run("deletes provisioned resources", func(t *testing.T) {
+		ap, dbStore := setup(t)
+		ctx := context.Background()
+
+		require.NoError(t, ap.applyChanges(ctx, correctProperties))
+		require.NoError(t, ap.applyChanges(ctx, deleteResources))
+
+		err := dbStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{UID: "my-rule", OrgID: 1})
+		require.ErrorIs(t, err, ngmodels.ErrAlertRuleNotFound)
+		provenance, err := dbStore.GetProvenance(ctx, &ngmodels.AlertRule{UID: "my-rule", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, ngmodels.ProvenanceNone, provenance)
+
+		amCfg, err := notifier.Load([]byte(getConfig(t, dbStore).AlertmanagerConfiguration))
+		require.NoError(t, err)
+		require.NotContains(t, amCfg.TemplateFiles, "my-template")
+		require.Equal(t, "grafana-default-email", amCfg.AlertmanagerConfig.Route.Receiver)
+
+		provenances, err := dbStore.GetProvenances(ctx, 1, ngmodels.ResourceTypeNotificationPolicy)
+		require.NoError(t, err)
+		require.Empty(t, provenances)
+	})
+
+	t.Run("deletes the rules that are removed from the files", func(t *testing.T) {
+		ap, dbStore := setup(t)
+		ctx := context.Background()
+
+		uiRule := tests.CreateTestAlertRule(t, ctx, dbStore, 60, 1)
+		require.NoError(t, ap.applyChanges(ctx, correctProperties))
+		require.NoError(t, ap.applyChanges(ctx, emptyFile))
+
+		for _, uid := range []string{"my-rule", "my-recording-rule"} {
+			err := dbStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{UID: uid, OrgID: 1})
+			require.ErrorIs(t, err, ngmodels.ErrAlertRuleNotFound, uid)
+		}
+		provenances, err := dbStore.GetProvenances(ctx, 1, uiRule.ResourceType())
+		require.NoError(t, err)
+		require.Empty(t, provenances)
+
+		require.NoError(t, dbStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{UID: uiRule.UID, OrgID: 1}))
+		amCfg, err := notifier.Load([]byte(getConfig(t, dbStore).AlertmanagerConfiguration))
+		require.NoError(t, err)
+		require.Contains(t, amCfg.TemplateFiles, "my-template")
+	})
+
+	t.Run("invalid Alertmanager configuration is not saved", func(t *testing.T) {
+		ap, dbStore := setup(t)
+		ctx := context.Background()
+
+		err := ap.applyChanges(ctx, invalidPolicy)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does-not-exist")
+
+		err = dbStore.GetLatestAlertmanagerConfiguration(ctx, &ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: 1})
+		require.ErrorIs(t, err, store.ErrNoAlertmanagerConfiguration)
+	})
+}
@@ -0,0 +1,239 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*alertingConfig, error) {
+	var configs []*alertingConfig
+	cr.log.Debug("Looking for alerting provisioning files", "path", path)
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Can't read alerting provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing alerting provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseConfig(path, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse alerting provisioning file %q: %w", file.Name(), err)
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	cr.log.Debug("Validating alerting provisioning files")
+	if err := cr.validateRequiredFields(configs); err != nil {
+		return nil, err
+	}
+
+	if err := cr.checkOrgIDs(ctx, configs); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+func (cr *configReader) parseConfig(path string, file os.FileInfo) (*alertingConfig, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	if err := yaml.Unmarshal(yamlFile, &apiVersion); err != nil {
+		return nil, err
+	}
+	if apiVersion == nil {
+		// empty file
+		return nil, nil
+	}
+	if apiVersion.APIVersion != 1 {
+		return nil, fmt.Errorf("unsupported apiVersion %d, expected 1", apiVersion.APIVersion)
+	}
+
+	var v1 *alertingConfigV1
+	if err := yaml.Unmarshal(yamlFile, &v1); err != nil {
+		return nil, err
+	}
+
+	return v1.mapToAlertingConfig()
+}
+
+func (cr *configReader) validateRequiredFields(configs []*alertingConfig) error {
+	var errStrings []string
+	for _, cfg := range configs {
+		for i, group := range cfg.Groups {
+			if group.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Rule group item %d in configuration doesn't contain required field name", i+1))
+			}
+			if group.Folder == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Rule group %q in configuration doesn't contain required field folder", group.Name))
+			}
+			if group.Interval <= 0 {
+				errStrings = append(errStrings, fmt.Sprintf("Rule group %q in configuration doesn't contain required field interval", group.Name))
+			}
+			for j, rule := range group.Rules {
+				if rule.UID == "" {
+					errStrings = append(errStrings, fmt.Sprintf("Rule item %d of rule group %q in configuration doesn't contain required field uid", j+1, group.Name))
+				}
+				if rule.Title == "" {
+					errStrings = append(errStrings, fmt.Sprintf("Rule item %d of rule group %q in configuration doesn't contain required field title", j+1, group.Name))
+				}
+			}
+		}
+
+		for i, rule := range cfg.DeleteRules {
+			if rule.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Deleted rule item %d in configuration doesn't contain required field uid", i+1))
+			}
+		}
+
+		for i, cp := range cfg.ContactPoints {
+			if cp.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Contact point item %d in configuration doesn't contain required field name", i+1))
+			}
+			if len(cp.Receivers) == 0 {
+				errStrings = append(errStrings, fmt.Sprintf("Contact point %q in configuration doesn't contain any receivers", cp.Name))
+			}
+			for j, recv := range cp.Receivers {
+				if recv.UID == "" {
+					errStrings = append(errStrings, fmt.Sprintf("Receiver item %d of contact point %q in configuration doesn't contain required field uid", j+1, cp.Name))
+				}
+				if recv.Type == "" {
+					errStrings = append(errStrings, fmt.Sprintf("Receiver item %d of contact point %q in configuration doesn't contain required field type", j+1, cp.Name))
+				}
+			}
+		}
+
+		for i, policy := range cfg.Policies {
+			if policy.Policy.Receiver == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Notification policy item %d in configuration doesn't contain required field receiver", i+1))
+			}
+		}
+
+		for i, mt := range cfg.MuteTimes {
+			if mt.MuteTiming.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Mute timing item %d in configuration doesn't contain required field name", i+1))
+			}
+		}
+
+		for i, t := range cfg.Templates {
+			if t.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Template item %d in configuration doesn't contain required field name", i+1))
+			}
+		}
+
+		for _, deleted := range []struct {
+			kind  string
+			items []*deleteResource
+		}{
+			{kind: "contact point", items: cfg.DeleteContactPoints},
+			{kind: "mute timing", items: cfg.DeleteMuteTimes},
+			{kind: "template", items: cfg.DeleteTemplates},
+		} {
+			for i, d := range deleted.items {
+				if d.Name == "" {
+					errStrings = append(errStrings, fmt.Sprintf("Deleted %s item %d in configuration doesn't contain required field name", deleted.kind, i+1))
+				}
+			}
+		}
+	}
+
+	if len(errStrings) != 0 {
+		return fmt.Errorf(strings.Join(errStrings, "\n"))
+	}
+	return nil
+}
+
+// checkOrgIDs defaults the organization of every item to the main organization and checks
+// that the organizations of the items exist.
+func (cr *configReader) checkOrgIDs(ctx context.Context, configs []*alertingConfig) error {
+	checked := make(map[int64]struct{})
+	check := func(orgID *int64) error {
+		if *orgID < 1 {
+			*orgID = 1
+		}
+		if _, ok := checked[*orgID]; ok {
+			return nil
+		}
+		if err := utils.CheckOrgExists(ctx, *orgID); err != nil {
+			return err
+		}
+		checked[*orgID] = struct{}{}
+		return nil
+	}
+
+	for _, cfg := range configs {
+		for _, group := range cfg.Groups {
+			if err := check(&group.OrgID); err != nil {
+				return fmt.Errorf("failed to provision rule group %q: %w", group.Name, err)
+			}
+			for i := range group.Rules {
+				group.Rules[i].OrgID = group.OrgID
+			}
+		}
+		for _, rule := range cfg.DeleteRules {
+			if err := check(&rule.OrgID); err != nil {
+				return fmt.Errorf("failed to delete rule %q: %w", rule.UID, err)
+			}
+		}
+		for _, cp := range cfg.ContactPoints {
+			if err := check(&cp.OrgID); err != nil {
+				return fmt.Errorf("failed to provision contact point %q: %w", cp.Name, err)
+			}
+		}
+		for _, policy := range cfg.Policies {
+			if err := check(&policy.OrgID); err != nil {
+				return fmt.Errorf("failed to provision notification policies: %w", err)
+			}
+		}
+		for i := range cfg.ResetPolicies {
+			if err := check(&cfg.ResetPolicies[i]); err != nil {
+				return fmt.Errorf("failed to reset notification policies: %w", err)
+			}
+		}
+		for _, mt := range cfg.MuteTimes {
+			if err := check(&mt.OrgID); err != nil {
+				return fmt.Errorf("failed to provision mute timing %q: %w", mt.MuteTiming.Name, err)
+			}
+		}
+		for _, t := range cfg.Templates {
+			if err := check(&t.OrgID); err != nil {
+				return fmt.Errorf("failed to provision template %q: %w", t.Name, err)
+			}
+		}
+		for _, deleted := range [][]*deleteResource{cfg.DeleteContactPoints, cfg.DeleteMuteTimes, cfg.DeleteTemplates} {
+			for _, d := range deleted {
+				if err := check(&d.OrgID); err != nil {
+					return fmt.Errorf("failed to delete %q: %w", d.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+var (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	noRequiredFields  = "./testdata/test-configs/no-required-fields"
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	emptyFile         = "./testdata/test-configs/empty"
+	unknownOrg        = "./testdata/test-configs/unknown-org"
+	invalidPolicy     = "./testdata/test-configs/invalid-policy"
+	deleteResources   = "./testdata/test-configs/delete"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+)
+
+func setupTestDB(t *testing.T) *sqlstore.SQLStore {
+	t.Helper()
+	sqlStore := sqlstore.InitTestDB(t)
+	err := sqlstore.CreateOrg(context.Background(), &models.CreateOrgCommand{Name: "Main Org."})
+	require.NoError(t, err)
+	return sqlStore
+}
+
+func TestAlertingAsConfig(t *testing.T) {
+	cfgProvider := &configReader{log: log.New("test logger")}
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		setupTestDB(t)
+
+		cfg, err := cfgProvider.readConfig(context.Background(), correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Len(t, cfg[0].Groups, 1)
+		group := cfg[0].Groups[0]
+		require.Equal(t, int64(1), group.OrgID)
+		require.Equal(t, "my-group", group.Name)
+		require.Equal(t, "my-folder", group.Folder)
+		require.Equal(t, time.Minute, group.Interval)
+
+		require.Len(t, group.Rules, 2)
+		rule := group.Rules[0]
+		require.Equal(t, int64(1), rule.OrgID)
+		require.Equal(t, "my-rule", rule.UID)
+		require.Equal(t, "My rule", rule.Title)
+		require.Equal(t, "A", rule.Condition)
+		require.Equal(t, "my-group", rule.RuleGroup)
+		require.Equal(t, int64(60), rule.IntervalSeconds)
+		require.Equal(t, 1, rule.RuleGroupIndex)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, 10*time.Minute, rule.KeepFiringFor)
+		require.Equal(t, ngmodels.OK, rule.NoDataState)
+		require.Equal(t, ngmodels.AlertingErrState, rule.ExecErrState)
+		require.Equal(t, map[string]string{"summary": "my summary"}, rule.Annotations)
+		require.Equal(t, map[string]string{"team": "ops"}, rule.Labels)
+		require.Len(t, rule.Data, 1)
+		require.Equal(t, "A", rule.Data[0].RefID)
+		require.Equal(t, "-100", rule.Data[0].DatasourceUID)
+		require.Equal(t, ngmodels.Duration(10*time.Minute), rule.Data[0].RelativeTimeRange.From)
+		require.JSONEq(t, `{"type": "math", "expression": "2 + 3 > 1"}`, string(rule.Data[0].Model))
+		require.Nil(t, rule.Record)
+
+		recordingRule := group.Rules[1]
+		require.Equal(t, 2, recordingRule.RuleGroupIndex)
+		require.Equal(t, &ngmodels.Record{Metric: "my_metric", From: "A"}, recordingRule.Record)
+
+		require.Len(t, cfg[0].ContactPoints, 1)
+		cp := cfg[0].ContactPoints[0]
+		require.Equal(t, "ops", cp.Name)
+		require.Len(t, cp.Receivers, 1)
+		require.Equal(t, "ops-slack", cp.Receivers[0].UID)
+		require.Equal(t, "ops", cp.Receivers[0].Name)
+		require.Equal(t, "slack", cp.Receivers[0].Type)
+		require.Equal(t, "#ops", cp.Receivers[0].Settings.Get("recipient").MustString())
+		require.Equal(t, map[string]string{"url": "https://hooks.slack.com/services/secret"}, cp.Receivers[0].SecureSettings)
+
+		require.Len(t, cfg[0].Policies, 1)
+		policy := cfg[0].Policies[0]
+		require.Equal(t, int64(1), policy.OrgID)
+		require.Equal(t, "ops", policy.Policy.Receiver)
+		require.Equal(t, []string{"alertname"}, policy.Policy.GroupByStr)
+		require.Len(t, policy.Policy.Routes, 1)
+		require.Equal(t, []string{"weekends"}, policy.Policy.Routes[0].MuteTimeIntervals)
+
+		require.Len(t, cfg[0].MuteTimes, 1)
+		require.Equal(t, int64(1), cfg[0].MuteTimes[0].OrgID)
+		require.Equal(t, "weekends", cfg[0].MuteTimes[0].MuteTiming.Name)
+		require.Len(t, cfg[0].MuteTimes[0].MuteTiming.TimeIntervals, 2)
+		require.Equal(t, "Europe/Berlin", cfg[0].MuteTimes[0].MuteTiming.TimeIntervals[0].Location.String())
+		require.Equal(t, []apimodels.DateRange{{StartDate: "2022-12-24", EndDate: "2022-12-26"}}, cfg[0].MuteTimes[0].MuteTiming.TimeIntervals[1].DateRanges)
+
+		require.Len(t, cfg[0].Templates, 1)
+		require.Equal(t, "my-template", cfg[0].Templates[0].Name)
+		require.Equal(t, `{{ define "my-template" }}custom message{{ end }}`, cfg[0].Templates[0].Template)
+	})
+
+	t.Run("Can read an export of the alerting configuration", func(t *testing.T) {
+		setupTestDB(t)
+
+		var policy apimodels.Route
+		require.NoError(t, json.Unmarshal([]byte(`{"receiver": "ops", "routes": [{"receiver": "ops", "object_matchers": [["team", "=", "ops"]]}]}`), &policy))
+		var muteTiming apimodels.MuteTimeInterval
+		require.NoError(t, json.Unmarshal([]byte(`{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"], "location": "Europe/Berlin"}]}`), &muteTiming))
+		export := apimodels.AlertingFileExport{
+			APIVersion: 1,
+			Groups: []apimodels.AlertRuleGroupExport{{
+				OrgID:    1,
+				Name:     "my-group",
+				Folder:   "my-folder",
+				Interval: "1m",
+				Rules: []apimodels.AlertRuleExport{{
+					UID:   "my-recording-rule",
+					Title: "My recording rule",
+					Data: []apimodels.AlertQueryExport{{
+						RefID:             "A",
+						RelativeTimeRange: apimodels.RelativeTimeRangeExport{From: 600},
+						DatasourceUID:     "-100",
+						Model:             map[string]interface{}{"type": "math", "expression": "2 + 3"},
+					}},
+					KeepFiringFor: "1m30s",
+					Record:        &ngmodels.Record{Metric: "my_metric", From: "A"},
+				}},
+			}},
+			ContactPoints: []apimodels.ContactPointExport{{
+				OrgID: 1,
+				Name:  "ops",
+				Receivers: []apimodels.ReceiverExport{{
+					UID:            "ops-slack",
+					Type:           "slack",
+					Settings:       map[string]interface{}{"recipient": "#ops"},
+					SecureSettings: map[string]string{"url": apimodels.RedactedValue},
+				}},
+			}},
+			Policies:  []apimodels.NotificationPolicyExport{{OrgID: 1, Route: policy}},
+			MuteTimes: []apimodels.MuteTimingExport{{OrgID: 1, MuteTimeInterval: muteTiming}},
+			Templates: []apimodels.TemplateExport{{OrgID: 1, Name: "my-template", Template: `{{ define "my-template" }}{{ end }}`}},
+		}
+		b, err := yaml.Marshal(export)
+		require.NoError(t, err)
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "alerting.yaml"), b, 0600))
+
+		cfg, err := cfgProvider.readConfig(context.Background(), dir)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Len(t, cfg[0].Groups, 1)
+		require.Equal(t, "my-folder", cfg[0].Groups[0].Folder)
+		rule := cfg[0].Groups[0].Rules[0]
+		require.Equal(t, "my-recording-rule", rule.UID)
+		require.Equal(t, 90*time.Second, rule.KeepFiringFor)
+		require.Equal(t, &ngmodels.Record{Metric: "my_metric", From: "A"}, rule.Record)
+		require.Equal(t, ngmodels.Duration(10*time.Minute), rule.Data[0].RelativeTimeRange.From)
+		require.JSONEq(t, `{"type": "math", "expression": "2 + 3"}`, string(rule.Data[0].Model))
+
+		require.Equal(t, "#ops", cfg[0].ContactPoints[0].Receivers[0].Settings.Get("recipient").MustString())
+		require.Equal(t, map[string]string{"url": apimodels.RedactedValue}, cfg[0].ContactPoints[0].Receivers[0].SecureSettings)
+		require.Equal(t, policy, cfg[0].Policies[0].Policy)
+		require.Equal(t, muteTiming, cfg[0].MuteTimes[0].MuteTiming)
+		require.Equal(t, `{{ define "my-template" }}{{ end }}`, cfg[0].Templates[0].Template)
+	})
+
+	t.Run("Default organization is the main organization", func(t *testing.T) {
+		setupTestDB(t)
+
+		cfg, err := cfgProvider.readConfig(context.Background(), deleteResources)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Equal(t, int64(1), cfg[0].DeleteRules[0].OrgID)
+		require.Equal(t, []int64{1}, cfg[0].ResetPolicies)
+	})
+
+	t.Run("Missing required fields should fail validation", func(t *testing.T) {
+		setupTestDB(t)
+
+		_, err := cfgProvider.readConfig(context.Background(), noRequiredFields)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Rule group item 1 in configuration doesn't contain required field name")
+		require.Contains(t, err.Error(), "doesn't contain required field folder")
+		require.Contains(t, err.Error(), "doesn't contain required field interval")
+		require.Contains(t, err.Error(), "Rule item 1 of rule group \"\" in configuration doesn't contain required field uid")
+		require.Contains(t, err.Error(), "Contact point item 1 in configuration doesn't contain required field name")
+		require.Contains(t, err.Error(), "doesn't contain required field type")
+		require.Contains(t, err.Error(), "Deleted rule item 1 in configuration doesn't contain required field uid")
+	})
+
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		setupTestDB(t)
+
+		_, err := cfgProvider.readConfig(context.Background(), brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Unknown organization should return error", func(t *testing.T) {
+		setupTestDB(t)
+
+		_, err := cfgProvider.readConfig(context.Background(), unknownOrg)
+		require.ErrorIs(t, err, models.ErrOrgNotFound)
+	})
+
+	t.Run("Empty yaml file or missing folder should not return error", func(t *testing.T) {
+		setupTestDB(t)
+
+		cfg, err := cfgProvider.readConfig(context.Background(), emptyFile)
+		require.NoError(t, err)
+		require.Empty(t, cfg)
+
+		cfg, err = cfgProvider.readConfig(context.Background(), emptyFolder)
+		require.NoError(t, err)
+		require.Empty(t, cfg)
+	})
+}
@@ -0,0 +1,371 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// alertingConfig is the normalized data object for alerting config data. Any config version should be mappable
+// to this type.
+type alertingConfig struct {
+	Groups              []*ruleGroup
+	DeleteRules         []*deleteRule
+	ContactPoints       []*contactPoint
+	DeleteContactPoints []*deleteResource
+	Policies            []*notificationPolicy
+	ResetPolicies       []int64
+	MuteTimes           []*muteTiming
+	DeleteMuteTimes     []*deleteResource
+	Templates           []*template
+	DeleteTemplates     []*deleteResource
+}
+
+type ruleGroup struct {
+	OrgID    int64
+	Name     string
+	Folder   string
+	Interval time.Duration
+	Rules    []ngmodels.AlertRule
+}
+
+type deleteRule struct {
+	OrgID int64
+	UID   string
+}
+
+type contactPoint struct {
+	OrgID     int64
+	Name      string
+	Receivers []*apimodels.PostableGrafanaReceiver
+}
+
+type notificationPolicy struct {
+	OrgID  int64
+	Policy apimodels.Route
+}
+
+type muteTiming struct {
+	OrgID      int64
+	MuteTiming apimodels.MuteTimeInterval
+}
+
+type template struct {
+	OrgID    int64
+	Name     string
+	Template string
+}
+
+// deleteResource is a contact point, mute timing or template to delete, identified by its name.
+type deleteResource struct {
+	OrgID int64
+	Name  string
+}
+
+// alertingConfigV1 is the mapping for version 1 configs. This is mapped to its normalised version.
+type alertingConfigV1 struct {
+	configVersion
+
+	Groups              []*ruleGroupV1          `json:"groups" yaml:"groups"`
+	DeleteRules         []*deleteRuleV1         `json:"deleteRules" yaml:"deleteRules"`
+	ContactPoints       []*contactPointV1       `json:"contactPoints" yaml:"contactPoints"`
+	DeleteContactPoints []*deleteResourceV1     `json:"deleteContactPoints" yaml:"deleteContactPoints"`
+	Policies            []*notificationPolicyV1 `json:"policies" yaml:"policies"`
+	ResetPolicies       []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
+	MuteTimes           []*muteTimingV1         `json:"muteTimes" yaml:"muteTimes"`
+	DeleteMuteTimes     []*deleteResourceV1     `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
+	Templates           []*templateV1           `json:"templates" yaml:"templates"`
+	DeleteTemplates     []*deleteResourceV1     `json:"deleteTemplates" yaml:"deleteTemplates"`
+}
+
+type ruleGroupV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+	Folder   values.StringValue `json:"folder" yaml:"folder"`
+	Interval values.StringValue `json:"interval" yaml:"interval"`
+	Rules    []*ruleV1          `json:"rules" yaml:"rules"`
+}
+
+type ruleV1 struct {
+	UID           values.StringValue    `json:"uid" yaml:"uid"`
+	Title         values.StringValue    `json:"title" yaml:"title"`
+	Condition     values.StringValue    `json:"condition" yaml:"condition"`
+	Data          []*queryV1            `json:"data" yaml:"data"`
+	For           values.StringValue    `json:"for" yaml:"for"`
+	KeepFiringFor values.StringValue    `json:"keepFiringFor" yaml:"keepFiringFor"`
+	NoDataState   values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	Annotations   values.StringMapValue `json:"annotations" yaml:"annotations"`
+	Labels        values.StringMapValue `json:"labels" yaml:"labels"`
+	IsPaused      values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	Record        *recordV1             `json:"record" yaml:"record"`
+}
+
+// recordV1 turns a rule into a recording rule that writes the results of a query or expression as a new series.
+type recordV1 struct {
+	Metric values.StringValue `json:"metric" yaml:"metric"`
+	From   values.StringValue `json:"from" yaml:"from"`
+}
+
+type queryV1 struct {
+	RefID             values.StringValue  `json:"refId" yaml:"refId"`
+	QueryType         values.StringValue  `json:"queryType" yaml:"queryType"`
+	RelativeTimeRange relativeTimeRangeV1 `json:"relativeTimeRange" yaml:"relativeTimeRange"`
+	DatasourceUID     values.StringValue  `json:"datasourceUid" yaml:"datasourceUid"`
+	Model             values.JSONValue    `json:"model" yaml:"model"`
+}
+
+// relativeTimeRangeV1 is the relative time range of a query in seconds.
+type relativeTimeRangeV1 struct {
+	From values.Int64Value `json:"from" yaml:"from"`
+	To   values.Int64Value `json:"to" yaml:"to"`
+}
+
+type deleteRuleV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+type contactPointV1 struct {
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name      values.StringValue `json:"name" yaml:"name"`
+	Receivers []*receiverV1      `json:"receivers" yaml:"receivers"`
+}
+
+type receiverV1 struct {
+	UID                   values.StringValue    `json:"uid" yaml:"uid"`
+	Type                  values.StringValue    `json:"type" yaml:"type"`
+	DisableResolveMessage values.BoolValue      `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+	Settings              values.JSONValue      `json:"settings" yaml:"settings"`
+	SecureSettings        values.StringMapValue `json:"secureSettings" yaml:"secureSettings"`
+}
+
+// notificationPolicyV1 is a notification policy tree in the format of the Alertmanager configuration,
+// with the organization it belongs to.
+type notificationPolicyV1 struct {
+	OrgID  values.Int64Value
+	Policy apimodels.Route
+}
+
+func (p *notificationPolicyV1) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var org struct {
+		OrgID values.Int64Value `yaml:"orgId"`
+	}
+	if err := unmarshal(&org); err != nil {
+		return err
+	}
+	p.OrgID = org.OrgID
+	return unmarshal(&p.Policy)
+}
+
+// muteTimingV1 is a mute timing in the format of the Alertmanager configuration, with the organization it belongs to.
+type muteTimingV1 struct {
+	OrgID      values.Int64Value
+	MuteTiming apimodels.MuteTimeInterval
+}
+
+func (m *muteTimingV1) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var org struct {
+		OrgID values.Int64Value `yaml:"orgId"`
+	}
+	if err := unmarshal(&org); err != nil {
+		return err
+	}
+	m.OrgID = org.OrgID
+	return unmarshal(&m.MuteTiming)
+}
+
+type templateV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+	Template values.StringValue `json:"template" yaml:"template"`
+}
+
+type deleteResourceV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+// mapToAlertingConfig maps config syntax to the normalized alertingConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *alertingConfigV1) mapToAlertingConfig() (*alertingConfig, error) {
+	r := &alertingConfig{}
+	if cfg == nil {
+		return r, nil
+	}
+
+	for _, g := range cfg.Groups {
+		group, err := g.mapToRuleGroup()
+		if err != nil {
+			return nil, err
+		}
+		r.Groups = append(r.Groups, group)
+	}
+
+	for _, d := range cfg.DeleteRules {
+		r.DeleteRules = append(r.DeleteRules, &deleteRule{
+			OrgID: d.OrgID.Value(),
+			UID:   d.UID.Value(),
+		})
+	}
+
+	for _, cp := range cfg.ContactPoints {
+		c := &contactPoint{
+			OrgID: cp.OrgID.Value(),
+			Name:  cp.Name.Value(),
+		}
+		for _, recv := range cp.Receivers {
+			c.Receivers = append(c.Receivers, &apimodels.PostableGrafanaReceiver{
+				UID:                   recv.UID.Value(),
+				Name:                  c.Name,
+				Type:                  recv.Type.Value(),
+				DisableResolveMessage: recv.DisableResolveMessage.Value(),
+				Settings:              simplejson.NewFromAny(recv.Settings.Value()),
+				SecureSettings:        recv.SecureSettings.Value(),
+			})
+		}
+		r.ContactPoints = append(r.ContactPoints, c)
+	}
+	r.DeleteContactPoints = mapToDeleteResources(cfg.DeleteContactPoints)
+
+	for _, p := range cfg.Policies {
+		r.Policies = append(r.Policies, &notificationPolicy{
+			OrgID:  p.OrgID.Value(),
+			Policy: p.Policy,
+		})
+	}
+	for _, orgID := range cfg.ResetPolicies {
+		r.ResetPolicies = append(r.ResetPolicies, orgID.Value())
+	}
+
+	for _, mt := range cfg.MuteTimes {
+		r.MuteTimes = append(r.MuteTimes, &muteTiming{
+			OrgID:      mt.OrgID.Value(),
+			MuteTiming: mt.MuteTiming,
+		})
+	}
+	r.DeleteMuteTimes = mapToDeleteResources(cfg.DeleteMuteTimes)
+
+	for _, t := range cfg.Templates {
+		r.Templates = append(r.Templates, &template{
+			OrgID:    t.OrgID.Value(),
+			Name:     t.Name.Value(),
+			Template: t.Template.Value(),
+		})
+	}
+	r.DeleteTemplates = mapToDeleteResources(cfg.DeleteTemplates)
+
+	return r, nil
+}
+
+func (g *ruleGroupV1) mapToRuleGroup() (*ruleGroup, error) {
+	group := &ruleGroup{
+		OrgID:  g.OrgID.Value(),
+		Name:   g.Name.Value(),
+		Folder: g.Folder.Value(),
+	}
+
+	if interval := g.Interval.Value(); interval != "" {
+		d, err := model.ParseDuration(interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval of rule group %q: %w", group.Name, err)
+		}
+		group.Interval = time.Duration(d)
+	}
+
+	for i, rule := range g.Rules {
+		alertRule := ngmodels.AlertRule{
+			OrgID:           group.OrgID,
+			UID:             rule.UID.Value(),
+			Title:           rule.Title.Value(),
+			Condition:       rule.Condition.Value(),
+			IntervalSeconds: int64(group.Interval.Seconds()),
+			RuleGroup:       group.Name,
+			RuleGroupIndex:  i + 1,
+			NoDataState:     ngmodels.NoDataState(rule.NoDataState.Value()),
+			ExecErrState:    ngmodels.ExecutionErrorState(rule.ExecErrState.Value()),
+			Annotations:     rule.Annotations.Value(),
+			Labels:          rule.Labels.Value(),
+			IsPaused:        rule.IsPaused.Value(),
+		}
+
+		if f := rule.For.Value(); f != "" {
+			d, err := model.ParseDuration(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid for duration of rule %q: %w", alertRule.Title, err)
+			}
+			alertRule.For = time.Duration(d)
+		}
+
+		if f := rule.KeepFiringFor.Value(); f != "" {
+			d, err := model.ParseDuration(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid keepFiringFor duration of rule %q: %w", alertRule.Title, err)
+			}
+			alertRule.KeepFiringFor = time.Duration(d)
+		}
+
+		if rule.Record != nil {
+			alertRule.Record = &ngmodels.Record{
+				Metric: rule.Record.Metric.Value(),
+				From:   rule.Record.From.Value(),
+			}
+		}
+
+		if s := alertRule.Annotations[ngmodels.DashboardUIDAnnotation]; s != "" {
+			alertRule.DashboardUID = &s
+		}
+		if s := alertRule.Annotations[ngmodels.PanelIDAnnotation]; s != "" {
+			panelID, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("the %s annotation of rule %q does not contain a valid panel ID: %w", ngmodels.PanelIDAnnotation, alertRule.Title, err)
+			}
+			alertRule.PanelID = &panelID
+		}
+
+		for _, q := range rule.Data {
+			m, err := json.Marshal(q.Model.Value())
+			if err != nil {
+				return nil, fmt.Errorf("invalid model of query %q of rule %q: %w", q.RefID.Value(), alertRule.Title, err)
+			}
+			alertRule.Data = append(alertRule.Data, ngmodels.AlertQuery{
+				RefID:     q.RefID.Value(),
+				QueryType: q.QueryType.Value(),
+				RelativeTimeRange: ngmodels.RelativeTimeRange{
+					From: ngmodels.Duration(time.Duration(q.RelativeTimeRange.From.Value()) * time.Second),
+					To:   ngmodels.Duration(time.Duration(q.RelativeTimeRange.To.Value()) * time.Second),
+				},
+				DatasourceUID: q.DatasourceUID.Value(),
+				Model:         m,
+			})
+		}
+
+		group.Rules = append(group.Rules, alertRule)
+	}
+
+	return group, nil
+}
+
+func mapToDeleteResources(cfg []*deleteResourceV1) []*deleteResource {
+	var r []*deleteResource
+	for _, d := range cfg {
+		r = append(r, &deleteResource{
+			OrgID: d.OrgID.Value(),
+			Name:  d.Name.Value(),
+		})
+	}
+	return r
+}
@@ -10,28 +10,33 @@ import (
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, pluginStore plugifaces.Store,
-	encryptionService encryption.Internal, notificatonService *notifications.NotificationService) (*ProvisioningServiceImpl, error) {
+	encryptionService encryption.Internal, notificatonService *notifications.NotificationService,
+	secretsService secrets.Service) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                     cfg,
 		SQLStore:                sqlStore,
 		pluginStore:             pluginStore,
 		EncryptionService:       encryptionService,
 		NotificationService:     notificatonService,
+		SecretsService:          secretsService,
 		log:                     log.New("provisioning"),
 		newDashboardProvisioner: dashboards.New,
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
+		provisionAlerting:       alerting.Provision,
 	}
 	return s, nil
 }
@@ -42,6 +47,7 @@ type ProvisioningService interface {
 	ProvisionDatasources(ctx context.Context) error
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionNotifications(ctx context.Context) error
+	ProvisionAlerting(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
@@ -55,6 +61,7 @@ func NewProvisioningServiceImpl() *ProvisioningServiceImpl {
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
+		provisionAlerting:       alerting.Provision,
 	}
 }
 
@@ -64,6 +71,7 @@ func newProvisioningServiceImpl(
 	provisionNotifiers func(context.Context, string, encryption.Internal, *notifications.NotificationService) error,
 	provisionDatasources func(context.Context, string) error,
 	provisionPlugins func(context.Context, string, plugifaces.Store) error,
+	provisionAlerting func(context.Context, string, *setting.Cfg, *sqlstore.SQLStore, secrets.Service) error,
 ) *ProvisioningServiceImpl {
 	return &ProvisioningServiceImpl{
 		log:                     log.New("provisioning"),
@@ -71,6 +79,7 @@ func newProvisioningServiceImpl(
 		provisionNotifiers:      provisionNotifiers,
 		provisionDatasources:    provisionDatasources,
 		provisionPlugins:        provisionPlugins,
+		provisionAlerting:       provisionAlerting,
 	}
 }
 
@@ -80,6 +89,7 @@ type ProvisioningServiceImpl struct {
 	pluginStore             plugifaces.Store
 	EncryptionService       encryption.Internal
 	NotificationService     *notifications.NotificationService
+	SecretsService          secrets.Service
 	log                     log.Logger
 	pollingCtxCancel        context.CancelFunc
 	newDashboardProvisioner dashboards.DashboardProvisionerFactory
@@ -87,6 +97,7 @@ type ProvisioningServiceImpl struct {
 	provisionNotifiers      func(context.Context, string, encryption.Internal, *notifications.NotificationService) error
 	provisionDatasources    func(context.Context, string) error
 	provisionPlugins        func(context.Context, string, plugifaces.Store) error
+	provisionAlerting       func(context.Context, string, *setting.Cfg, *sqlstore.SQLStore, secrets.Service) error
 	mutex                   sync.Mutex
 }
 
@@ -106,6 +117,11 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	err = ps.ProvisionAlerting(ctx)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -168,6 +184,20 @@ func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) e
 	return nil
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
+	if !ps.Cfg.UnifiedAlerting.IsEnabled() {
+		return nil
+	}
+
+	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	if err := ps.provisionAlerting(ctx, alertingPath, ps.Cfg, ps.SQLStore, ps.SecretsService); err != nil {
+		err = errutil.Wrap("Alerting provisioning error", err)
+		ps.log.Error("Failed to provision alerting", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
 	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.SQLStore)
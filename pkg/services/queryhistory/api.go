@@ -1,6 +1,7 @@
 package queryhistory
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -13,8 +14,12 @@ import (
 
 func (s *QueryHistoryService) registerAPIEndpoints() {
 	s.RouteRegister.Group("/api/query-history", func(entities routing.RouteRegister) {
+		entities.Get("/", middleware.ReqSignedIn, routing.Wrap(s.searchHandler))
 		entities.Post("/", middleware.ReqSignedIn, routing.Wrap(s.createHandler))
 		entities.Delete("/:uid", middleware.ReqSignedIn, routing.Wrap(s.deleteHandler))
+		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(s.patchHandler))
+		entities.Post("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.starHandler))
+		entities.Delete("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.unstarHandler))
 	})
 }
 
@@ -32,6 +37,29 @@ func (s *QueryHistoryService) createHandler(c *models.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
 }
 
+func (s *QueryHistoryService) searchHandler(c *models.ReqContext) response.Response {
+	query := SearchInQueryHistoryQuery{
+		DatasourceUIDs: c.QueryStrings("datasourceUid"),
+		SearchString:   c.Query("searchString"),
+		OnlyStarred:    c.QueryBool("onlyStarred"),
+		Sort:           c.Query("sort"),
+		Page:           c.QueryInt("page"),
+		Limit:          c.QueryInt("limit"),
+		From:           c.QueryInt64("from"),
+		To:             c.QueryInt64("to"),
+	}
+
+	result, err := s.SearchInQueryHistory(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		if errors.Is(err, ErrInvalidSearchSort) || errors.Is(err, ErrInvalidSearchTimeSpan) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get query history", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryHistorySearchResponse{Result: result})
+}
+
 func (s *QueryHistoryService) deleteHandler(c *models.ReqContext) response.Response {
 	queryUID := web.Params(c.Req)[":uid"]
 	if len(queryUID) == 0 {
@@ -52,3 +80,63 @@ func (s *QueryHistoryService) deleteHandler(c *models.ReqContext) response.Respo
 		ID:      id,
 	})
 }
+
+func (s *QueryHistoryService) patchHandler(c *models.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) == 0 || !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	cmd := PatchQueryInQueryHistoryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	query, err := s.PatchQueryInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID, cmd)
+	if err != nil {
+		return queryHistoryErrorResponse(err, "Failed to update query in query history")
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
+}
+
+func (s *QueryHistoryService) starHandler(c *models.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) == 0 || !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	query, err := s.StarQueryInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID)
+	if err != nil {
+		return queryHistoryErrorResponse(err, "Failed to star query in query history")
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
+}
+
+func (s *QueryHistoryService) unstarHandler(c *models.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) == 0 || !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	query, err := s.UnstarQueryInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID)
+	if err != nil {
+		return queryHistoryErrorResponse(err, "Failed to unstar query in query history")
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
+}
+
+func queryHistoryErrorResponse(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, ErrQueryNotFound):
+		return response.Error(http.StatusNotFound, "Query in query history not found", err)
+	case errors.Is(err, ErrStarredQueryNotFound):
+		return response.Error(http.StatusNotFound, "Starred query not found", err)
+	case errors.Is(err, ErrQueryAlreadyStarred):
+		return response.Error(http.StatusBadRequest, "Query was already starred", err)
+	default:
+		return response.Error(http.StatusInternalServerError, message, err)
+	}
+}
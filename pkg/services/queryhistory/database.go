@@ -2,13 +2,20 @@ package queryhistory
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/util"
 )
 
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
 func (s QueryHistoryService) createQuery(ctx context.Context, user *models.SignedInUser, cmd CreateQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
 	queryHistory := QueryHistory{
 		OrgID:         user.OrgId,
@@ -28,22 +35,99 @@ func (s QueryHistoryService) createQuery(ctx context.Context, user *models.Signe
 		return QueryHistoryDTO{}, err
 	}
 
-	dto := QueryHistoryDTO{
-		UID:           queryHistory.UID,
-		DatasourceUID: queryHistory.DatasourceUID,
-		CreatedBy:     queryHistory.CreatedBy,
-		CreatedAt:     queryHistory.CreatedAt,
-		Comment:       queryHistory.Comment,
-		Queries:       queryHistory.Queries,
-		Starred:       false,
+	return toDTO(queryHistory, false), nil
+}
+
+func (s QueryHistoryService) searchQueries(ctx context.Context, user *models.SignedInUser, query SearchInQueryHistoryQuery) (QueryHistorySearchResult, error) {
+	if query.Sort == "" {
+		query.Sort = SortTimeDesc
+	}
+	if query.Sort != SortTimeDesc && query.Sort != SortTimeAsc {
+		return QueryHistorySearchResult{}, ErrInvalidSearchSort
+	}
+	if query.From > 0 && query.To > 0 && query.From > query.To {
+		return QueryHistorySearchResult{}, ErrInvalidSearchTimeSpan
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
 	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+
+	result := QueryHistorySearchResult{
+		QueryHistory: make([]QueryHistoryDTO, 0),
+		Page:         query.Page,
+		PerPage:      query.Limit,
+	}
+
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		dialect := s.SQLStore.Dialect
+
+		filter := sqlstore.SQLBuilder{}
+		filter.Write(` FROM query_history
+	LEFT JOIN query_history_star ON query_history_star.query_uid = query_history.uid AND query_history_star.user_id = ?
+	WHERE query_history.org_id = ? AND query_history.created_by = ?`, user.UserId, user.OrgId, user.UserId)
+
+		if len(query.DatasourceUIDs) > 0 {
+			filter.Write(" AND query_history.datasource_uid IN (?"+strings.Repeat(",?", len(query.DatasourceUIDs)-1)+")", stringsToParams(query.DatasourceUIDs)...)
+		}
+		if query.SearchString != "" {
+			like := "%" + escapeLike(query.SearchString) + "%"
+			likeExpr := dialect.LikeStr() + " ?" + likeEscapeClause(dialect)
+			filter.Write(" AND (query_history.queries "+likeExpr+" OR query_history.comment "+likeExpr+")", like, like)
+		}
+		if query.OnlyStarred {
+			filter.Write(" AND query_history_star.query_uid IS NOT NULL")
+		}
+		if query.From > 0 {
+			filter.Write(" AND query_history.created_at >= ?", query.From)
+		}
+		if query.To > 0 {
+			filter.Write(" AND query_history.created_at <= ?", query.To)
+		}
+
+		var count int64
+		if _, err := session.SQL("SELECT COUNT(*)"+filter.GetSQLString(), filter.GetParams()...).Get(&count); err != nil {
+			return err
+		}
+		result.TotalCount = int(count)
 
-	return dto, nil
+		order := "DESC"
+		if query.Sort == SortTimeAsc {
+			order = "ASC"
+		}
+
+		sb := sqlstore.SQLBuilder{}
+		sb.Write(`SELECT
+		query_history.uid,
+		query_history.datasource_uid,
+		query_history.created_by,
+		query_history.created_at,
+		query_history.comment,
+		query_history.queries,
+		CASE WHEN query_history_star.query_uid IS NULL THEN ` + dialect.BooleanStr(false) + ` ELSE ` + dialect.BooleanStr(true) + ` END AS starred`)
+		sb.Write(filter.GetSQLString(), filter.GetParams()...)
+		sb.Write(" ORDER BY query_history.created_at " + order + ", query_history.id " + order)
+		sb.Write(" " + dialect.LimitOffset(int64(query.Limit), int64((query.Page-1)*query.Limit)))
+
+		return session.SQL(sb.GetSQLString(), sb.GetParams()...).Find(&result.QueryHistory)
+	})
+
+	return result, err
 }
 
 func (s QueryHistoryService) deleteQuery(ctx context.Context, user *models.SignedInUser, UID string) (int64, error) {
 	var queryID int64
-	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		// Try to unstar the query first, it is fine if it was not starred
+		if _, err := session.Table("query_history_star").Where("user_id = ? AND query_uid = ?", user.UserId, UID).Delete(QueryHistoryStar{}); err != nil {
+			return err
+		}
+
 		id, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgId, user.UserId, UID).Delete(QueryHistory{})
 		if id == 0 {
 			return ErrQueryNotFound
@@ -54,3 +138,182 @@ func (s QueryHistoryService) deleteQuery(ctx context.Context, user *models.Signe
 
 	return queryID, err
 }
+
+func (s QueryHistoryService) patchQuery(ctx context.Context, user *models.SignedInUser, UID string, cmd PatchQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
+	var dto QueryHistoryDTO
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		queryHistory, err := getQuery(session, user, UID)
+		if err != nil {
+			return err
+		}
+
+		if cmd.Comment != nil {
+			queryHistory.Comment = *cmd.Comment
+			if _, err := session.ID(queryHistory.ID).Cols("comment").Update(&queryHistory); err != nil {
+				return err
+			}
+		}
+
+		starred, err := isStarred(session, user, UID)
+		if err != nil {
+			return err
+		}
+		if cmd.Starred != nil && *cmd.Starred != starred {
+			if *cmd.Starred {
+				err = insertStar(session, user, UID)
+			} else {
+				err = deleteStar(session, user, UID)
+			}
+			if err != nil {
+				return err
+			}
+			starred = *cmd.Starred
+		}
+
+		dto = toDTO(queryHistory, starred)
+		return nil
+	})
+
+	return dto, err
+}
+
+func (s QueryHistoryService) starQuery(ctx context.Context, user *models.SignedInUser, UID string) (QueryHistoryDTO, error) {
+	var dto QueryHistoryDTO
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		queryHistory, err := getQuery(session, user, UID)
+		if err != nil {
+			return err
+		}
+
+		starred, err := isStarred(session, user, UID)
+		if err != nil {
+			return err
+		}
+		if starred {
+			return ErrQueryAlreadyStarred
+		}
+
+		if err := insertStar(session, user, UID); err != nil {
+			return err
+		}
+
+		dto = toDTO(queryHistory, true)
+		return nil
+	})
+
+	return dto, err
+}
+
+func (s QueryHistoryService) unstarQuery(ctx context.Context, user *models.SignedInUser, UID string) (QueryHistoryDTO, error) {
+	var dto QueryHistoryDTO
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		queryHistory, err := getQuery(session, user, UID)
+		if err != nil {
+			return err
+		}
+
+		if err := deleteStar(session, user, UID); err != nil {
+			return err
+		}
+
+		dto = toDTO(queryHistory, false)
+		return nil
+	})
+
+	return dto, err
+}
+
+// deleteStaleQueries removes queries created before olderThan (unix timestamp in seconds)
+// unless they have been starred by anyone.
+func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan int64) (int, error) {
+	var rowsCount int64
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		sql := `DELETE FROM query_history
+	WHERE created_at < ?
+	AND uid NOT IN (SELECT query_uid FROM query_history_star)`
+
+		res, err := session.Exec(sql, olderThan)
+		if err != nil {
+			return err
+		}
+
+		rowsCount, err = res.RowsAffected()
+		return err
+	})
+
+	return int(rowsCount), err
+}
+
+func getQuery(session *sqlstore.DBSession, user *models.SignedInUser, UID string) (QueryHistory, error) {
+	var queryHistory QueryHistory
+	exists, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgId, user.UserId, UID).Get(&queryHistory)
+	if err != nil {
+		return QueryHistory{}, err
+	}
+	if !exists {
+		return QueryHistory{}, ErrQueryNotFound
+	}
+	return queryHistory, nil
+}
+
+func isStarred(session *sqlstore.DBSession, user *models.SignedInUser, UID string) (bool, error) {
+	return session.Table("query_history_star").Where("user_id = ? AND query_uid = ?", user.UserId, UID).Exist()
+}
+
+func insertStar(session *sqlstore.DBSession, user *models.SignedInUser, UID string) error {
+	_, err := session.Insert(&QueryHistoryStar{
+		QueryUID: UID,
+		UserID:   user.UserId,
+	})
+	return err
+}
+
+func deleteStar(session *sqlstore.DBSession, user *models.SignedInUser, UID string) error {
+	id, err := session.Table("query_history_star").Where("user_id = ? AND query_uid = ?", user.UserId, UID).Delete(QueryHistoryStar{})
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		return ErrStarredQueryNotFound
+	}
+	return nil
+}
+
+func toDTO(queryHistory QueryHistory, starred bool) QueryHistoryDTO {
+	return QueryHistoryDTO{
+		UID:           queryHistory.UID,
+		DatasourceUID: queryHistory.DatasourceUID,
+		CreatedBy:     queryHistory.CreatedBy,
+		CreatedAt:     queryHistory.CreatedAt,
+		Comment:       queryHistory.Comment,
+		Queries:       queryHistory.Queries,
+		Starred:       starred,
+	}
+}
+
+// likeEscapeChar is the character that escapes the wildcards of LIKE patterns.
+const likeEscapeChar = `\`
+
+var likeEscaper = strings.NewReplacer(likeEscapeChar, likeEscapeChar+likeEscapeChar, "%", likeEscapeChar+"%", "_", likeEscapeChar+"_")
+
+// escapeLike escapes the wildcards of a string, so that it only matches itself in LIKE patterns.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// likeEscapeClause returns the ESCAPE clause of the LIKE patterns escaped with escapeLike. The backslash
+// must itself be escaped in the string literals of MySQL, but not in those of PostgreSQL and SQLite.
+func likeEscapeClause(dialect migrator.Dialect) string {
+	if dialect.DriverName() == migrator.MySQL {
+		return ` ESCAPE '\\'`
+	}
+	return ` ESCAPE '\'`
+}
+
+func stringsToParams(values []string) []interface{} {
+	params := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		params = append(params, v)
+	}
+	return params
+}
@@ -7,7 +7,11 @@ import (
 )
 
 var (
-	ErrQueryNotFound = errors.New("query in query history not found")
+	ErrQueryNotFound         = errors.New("query in query history not found")
+	ErrStarredQueryNotFound  = errors.New("starred query not found")
+	ErrQueryAlreadyStarred   = errors.New("query was already starred")
+	ErrInvalidSearchSort     = errors.New("invalid sort, use time-desc or time-asc")
+	ErrInvalidSearchTimeSpan = errors.New("invalid time range, from must be before to")
 )
 
 type QueryHistory struct {
@@ -21,19 +25,49 @@ type QueryHistory struct {
 	Queries       *simplejson.Json
 }
 
+type QueryHistoryStar struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	QueryUID string `xorm:"query_uid"`
+	UserID   int64  `xorm:"user_id"`
+}
+
+const (
+	SortTimeDesc = "time-desc"
+	SortTimeAsc  = "time-asc"
+)
+
 type CreateQueryInQueryHistoryCommand struct {
 	DatasourceUID string           `json:"datasourceUid"`
 	Queries       *simplejson.Json `json:"queries"`
 }
 
+// SearchInQueryHistoryQuery filters the queries of the signed in user. From and To are
+// unix timestamps in seconds and are ignored when zero.
+type SearchInQueryHistoryQuery struct {
+	DatasourceUIDs []string `json:"datasourceUids"`
+	SearchString   string   `json:"searchString"`
+	OnlyStarred    bool     `json:"onlyStarred"`
+	Sort           string   `json:"sort"`
+	Page           int      `json:"page"`
+	Limit          int      `json:"limit"`
+	From           int64    `json:"from"`
+	To             int64    `json:"to"`
+}
+
+// PatchQueryInQueryHistoryCommand updates an existing query. Fields left empty are not changed.
+type PatchQueryInQueryHistoryCommand struct {
+	Comment *string `json:"comment"`
+	Starred *bool   `json:"starred"`
+}
+
 type QueryHistoryDTO struct {
-	UID           string           `json:"uid"`
-	DatasourceUID string           `json:"datasourceUid"`
-	CreatedBy     int64            `json:"createdBy"`
-	CreatedAt     int64            `json:"createdAt"`
-	Comment       string           `json:"comment"`
-	Queries       *simplejson.Json `json:"queries"`
-	Starred       bool             `json:"starred"`
+	UID           string           `json:"uid" xorm:"uid"`
+	DatasourceUID string           `json:"datasourceUid" xorm:"datasource_uid"`
+	CreatedBy     int64            `json:"createdBy" xorm:"created_by"`
+	CreatedAt     int64            `json:"createdAt" xorm:"created_at"`
+	Comment       string           `json:"comment" xorm:"comment"`
+	Queries       *simplejson.Json `json:"queries" xorm:"queries"`
+	Starred       bool             `json:"starred" xorm:"starred"`
 }
 
 // QueryHistoryResponse is a response struct for QueryHistoryDTO
@@ -41,6 +75,19 @@ type QueryHistoryResponse struct {
 	Result QueryHistoryDTO `json:"result"`
 }
 
+// QueryHistorySearchResult contains a page of queries and the total number of matches
+type QueryHistorySearchResult struct {
+	TotalCount   int               `json:"totalCount"`
+	QueryHistory []QueryHistoryDTO `json:"queryHistory"`
+	Page         int               `json:"page"`
+	PerPage      int               `json:"perPage"`
+}
+
+// QueryHistorySearchResponse is a response struct for QueryHistorySearchResult
+type QueryHistorySearchResponse struct {
+	Result QueryHistorySearchResult `json:"result"`
+}
+
 // DeleteQueryFromQueryHistoryResponse is the response struct for deleting a query from query history
 type DeleteQueryFromQueryHistoryResponse struct {
 	ID      int64  `json:"id"`
@@ -28,7 +28,12 @@ func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, routeRegister
 
 type Service interface {
 	CreateQueryInQueryHistory(ctx context.Context, user *models.SignedInUser, cmd CreateQueryInQueryHistoryCommand) (QueryHistoryDTO, error)
+	SearchInQueryHistory(ctx context.Context, user *models.SignedInUser, query SearchInQueryHistoryQuery) (QueryHistorySearchResult, error)
 	DeleteQueryFromQueryHistory(ctx context.Context, user *models.SignedInUser, UID string) (int64, error)
+	PatchQueryInQueryHistory(ctx context.Context, user *models.SignedInUser, UID string, cmd PatchQueryInQueryHistoryCommand) (QueryHistoryDTO, error)
+	StarQueryInQueryHistory(ctx context.Context, user *models.SignedInUser, UID string) (QueryHistoryDTO, error)
+	UnstarQueryInQueryHistory(ctx context.Context, user *models.SignedInUser, UID string) (QueryHistoryDTO, error)
+	DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error)
 }
 
 type QueryHistoryService struct {
@@ -45,3 +50,24 @@ func (s QueryHistoryService) CreateQueryInQueryHistory(ctx context.Context, user
 func (s QueryHistoryService) DeleteQueryFromQueryHistory(ctx context.Context, user *models.SignedInUser, UID string) (int64, error) {
 	return s.deleteQuery(ctx, user, UID)
 }
+
+func (s QueryHistoryService) SearchInQueryHistory(ctx context.Context, user *models.SignedInUser, query SearchInQueryHistoryQuery) (QueryHistorySearchResult, error) {
+	return s.searchQueries(ctx, user, query)
+}
+
+func (s QueryHistoryService) PatchQueryInQueryHistory(ctx context.Context, user *models.SignedInUser, UID string, cmd PatchQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
+	return s.patchQuery(ctx, user, UID, cmd)
+}
+
+func (s QueryHistoryService) StarQueryInQueryHistory(ctx context.Context, user *models.SignedInUser, UID string) (QueryHistoryDTO, error) {
+	return s.starQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) UnstarQueryInQueryHistory(ctx context.Context, user *models.SignedInUser, UID string) (QueryHistoryDTO, error) {
+	return s.unstarQuery(ctx, user, UID)
+}
+
+// DeleteStaleQueriesInQueryHistory deletes unstarred queries created before olderThan, a unix timestamp in seconds.
+func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error) {
+	return s.deleteStaleQueries(ctx, olderThan)
+}
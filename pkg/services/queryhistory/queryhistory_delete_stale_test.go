@@ -0,0 +1,37 @@
+package queryhistory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteStaleQueriesInQueryHistory(t *testing.T) {
+	testScenarioWithQueryInQueryHistory(t, "When we delete queries older than now, it should delete unstarred queries",
+		func(t *testing.T, sc scenarioContext) {
+			rowsDeleted, err := sc.service.DeleteStaleQueriesInQueryHistory(context.Background(), time.Now().Add(time.Second).Unix())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When we delete queries older than now, it should keep starred queries",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.starHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			rowsDeleted, err := sc.service.DeleteStaleQueriesInQueryHistory(context.Background(), time.Now().Add(time.Second).Unix())
+			require.NoError(t, err)
+			require.Equal(t, 0, rowsDeleted)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When we delete queries older than a day, it should keep recent queries",
+		func(t *testing.T, sc scenarioContext) {
+			rowsDeleted, err := sc.service.DeleteStaleQueriesInQueryHistory(context.Background(), time.Now().Add(-24*time.Hour).Unix())
+			require.NoError(t, err)
+			require.Equal(t, 0, rowsDeleted)
+		})
+}
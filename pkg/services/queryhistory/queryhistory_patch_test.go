@@ -0,0 +1,45 @@
+package queryhistory
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchQueryInQueryHistory(t *testing.T) {
+	testScenarioWithQueryInQueryHistory(t, "When users tries to patch query that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			comment := "test comment"
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "notexisting"})
+			sc.reqContext.Req.Body = mockRequestBody(PatchQueryInQueryHistoryCommand{Comment: &comment})
+			resp := sc.service.patchHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to update the comment of a query, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			comment := "test comment"
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(PatchQueryInQueryHistoryCommand{Comment: &comment})
+			resp := sc.service.patchHandler(sc.reqContext)
+			result := validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, comment, result.Result.Comment)
+			require.False(t, result.Result.Starred)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to star a query with patch, it should keep the comment",
+		func(t *testing.T, sc scenarioContext) {
+			comment := "test comment"
+			starred := true
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(PatchQueryInQueryHistoryCommand{Comment: &comment})
+			sc.service.patchHandler(sc.reqContext)
+
+			sc.reqContext.Req.Body = mockRequestBody(PatchQueryInQueryHistoryCommand{Starred: &starred})
+			resp := sc.service.patchHandler(sc.reqContext)
+			result := validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, comment, result.Result.Comment)
+			require.True(t, result.Result.Starred)
+		})
+}
@@ -0,0 +1,127 @@
+package queryhistory
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchInQueryHistory(t *testing.T) {
+	testScenarioWithQueryInQueryHistory(t, "When users tries to get query in empty query history, it should return empty result",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form = url.Values{"datasourceUid": []string{"test"}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			result := validateAndUnMarshalSearchResponse(t, resp)
+			require.Equal(t, 0, result.Result.TotalCount)
+			require.Len(t, result.Result.QueryHistory, 0)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to get query in query history, it should return the query",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form = url.Values{"datasourceUid": []string{testDsUID}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			result := validateAndUnMarshalSearchResponse(t, resp)
+			require.Equal(t, 1, result.Result.TotalCount)
+			require.Len(t, result.Result.QueryHistory, 1)
+			require.Equal(t, sc.initialResult.Result.UID, result.Result.QueryHistory[0].UID)
+			require.False(t, result.Result.QueryHistory[0].Starred)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to search by query text, it should only return matching queries",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form = url.Values{"searchString": []string{"does-not-match"}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			result := validateAndUnMarshalSearchResponse(t, resp)
+			require.Len(t, result.Result.QueryHistory, 0)
+
+			sc.reqContext.Req.Form = url.Values{"searchString": []string{"tes"}}
+			resp = sc.service.searchHandler(sc.reqContext)
+			result = validateAndUnMarshalSearchResponse(t, resp)
+			require.Len(t, result.Result.QueryHistory, 1)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to get only starred queries, it should not return unstarred queries",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form = url.Values{"onlyStarred": []string{"true"}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			result := validateAndUnMarshalSearchResponse(t, resp)
+			require.Len(t, result.Result.QueryHistory, 0)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to get queries outside of the time range, it should return empty result",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form = url.Values{"to": []string{"1"}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			result := validateAndUnMarshalSearchResponse(t, resp)
+			require.Len(t, result.Result.QueryHistory, 0)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users tries to get queries with sorting and pagination, it should return the right page",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form = url.Values{"sort": []string{SortTimeAsc}, "limit": []string{"2"}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			result := validateAndUnMarshalSearchResponse(t, resp)
+			require.Equal(t, 3, result.Result.TotalCount)
+			require.Len(t, result.Result.QueryHistory, 2)
+			require.Equal(t, "first", result.Result.QueryHistory[0].Queries.Get("expr").MustString())
+			require.Equal(t, "second", result.Result.QueryHistory[1].Queries.Get("expr").MustString())
+
+			sc.reqContext.Req.Form = url.Values{"sort": []string{SortTimeAsc}, "limit": []string{"2"}, "page": []string{"2"}}
+			resp = sc.service.searchHandler(sc.reqContext)
+			result = validateAndUnMarshalSearchResponse(t, resp)
+			require.Len(t, result.Result.QueryHistory, 1)
+			require.Equal(t, "third", result.Result.QueryHistory[0].Queries.Get("expr").MustString())
+
+			sc.reqContext.Req.Form = url.Values{"limit": []string{"1"}}
+			resp = sc.service.searchHandler(sc.reqContext)
+			result = validateAndUnMarshalSearchResponse(t, resp)
+			require.Equal(t, "third", result.Result.QueryHistory[0].Queries.Get("expr").MustString())
+		})
+
+	testScenario(t, "When users tries to search for wildcards of LIKE patterns, it should only return the queries containing them",
+		func(t *testing.T, sc scenarioContext) {
+			for _, expr := range []string{"100% done", "not_done", "done"} {
+				sc.reqContext.Req.Body = mockRequestBody(CreateQueryInQueryHistoryCommand{
+					DatasourceUID: testDsUID,
+					Queries:       simplejson.NewFromAny(map[string]interface{}{"expr": expr}),
+				})
+				validateAndUnMarshalResponse(t, sc.service.createHandler(sc.reqContext))
+			}
+
+			for search, expected := range map[string]string{"%": "100% done", "0% d": "100% done", "_": "not_done", "t_d": "not_done"} {
+				sc.reqContext.Req.Form = url.Values{"searchString": []string{search}}
+				resp := sc.service.searchHandler(sc.reqContext)
+				result := validateAndUnMarshalSearchResponse(t, resp)
+				require.Len(t, result.Result.QueryHistory, 1, search)
+				require.Equal(t, expected, result.Result.QueryHistory[0].Queries.Get("expr").MustString(), search)
+			}
+
+			sc.reqContext.Req.Form = url.Values{"searchString": []string{`\`}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			result := validateAndUnMarshalSearchResponse(t, resp)
+			require.Len(t, result.Result.QueryHistory, 0)
+		})
+
+	testScenario(t, "When users tries to search with an invalid sort, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form = url.Values{"sort": []string{"title"}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
+
+func validateAndUnMarshalSearchResponse(t *testing.T, resp response.Response) QueryHistorySearchResponse {
+	t.Helper()
+
+	require.Equal(t, 200, resp.Status())
+
+	var result = QueryHistorySearchResponse{}
+	err := json.Unmarshal(resp.Body(), &result)
+	require.NoError(t, err)
+
+	return result
+}
@@ -0,0 +1,51 @@
+package queryhistory
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStarQueryInQueryHistory(t *testing.T) {
+	testScenarioWithQueryInQueryHistory(t, "When users tries to star query that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "notexisting"})
+			resp := sc.service.starHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to star query that exists, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.starHandler(sc.reqContext)
+			result := validateAndUnMarshalResponse(t, resp)
+			require.True(t, result.Result.Starred)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to star query that is already starred, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.service.starHandler(sc.reqContext)
+			resp := sc.service.starHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
+
+func TestUnstarQueryInQueryHistory(t *testing.T) {
+	testScenarioWithQueryInQueryHistory(t, "When users tries to unstar query that is not starred, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.unstarHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to unstar starred query, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.service.starHandler(sc.reqContext)
+			resp := sc.service.unstarHandler(sc.reqContext)
+			result := validateAndUnMarshalResponse(t, resp)
+			require.False(t, result.Result.Starred)
+		})
+}
@@ -21,6 +21,7 @@ import (
 var (
 	testOrgID  = int64(1)
 	testUserID = int64(1)
+	testDsUID  = "NCzh67i"
 )
 
 type scenarioContext struct {
@@ -82,7 +83,7 @@ func testScenarioWithQueryInQueryHistory(t *testing.T, desc string, fn func(t *t
 
 	testScenario(t, desc, func(t *testing.T, sc scenarioContext) {
 		command := CreateQueryInQueryHistoryCommand{
-			DatasourceUID: "NCzh67i",
+			DatasourceUID: testDsUID,
 			Queries: simplejson.NewFromAny(map[string]interface{}{
 				"expr": "test",
 			}),
@@ -94,6 +95,25 @@ func testScenarioWithQueryInQueryHistory(t *testing.T, desc string, fn func(t *t
 	})
 }
 
+func testScenarioWithMultipleQueriesInQueryHistory(t *testing.T, desc string, fn func(t *testing.T, sc scenarioContext)) {
+	t.Helper()
+
+	testScenario(t, desc, func(t *testing.T, sc scenarioContext) {
+		for _, expr := range []string{"first", "second", "third"} {
+			command := CreateQueryInQueryHistoryCommand{
+				DatasourceUID: testDsUID,
+				Queries: simplejson.NewFromAny(map[string]interface{}{
+					"expr": expr,
+				}),
+			}
+			sc.reqContext.Req.Body = mockRequestBody(command)
+			resp := sc.service.createHandler(sc.reqContext)
+			sc.initialResult = validateAndUnMarshalResponse(t, resp)
+		}
+		fn(t, sc)
+	})
+}
+
 func mockRequestBody(v interface{}) io.ReadCloser {
 	b, _ := json.Marshal(v)
 	return io.NopCloser(bytes.NewReader(b))
@@ -35,7 +35,8 @@ func (ss *SQLStore) SaveThumbnail(ctx context.Context, cmd *models.SaveDashboard
 			existing.Updated = time.Now()
 			existing.DashboardVersion = cmd.DashboardVersion
 			existing.State = models.ThumbnailStateDefault
-			_, err = sess.ID(existing.Id).Update(existing)
+			existing.StorageKey = cmd.StorageKey
+			_, err = sess.ID(existing.Id).MustCols("image", "storage_key").Update(existing)
 			cmd.Result = existing
 			return err
 		}
@@ -57,6 +58,7 @@ func (ss *SQLStore) SaveThumbnail(ctx context.Context, cmd *models.SaveDashboard
 		thumb.DashboardVersion = cmd.DashboardVersion
 		thumb.State = models.ThumbnailStateDefault
 		thumb.PanelId = cmd.PanelID
+		thumb.StorageKey = cmd.StorageKey
 		_, err = sess.Insert(thumb)
 		cmd.Result = thumb
 		return err
@@ -117,6 +119,44 @@ func (ss *SQLStore) FindDashboardsWithStaleThumbnails(ctx context.Context, cmd *
 	return cmd.Result, err
 }
 
+// FindThumbnailsInDatabase returns thumbnails whose image has not been moved to an external storage yet.
+func (ss *SQLStore) FindThumbnailsInDatabase(ctx context.Context, query *models.FindThumbnailsInDatabaseQuery) ([]*models.DashboardThumbnailInDatabase, error) {
+	err := ss.WithDbSession(ctx, func(sess *DBSession) error {
+		sess.Table("dashboard_thumbnail")
+		sess.Join("INNER", "dashboard", "dashboard.id = dashboard_thumbnail.dashboard_id")
+		sess.Where("dashboard_thumbnail.storage_key IS NULL OR dashboard_thumbnail.storage_key = ''")
+		sess.Select("dashboard_thumbnail.id, " +
+			"dashboard.uid AS dashboard_uid, " +
+			"dashboard.org_id, " +
+			"dashboard_thumbnail.panel_id, " +
+			"dashboard_thumbnail.kind, " +
+			"dashboard_thumbnail.theme, " +
+			"dashboard_thumbnail.mime_type, " +
+			"dashboard_thumbnail.image")
+		sess.OrderBy("dashboard_thumbnail.id")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+
+		var thumbnails = make([]*models.DashboardThumbnailInDatabase, 0)
+		if err := sess.Find(&thumbnails); err != nil {
+			return err
+		}
+		query.Result = thumbnails
+		return nil
+	})
+
+	return query.Result, err
+}
+
+// MoveThumbnailToStorage records the storage key of a thumbnail and removes its image from the database.
+func (ss *SQLStore) MoveThumbnailToStorage(ctx context.Context, cmd *models.MoveThumbnailToStorageCommand) error {
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		_, err := sess.Exec("UPDATE dashboard_thumbnail SET storage_key = ?, image = ? WHERE id = ?", cmd.StorageKey, []byte{}, cmd.Id)
+		return err
+	})
+}
+
 func findThumbnailByMeta(sess *DBSession, meta models.DashboardThumbnailMeta) (*models.DashboardThumbnail, error) {
 	result := &models.DashboardThumbnail{}
 
@@ -132,7 +172,8 @@ func findThumbnailByMeta(sess *DBSession, meta models.DashboardThumbnailMeta) (*
 		"dashboard_thumbnail.kind",
 		"dashboard_thumbnail.mime_type",
 		"dashboard_thumbnail.theme",
-		"dashboard_thumbnail.updated")
+		"dashboard_thumbnail.updated",
+		"dashboard_thumbnail.storage_key")
 	exists, err := sess.Get(result)
 
 	if !exists {
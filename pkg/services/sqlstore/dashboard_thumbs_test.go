@@ -185,6 +185,27 @@ func TestSqlStorage(t *testing.T) {
 		require.Len(t, res, 1)
 		require.Equal(t, dash.Id, res[0].Id)
 	})
+
+	t.Run("Should find thumbnails stored in the database until they are moved to a storage", func(t *testing.T) {
+		setup()
+		dash := insertTestDashboard(t, sqlStore, "test dash 23", 1, savedFolder.Id, false, "prod", "webapp")
+		upsertTestDashboardThumbnail(t, sqlStore, dash.Uid, dash.OrgId, dash.Version)
+
+		query := models.FindThumbnailsInDatabaseQuery{}
+		res, err := sqlStore.FindThumbnailsInDatabase(context.Background(), &query)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, dash.Uid, res[0].DashboardUID)
+		require.Equal(t, dash.OrgId, res[0].OrgId)
+
+		err = sqlStore.MoveThumbnailToStorage(context.Background(), &models.MoveThumbnailToStorageCommand{Id: res[0].Id, StorageKey: "key"})
+		require.NoError(t, err)
+		require.Equal(t, "key", getThumbnail(t, sqlStore, dash.Uid, dash.OrgId).StorageKey)
+
+		res, err = sqlStore.FindThumbnailsInDatabase(context.Background(), &query)
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+	})
 }
 
 func getThumbnail(t *testing.T, sqlStore *SQLStore, dashboardUID string, orgId int64) *models.DashboardThumbnail {
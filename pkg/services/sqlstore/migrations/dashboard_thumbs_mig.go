@@ -24,4 +24,8 @@ func addDashboardThumbsMigrations(mg *migrator.Migrator) {
 
 	mg.AddMigration("create dashboard_thumbnail table", migrator.NewAddTableMigration(dashThumbs))
 	mg.AddMigration("add unique indexes for dashboard_thumbnail", migrator.NewAddIndexMigration(dashThumbs, dashThumbs.Indices[0]))
+
+	mg.AddMigration("add storage_key column to dashboard_thumbnail table", migrator.NewAddColumnMigration(dashThumbs, &migrator.Column{
+		Name: "storage_key", Type: migrator.DB_NVarchar, Length: 255, Nullable: true, // key of the image in the external storage, image is empty when set
+	}))
 }
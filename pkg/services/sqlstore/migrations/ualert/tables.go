@@ -25,6 +25,12 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 
 	// Create provisioning data table
 	AddProvisioningMigrations(mg)
+
+	// Create alert state history table
+	AddAlertStateHistoryMigrations(mg)
+
+	// Create alert image table
+	AddAlertImageMigrations(mg)
 }
 
 // AddAlertDefinitionMigrations should not be modified.
@@ -234,6 +240,18 @@ func AddAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64)
 			Cols: []string{"org_id", "dashboard_uid", "panel_id"},
 		},
 	))
+
+	// add record column for recording rules
+	mg.AddMigration("add record column to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "record", Type: migrator.DB_Text, Nullable: true}))
+
+	// add is_paused column for paused rules
+	mg.AddMigration("add is_paused column to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "is_paused", Type: migrator.DB_Bool, Nullable: false, Default: "0"}))
+
+	// add keep_firing_for column
+	mg.AddMigration("add keep_firing_for column to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0"}))
+
+	// add rule_group_index column for the evaluation order of the rules of a group
+	mg.AddMigration("add rule_group_index column to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "rule_group_index", Type: migrator.DB_Int, Nullable: false, Default: "0"}))
 }
 
 func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
@@ -277,6 +295,18 @@ func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
 
 	// add labels column
 	mg.AddMigration("add column labels to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "labels", Type: migrator.DB_Text, Nullable: true}))
+
+	// add record column for recording rules
+	mg.AddMigration("add record column to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "record", Type: migrator.DB_Text, Nullable: true}))
+
+	// add is_paused column for paused rules
+	mg.AddMigration("add is_paused column to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "is_paused", Type: migrator.DB_Bool, Nullable: false, Default: "0"}))
+
+	// add keep_firing_for column
+	mg.AddMigration("add keep_firing_for column to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0"}))
+
+	// add rule_group_index column for the evaluation order of the rules of a group
+	mg.AddMigration("add rule_group_index column to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "rule_group_index", Type: migrator.DB_Int, Nullable: false, Default: "0"}))
 }
 
 func AddAlertmanagerConfigMigrations(mg *migrator.Migrator) {
@@ -349,3 +379,49 @@ func AddProvisioningMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("create provenance_type table", migrator.NewAddTableMigration(provisioningTable))
 	mg.AddMigration("add index to uniquify (record_key, record_type, org_id) columns", migrator.NewAddIndexMigration(provisioningTable, provisioningTable.Indices[0]))
 }
+
+func AddAlertStateHistoryMigrations(mg *migrator.Migrator) {
+	stateHistory := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "eval_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "eval_error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"epoch"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(stateHistory))
+	mg.AddMigration("add index in alert_state_history table on org_id, rule_uid and epoch columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[0]))
+	mg.AddMigration("add index in alert_state_history table on epoch column", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[1]))
+}
+
+func AddAlertImageMigrations(mg *migrator.Migrator) {
+	imageTable := migrator.Table{
+		Name: "alert_image",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "token", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "path", Type: migrator.DB_NVarchar, Length: 2048, Nullable: false},
+			{Name: "url", Type: migrator.DB_NVarchar, Length: 2048, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "expires_at", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"token"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_image table", migrator.NewAddTableMigration(imageTable))
+	mg.AddMigration("add unique index on token to alert_image table", migrator.NewAddIndexMigration(imageTable, imageTable.Indices[0]))
+}
@@ -493,7 +493,7 @@ func (m *migration) validateAlertmanagerConfig(orgID int64, config *PostableUser
 
 			switch gr.Type {
 			case "email":
-				_, err = channels.NewEmailNotifier(cfg, nil, nil) // Email notifier already has a default template.
+				_, err = channels.NewEmailNotifier(cfg, nil, nil, nil) // Email notifier already has a default template.
 			case "pagerduty":
 				_, err = channels.NewPagerdutyNotifier(cfg, nil, nil, decryptFunc)
 			case "pushover":
@@ -501,7 +501,7 @@ func (m *migration) validateAlertmanagerConfig(orgID int64, config *PostableUser
 			case "slack":
 				_, err = channels.NewSlackNotifier(cfg, nil, decryptFunc)
 			case "telegram":
-				_, err = channels.NewTelegramNotifier(cfg, nil, nil, decryptFunc)
+				_, err = channels.NewTelegramNotifier(cfg, nil, nil, nil, decryptFunc)
 			case "victorops":
 				_, err = channels.NewVictoropsNotifier(cfg, nil, nil)
 			case "teams":
@@ -515,7 +515,7 @@ func (m *migration) validateAlertmanagerConfig(orgID int64, config *PostableUser
 			case "sensugo":
 				_, err = channels.NewSensuGoNotifier(cfg, nil, nil, decryptFunc)
 			case "discord":
-				_, err = channels.NewDiscordNotifier(cfg, nil, nil)
+				_, err = channels.NewDiscordNotifier(cfg, nil, nil, nil)
 			case "googlechat":
 				_, err = channels.NewGoogleChatNotifier(cfg, nil, nil)
 			case "LINE":
@@ -0,0 +1,56 @@
+package thumbs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const migrationBatchSize = 100
+
+// MigrateThumbnailsToStorage moves the thumbnail images still kept in the dashboard_thumbnail table
+// to the storage configured in [dashboard_previews]. It returns the number of moved thumbnails.
+func MigrateThumbnailsToStorage(ctx context.Context, cfg *setting.Cfg, store *sqlstore.SQLStore) (int, error) {
+	storage, err := newThumbnailStorage(cfg)
+	if err != nil {
+		return 0, err
+	}
+	if storage == nil {
+		return 0, errors.New("the thumbnail storage is set to database, set [dashboard_previews] storage to filesystem or s3 first")
+	}
+
+	return migrateThumbnailsToStorage(ctx, store, storage)
+}
+
+func migrateThumbnailsToStorage(ctx context.Context, store *sqlstore.SQLStore, storage thumbnailStorage) (int, error) {
+	moved := 0
+	for {
+		thumbnails, err := store.FindThumbnailsInDatabase(ctx, &models.FindThumbnailsInDatabaseQuery{Limit: migrationBatchSize})
+		if err != nil {
+			return moved, err
+		}
+		if len(thumbnails) == 0 {
+			return moved, nil
+		}
+
+		for _, thumbnail := range thumbnails {
+			key := thumbnailStorageKey(models.DashboardThumbnailMeta{
+				DashboardUID: thumbnail.DashboardUID,
+				OrgId:        thumbnail.OrgId,
+				PanelID:      thumbnail.PanelId,
+				Kind:         thumbnail.Kind,
+				Theme:        thumbnail.Theme,
+			})
+			if err := storage.put(ctx, key, thumbnail.Image, thumbnail.MimeType); err != nil {
+				return moved, err
+			}
+			if err := store.MoveThumbnailToStorage(ctx, &models.MoveThumbnailToStorageCommand{Id: thumbnail.Id, StorageKey: key}); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+}
@@ -3,6 +3,7 @@ package thumbs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,17 +13,20 @@ import (
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
-func newThumbnailRepo(store *sqlstore.SQLStore) thumbnailRepo {
+func newThumbnailRepo(store *sqlstore.SQLStore, storage thumbnailStorage) thumbnailRepo {
 	repo := &sqlThumbnailRepository{
-		store: store,
-		log:   log.New("thumbnails_repo"),
+		store:   store,
+		storage: storage,
+		log:     log.New("thumbnails_repo"),
 	}
 	return repo
 }
 
 type sqlThumbnailRepository struct {
 	store *sqlstore.SQLStore
-	log   log.Logger
+	// storage is where the images are kept, they are stored in the database when it is nil
+	storage thumbnailStorage
+	log     log.Logger
 }
 
 func (r *sqlThumbnailRepository) saveFromFile(ctx context.Context, filePath string, meta models.DashboardThumbnailMeta, dashboardVersion int) (int64, error) {
@@ -61,6 +65,16 @@ func (r *sqlThumbnailRepository) saveFromBytes(ctx context.Context, content []by
 		DashboardVersion:       dashboardVersion,
 	}
 
+	if r.storage != nil {
+		key := thumbnailStorageKey(meta)
+		if err := r.storage.put(ctx, key, content, mimeType); err != nil {
+			r.log.Error("error saving to the thumbnail storage", "dashboardUID", meta.DashboardUID, "err", err)
+			return 0, err
+		}
+		cmd.Image = []byte{}
+		cmd.StorageKey = key
+	}
+
 	_, err := r.store.SaveThumbnail(ctx, cmd)
 	if err != nil {
 		r.log.Error("error saving to the db", "dashboardUID", meta.DashboardUID, "err", err)
@@ -81,7 +95,19 @@ func (r *sqlThumbnailRepository) getThumbnail(ctx context.Context, meta models.D
 	query := &models.GetDashboardThumbnailCommand{
 		DashboardThumbnailMeta: meta,
 	}
-	return r.store.GetThumbnail(ctx, query)
+	thumbnail, err := r.store.GetThumbnail(ctx, query)
+	if err != nil || thumbnail.StorageKey == "" {
+		return thumbnail, err
+	}
+
+	if r.storage == nil {
+		return nil, fmt.Errorf("thumbnail %s is kept in an external storage, but the storage is set to database", thumbnail.StorageKey)
+	}
+	thumbnail.Image, err = r.storage.get(ctx, thumbnail.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	return thumbnail, nil
 }
 
 func (r *sqlThumbnailRepository) findDashboardsWithStaleThumbnails(ctx context.Context, theme models.Theme, kind models.ThumbnailKind) ([]*models.DashboardWithStaleThumbnail, error) {
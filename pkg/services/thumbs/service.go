@@ -58,12 +58,16 @@ type crawlerScheduleOptions struct {
 	themes           []models.Theme
 }
 
-func ProvideService(cfg *setting.Cfg, features featuremgmt.FeatureToggles, lockService *serverlock.ServerLockService, renderService rendering.Service, gl *live.GrafanaLive, store *sqlstore.SQLStore) Service {
+func ProvideService(cfg *setting.Cfg, features featuremgmt.FeatureToggles, lockService *serverlock.ServerLockService, renderService rendering.Service, gl *live.GrafanaLive, store *sqlstore.SQLStore) (Service, error) {
 	if !features.IsEnabled(featuremgmt.FlagDashboardPreviews) {
-		return &dummyService{}
+		return &dummyService{}, nil
 	}
 
-	thumbnailRepo := newThumbnailRepo(store)
+	storage, err := newThumbnailStorage(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the thumbnail storage: %w", err)
+	}
+	thumbnailRepo := newThumbnailRepo(store, storage)
 
 	authOpts := rendering.AuthOpts{
 		OrgID:   0,
@@ -87,7 +91,7 @@ func ProvideService(cfg *setting.Cfg, features featuremgmt.FeatureToggles, lockS
 			themes:           []models.Theme{models.ThemeDark, models.ThemeLight},
 			auth:             authOpts,
 		},
-	}
+	}, nil
 }
 
 func (hs *thumbService) Enabled() bool {
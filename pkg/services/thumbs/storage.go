@@ -0,0 +1,151 @@
+package thumbs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// thumbnailStorage keeps the thumbnail images outside of the database. Only the
+// metadata and the storage key of the image are kept in the dashboard_thumbnail table.
+type thumbnailStorage interface {
+	put(ctx context.Context, key string, content []byte, mimeType string) error
+	get(ctx context.Context, key string) ([]byte, error)
+}
+
+// newThumbnailStorage returns the storage configured in [dashboard_previews], or nil
+// if the images should be stored in the database.
+func newThumbnailStorage(cfg *setting.Cfg) (thumbnailStorage, error) {
+	switch cfg.DashboardPreviews.StorageProvider {
+	case "", setting.DashboardPreviewsStorageDatabase:
+		return nil, nil
+	case setting.DashboardPreviewsStorageFilesystem:
+		return newFSThumbnailStorage(cfg.DashboardPreviews.FilesystemPath), nil
+	case setting.DashboardPreviewsStorageS3:
+		storage, err := newS3ThumbnailStorage(cfg.DashboardPreviews.S3)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	default:
+		return nil, fmt.Errorf("unknown thumbnail storage %q", cfg.DashboardPreviews.StorageProvider)
+	}
+}
+
+// thumbnailStorageKey returns the key of the image of a thumbnail. It only depends on the natural key
+// of the thumbnail, so a new image replaces the previous one.
+func thumbnailStorageKey(meta models.DashboardThumbnailMeta) string {
+	return fmt.Sprintf("%d/%s/%d/%s-%s", meta.OrgId, meta.DashboardUID, meta.PanelID, meta.Kind, meta.Theme)
+}
+
+type fsThumbnailStorage struct {
+	root string
+}
+
+func newFSThumbnailStorage(root string) *fsThumbnailStorage {
+	return &fsThumbnailStorage{root: filepath.Clean(root)}
+}
+
+func (s *fsThumbnailStorage) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid thumbnail key %q", key)
+	}
+	return path, nil
+}
+
+func (s *fsThumbnailStorage) put(_ context.Context, key string, content []byte, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0640)
+}
+
+func (s *fsThumbnailStorage) get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	// path is checked to be inside of the thumbnails directory
+	// nolint:gosec
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, models.ErrDashboardThumbnailNotFound
+	}
+	return content, err
+}
+
+type s3ThumbnailStorage struct {
+	client *s3.S3
+	bucket string
+	path   string
+}
+
+func newS3ThumbnailStorage(cfg setting.DashboardPreviewsS3Settings) (*s3ThumbnailStorage, error) {
+	awsCfg, err := imguploader.NewS3Config(cfg.Endpoint, cfg.Region, cfg.AccessKey, cfg.SecretKey, cfg.PathStyleAccess)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	path := cfg.Path
+	if path != "" && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	return &s3ThumbnailStorage{
+		client: s3.New(sess),
+		bucket: cfg.Bucket,
+		path:   path,
+	}, nil
+}
+
+func (s *s3ThumbnailStorage) put(ctx context.Context, key string, content []byte, mimeType string) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.path + key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(mimeType),
+	})
+	return err
+}
+
+func (s *s3ThumbnailStorage) get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.path + key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, models.ErrDashboardThumbnailNotFound
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = out.Body.Close()
+	}()
+
+	return io.ReadAll(out.Body)
+}
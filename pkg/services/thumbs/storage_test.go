@@ -0,0 +1,123 @@
+package thumbs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestFSThumbnailStorage(t *testing.T) {
+	storage := newFSThumbnailStorage(t.TempDir())
+	ctx := context.Background()
+
+	t.Run("stores and returns images", func(t *testing.T) {
+		require.NoError(t, storage.put(ctx, "1/abc/0/thumb-dark", []byte("image"), "image/png"))
+
+		content, err := storage.get(ctx, "1/abc/0/thumb-dark")
+		require.NoError(t, err)
+		require.Equal(t, []byte("image"), content)
+		require.FileExists(t, filepath.Join(storage.root, "1", "abc", "0", "thumb-dark"))
+	})
+
+	t.Run("returns not found for missing images", func(t *testing.T) {
+		_, err := storage.get(ctx, "1/missing/0/thumb-dark")
+		require.ErrorIs(t, err, models.ErrDashboardThumbnailNotFound)
+	})
+
+	t.Run("rejects keys outside of the storage directory", func(t *testing.T) {
+		err := storage.put(ctx, "../outside", []byte("image"), "image/png")
+		require.Error(t, err)
+		_, err = os.Stat(filepath.Join(filepath.Dir(storage.root), "outside"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestNewThumbnailStorage(t *testing.T) {
+	cfg := setting.NewCfg()
+	storage, err := newThumbnailStorage(cfg)
+	require.NoError(t, err)
+	require.Nil(t, storage)
+
+	cfg.DashboardPreviews.StorageProvider = setting.DashboardPreviewsStorageFilesystem
+	cfg.DashboardPreviews.FilesystemPath = t.TempDir()
+	storage, err = newThumbnailStorage(cfg)
+	require.NoError(t, err)
+	require.IsType(t, &fsThumbnailStorage{}, storage)
+
+	cfg.DashboardPreviews.StorageProvider = "ftp"
+	_, err = newThumbnailStorage(cfg)
+	require.Error(t, err)
+}
+
+func TestThumbnailRepoWithStorage(t *testing.T) {
+	setup := func(t *testing.T) (*sqlstore.SQLStore, *models.Dashboard, models.DashboardThumbnailMeta) {
+		t.Helper()
+		store := sqlstore.InitTestDB(t)
+		dash, err := store.SaveDashboard(models.SaveDashboardCommand{
+			OrgId:     1,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "test dash"}),
+		})
+		require.NoError(t, err)
+
+		meta := models.DashboardThumbnailMeta{
+			DashboardUID: dash.Uid,
+			OrgId:        dash.OrgId,
+			Kind:         models.ThumbnailKindDefault,
+			Theme:        models.ThemeDark,
+		}
+		return store, dash, meta
+	}
+
+	t.Run("keeps only the metadata in the database", func(t *testing.T) {
+		store, dash, meta := setup(t)
+		storage := newFSThumbnailStorage(t.TempDir())
+		repo := newThumbnailRepo(store, storage)
+		ctx := context.Background()
+
+		_, err := repo.saveFromBytes(ctx, []byte("image"), "image/png", meta, dash.Version)
+		require.NoError(t, err)
+
+		stored, err := store.GetThumbnail(ctx, &models.GetDashboardThumbnailCommand{DashboardThumbnailMeta: meta})
+		require.NoError(t, err)
+		require.Empty(t, stored.Image)
+		require.Equal(t, thumbnailStorageKey(meta), stored.StorageKey)
+
+		thumbnail, err := repo.getThumbnail(ctx, meta)
+		require.NoError(t, err)
+		require.Equal(t, []byte("image"), thumbnail.Image)
+		require.Equal(t, "image/png", thumbnail.MimeType)
+	})
+
+	t.Run("migrates images from the database to the storage", func(t *testing.T) {
+		store, dash, meta := setup(t)
+		ctx := context.Background()
+
+		_, err := newThumbnailRepo(store, nil).saveFromBytes(ctx, []byte("image"), "image/png", meta, dash.Version)
+		require.NoError(t, err)
+
+		storage := newFSThumbnailStorage(t.TempDir())
+		moved, err := migrateThumbnailsToStorage(ctx, store, storage)
+		require.NoError(t, err)
+		require.Equal(t, 1, moved)
+
+		stored, err := store.GetThumbnail(ctx, &models.GetDashboardThumbnailCommand{DashboardThumbnailMeta: meta})
+		require.NoError(t, err)
+		require.Empty(t, stored.Image)
+
+		thumbnail, err := newThumbnailRepo(store, storage).getThumbnail(ctx, meta)
+		require.NoError(t, err)
+		require.Equal(t, []byte("image"), thumbnail.Image)
+
+		moved, err = migrateThumbnailsToStorage(ctx, store, storage)
+		require.NoError(t, err)
+		require.Equal(t, 0, moved)
+	})
+}
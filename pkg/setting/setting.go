@@ -231,6 +231,9 @@ type Cfg struct {
 	// SMTP email settings
 	Smtp SmtpSettings
 
+	// Dashboard previews
+	DashboardPreviews DashboardPreviewsSettings
+
 	// Rendering
 	ImagesDir                      string
 	CSVsDir                        string
@@ -427,6 +430,9 @@ type Cfg struct {
 
 	// Query history
 	QueryHistoryEnabled bool
+	// QueryHistoryRetention is how long unstarred queries are kept in query history.
+	// A zero value keeps them forever.
+	QueryHistoryRetention time.Duration
 }
 
 type CommandLineArgs struct {
@@ -942,6 +948,11 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(false)
+	queryHistoryRetention, err := gtime.ParseDuration(valueAsString(queryHistory, "retention", "14d"))
+	if err != nil {
+		return fmt.Errorf("invalid value for [query_history] retention: %w", err)
+	}
+	cfg.QueryHistoryRetention = queryHistoryRetention
 
 	panelsSection := iniFile.Section("panels")
 	cfg.DisableSanitizeHtml = panelsSection.Key("disable_sanitize_html").MustBool(false)
@@ -977,6 +988,10 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.readDataSourcesSettings()
 
+	if err := cfg.readDashboardPreviewsSettings(); err != nil {
+		return err
+	}
+
 	if VerifyEmailEnabled && !cfg.Smtp.Enabled {
 		cfg.Logger.Warn("require_email_validation is enabled but smtp is disabled")
 	}
@@ -0,0 +1,60 @@
+package setting
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	DashboardPreviewsStorageDatabase   = "database"
+	DashboardPreviewsStorageFilesystem = "filesystem"
+	DashboardPreviewsStorageS3         = "s3"
+)
+
+type DashboardPreviewsSettings struct {
+	// StorageProvider is where the thumbnail images are kept. Only the metadata
+	// is stored in the database unless the provider is "database".
+	StorageProvider string
+	FilesystemPath  string
+	S3              DashboardPreviewsS3Settings
+}
+
+type DashboardPreviewsS3Settings struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Path            string
+	AccessKey       string
+	SecretKey       string
+	PathStyleAccess bool
+}
+
+func (cfg *Cfg) readDashboardPreviewsSettings() error {
+	sec := cfg.Raw.Section("dashboard_previews")
+	cfg.DashboardPreviews.StorageProvider = valueAsString(sec, "storage", DashboardPreviewsStorageDatabase)
+
+	switch cfg.DashboardPreviews.StorageProvider {
+	case DashboardPreviewsStorageDatabase:
+	case DashboardPreviewsStorageFilesystem:
+		fsSec := cfg.Raw.Section("dashboard_previews.storage.filesystem")
+		cfg.DashboardPreviews.FilesystemPath = makeAbsolute(valueAsString(fsSec, "path", filepath.Join(cfg.DataPath, "thumbnails")), HomePath)
+	case DashboardPreviewsStorageS3:
+		s3Sec := cfg.Raw.Section("dashboard_previews.storage.s3")
+		cfg.DashboardPreviews.S3 = DashboardPreviewsS3Settings{
+			Endpoint:        s3Sec.Key("endpoint").MustString(""),
+			Region:          s3Sec.Key("region").MustString(""),
+			Bucket:          s3Sec.Key("bucket").MustString(""),
+			Path:            s3Sec.Key("path").MustString(""),
+			AccessKey:       s3Sec.Key("access_key").MustString(""),
+			SecretKey:       s3Sec.Key("secret_key").MustString(""),
+			PathStyleAccess: s3Sec.Key("path_style_access").MustBool(false),
+		}
+		if cfg.DashboardPreviews.S3.Bucket == "" {
+			return fmt.Errorf("[dashboard_previews.storage.s3] bucket is required")
+		}
+	default:
+		return fmt.Errorf("unknown [dashboard_previews] storage %q, use database, filesystem or s3", cfg.DashboardPreviews.StorageProvider)
+	}
+
+	return nil
+}
@@ -48,6 +48,10 @@ const (
 	schedulereDefaultExecuteAlerts          = true
 	schedulerDefaultMaxAttempts             = 3
 	schedulerDefaultLegacyMinInterval       = 1
+	recordingRulesDefaultTimeout            = 10 * time.Second
+	stateHistoryDefaultRetention            = 30 * 24 * time.Hour
+	screenshotsDefaultCaptureTimeout        = 10 * time.Second
+	screenshotsDefaultMaxConcurrent         = 5
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -65,6 +69,7 @@ type UnifiedAlertingSettings struct {
 	HAPeerTimeout                  time.Duration
 	HAGossipInterval               time.Duration
 	HAPushPullInterval             time.Duration
+	HAEvaluationSharding           bool
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
@@ -77,6 +82,33 @@ type UnifiedAlertingSettings struct {
 	BaseInterval time.Duration
 	// DefaultAlertForDuration default time for how long an alert rule should be evaluated before change state.
 	DefaultAlertForDuration time.Duration
+	RecordingRules          RecordingRuleSettings
+	StateHistory            StateHistorySettings
+	Screenshots             ScreenshotSettings
+}
+
+// RecordingRuleSettings configures where recording rules write their results.
+type RecordingRuleSettings struct {
+	Enabled           bool
+	URL               string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	Timeout           time.Duration
+}
+
+// StateHistorySettings configures the history of the state changes of alert instances.
+type StateHistorySettings struct {
+	Enabled bool
+	// Retention is how long state changes are kept. Zero keeps them forever.
+	Retention time.Duration
+}
+
+// ScreenshotSettings configures the screenshots of panels attached to notifications.
+type ScreenshotSettings struct {
+	Capture                    bool
+	CaptureTimeout             time.Duration
+	MaxConcurrentScreenshots   int64
+	UploadExternalImageStorage bool
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -179,6 +211,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 			uaCfg.HAPeers = append(uaCfg.HAPeers, peer)
 		}
 	}
+	uaCfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(false)
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -244,10 +277,54 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfg.DefaultAlertForDuration = uaMinInterval
 	}
 
+	rr := iniFile.Section("unified_alerting.recording_rules")
+	uaCfg.RecordingRules = RecordingRuleSettings{
+		Enabled:           ownKeyAsBool(rr, "enabled", false),
+		URL:               rr.Key("url").MustString(""),
+		BasicAuthUsername: rr.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: rr.Key("basic_auth_password").MustString(""),
+	}
+	uaCfg.RecordingRules.Timeout, err = gtime.ParseDuration(valueAsString(rr, "timeout", recordingRulesDefaultTimeout.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.RecordingRules.Enabled && uaCfg.RecordingRules.URL == "" {
+		return errors.New("setting 'url' in section 'unified_alerting.recording_rules' is required when recording rules are enabled")
+	}
+
+	sh := iniFile.Section("unified_alerting.state_history")
+	uaCfg.StateHistory.Enabled = ownKeyAsBool(sh, "enabled", true)
+	uaCfg.StateHistory.Retention, err = gtime.ParseDuration(valueAsString(sh, "retention", stateHistoryDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+
+	ss := iniFile.Section("unified_alerting.screenshots")
+	uaCfg.Screenshots = ScreenshotSettings{
+		Capture:                    ss.Key("capture").MustBool(false),
+		MaxConcurrentScreenshots:   ss.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent),
+		UploadExternalImageStorage: ss.Key("upload_external_image_storage").MustBool(false),
+	}
+	uaCfg.Screenshots.CaptureTimeout, err = gtime.ParseDuration(valueAsString(ss, "capture_timeout", screenshotsDefaultCaptureTimeout.String()))
+	if err != nil {
+		return err
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
 
+// ownKeyAsBool returns the value of a key of the section itself, ignoring the keys that child sections
+// inherit from their parent, e.g. the enabled key of [unified_alerting].
+func ownKeyAsBool(section *ini.Section, keyName string, defaultValue bool) bool {
+	for _, name := range section.KeyStrings() {
+		if name == keyName {
+			return section.Key(keyName).MustBool(defaultValue)
+		}
+	}
+	return defaultValue
+}
+
 func GetAlertmanagerDefaultConfiguration() string {
 	return alertmanagerDefaultConfiguration
 }
@@ -39,6 +39,42 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 3)
 		require.ElementsMatch(t, []string{"hostname1:9090", "hostname2:9090", "hostname3:9090"}, cfg.UnifiedAlerting.HAPeers)
 	}
+
+	// Recording rules are disabled by default and require a URL when enabled.
+	{
+		require.False(t, cfg.UnifiedAlerting.RecordingRules.Enabled)
+		require.Equal(t, 10*time.Second, cfg.UnifiedAlerting.RecordingRules.Timeout)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.recording_rules")
+		require.NoError(t, err)
+		_, err = s.NewKey("enabled", "true")
+		require.NoError(t, err)
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+
+		_, err = s.NewKey("url", "http://localhost:9090/api/v1/write")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.True(t, cfg.UnifiedAlerting.RecordingRules.Enabled)
+		require.Equal(t, "http://localhost:9090/api/v1/write", cfg.UnifiedAlerting.RecordingRules.URL)
+	}
+
+	// Recording rules are not enabled by the enabled key of [unified_alerting].
+	{
+		f := ini.Empty()
+		_, err := f.Section("unified_alerting").NewKey("enabled", "true")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		require.False(t, cfg.UnifiedAlerting.RecordingRules.Enabled)
+	}
+
+	// State history is not disabled by the enabled key of [unified_alerting].
+	{
+		f := ini.Empty()
+		_, err := f.Section("unified_alerting").NewKey("enabled", "false")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		require.True(t, cfg.UnifiedAlerting.StateHistory.Enabled)
+	}
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
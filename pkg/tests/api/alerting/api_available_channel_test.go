@@ -158,6 +158,245 @@ var expAvailableChannelJsonOutput = `
       }
     ]
   },
+  {
+    "type": "mqtt",
+    "name": "MQTT",
+    "heading": "MQTT settings",
+    "description": "Publishes notifications to an MQTT broker",
+    "info": "",
+    "options": [
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Broker URL",
+        "description": "The scheme is tcp, ssl, ws or wss.",
+        "placeholder": "tcp://localhost:1883",
+        "propertyName": "brokerUrl",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Topic",
+        "description": "You can use template variables.",
+        "placeholder": "grafana/alerts",
+        "propertyName": "topic",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Client ID",
+        "description": "The ID of the client. Defaults to grafana_ followed by the UID of the contact point.",
+        "placeholder": "",
+        "propertyName": "clientId",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "select",
+        "inputType": "",
+        "label": "Message format",
+        "description": "JSON publishes the alerts along with the message, text publishes the message only.",
+        "placeholder": "",
+        "propertyName": "messageFormat",
+        "selectOptions": [
+          {
+            "value": "json",
+            "label": "JSON"
+          },
+          {
+            "value": "text",
+            "label": "Text"
+          }
+        ],
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Message",
+        "description": "You can use template variables.",
+        "placeholder": "{{ template \"default.message\" . }}",
+        "propertyName": "message",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "select",
+        "inputType": "",
+        "label": "QoS",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "qos",
+        "selectOptions": [
+          {
+            "value": "0",
+            "label": "At most once (0)"
+          },
+          {
+            "value": "1",
+            "label": "At least once (1)"
+          },
+          {
+            "value": "2",
+            "label": "Exactly once (2)"
+          }
+        ],
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "checkbox",
+        "inputType": "",
+        "label": "Retain",
+        "description": "The broker keeps the last message of the topic for new subscribers.",
+        "placeholder": "",
+        "propertyName": "retain",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Username",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "username",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "password",
+        "label": "Password",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "password",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      },
+      {
+        "element": "checkbox",
+        "inputType": "",
+        "label": "Disable certificate verification",
+        "description": "Do not verify the certificate of the broker. Not recommended.",
+        "placeholder": "",
+        "propertyName": "insecureSkipVerify",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "CA certificate",
+        "description": "PEM encoded certificate of the authority that signed the certificate of the broker.",
+        "placeholder": "",
+        "propertyName": "tlsCACertificate",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Client certificate",
+        "description": "PEM encoded certificate used to authenticate to the broker.",
+        "placeholder": "",
+        "propertyName": "tlsClientCertificate",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Client key",
+        "description": "PEM encoded key of the client certificate.",
+        "placeholder": "",
+        "propertyName": "tlsClientKey",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      }
+    ]
+  },
   {
     "type": "email",
     "name": "Email",
@@ -1299,6 +1538,156 @@ var expAvailableChannelJsonOutput = `
       }
     ]
   },
+  {
+    "type": "http",
+    "name": "HTTP",
+    "heading": "HTTP settings",
+    "description": "Sends an HTTP request with a templated body to a URL",
+    "info": "",
+    "options": [
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "URL",
+        "description": "Templated URL of the request",
+        "placeholder": "",
+        "propertyName": "url",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "select",
+        "inputType": "",
+        "label": "HTTP Method",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "httpMethod",
+        "selectOptions": [
+          {
+            "value": "POST",
+            "label": "POST"
+          },
+          {
+            "value": "PUT",
+            "label": "PUT"
+          },
+          {
+            "value": "PATCH",
+            "label": "PATCH"
+          }
+        ],
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Content Type",
+        "description": "Content type of the body. JSON bodies are validated before they are sent.",
+        "placeholder": "application/json",
+        "propertyName": "contentType",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Headers",
+        "description": "Templated headers of the request, one per line in the format \"Name: value\"",
+        "placeholder": "X-Source: grafana",
+        "propertyName": "headers",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Body",
+        "description": "Templated body of the request",
+        "placeholder": "{\"summary\": \"{{ .CommonLabels.alertname }} is {{ .Status }}\"}",
+        "propertyName": "body",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Username",
+        "description": "Username for the HTTP basic authentication",
+        "placeholder": "",
+        "propertyName": "username",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "password",
+        "label": "Password",
+        "description": "Password for the HTTP basic authentication",
+        "placeholder": "",
+        "propertyName": "password",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Max Alerts",
+        "description": "Max alerts to include in a notification. Remaining alerts in the same batch will be ignored above this number. 0 means no limit.",
+        "placeholder": "",
+        "propertyName": "maxAlerts",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      }
+    ]
+  },
   {
 	"type": "wecom",
 	"name": "WeCom",
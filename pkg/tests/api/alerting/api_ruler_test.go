@@ -120,7 +120,8 @@ func TestAlertRulePermissions(t *testing.T) {
 						  "namespace_id":1,
 						  "rule_group":"arulegroup",
 						  "no_data_state":"NoData",
-						  "exec_err_state":"Alerting"
+						  "exec_err_state":"Alerting",
+						  "is_paused":false
 					   }
 					}
 				 ]
@@ -170,7 +171,8 @@ func TestAlertRulePermissions(t *testing.T) {
 						"namespace_id":2,
 						"rule_group":"arulegroup",
 						"no_data_state":"NoData",
-						"exec_err_state":"Alerting"
+						"exec_err_state":"Alerting",
+						"is_paused":false
 					 }
 				  }
 			   ]
@@ -242,7 +244,8 @@ func TestAlertRulePermissions(t *testing.T) {
 						  "namespace_id":1,
 						  "rule_group":"arulegroup",
 						  "no_data_state":"NoData",
-						  "exec_err_state":"Alerting"
+						  "exec_err_state":"Alerting",
+						  "is_paused":false
 					   }
 					}
 				 ]
@@ -600,7 +603,8 @@ func TestRulerRulesFilterByDashboard(t *testing.T) {
 				"namespace_id": 1,
 				"rule_group": "anotherrulegroup",
 				"no_data_state": "NoData",
-				"exec_err_state": "Alerting"
+				"exec_err_state": "Alerting",
+				"is_paused": false
 			}
 		}, {
 			"expr": "",
@@ -632,7 +636,8 @@ func TestRulerRulesFilterByDashboard(t *testing.T) {
 				"namespace_id": 1,
 				"rule_group": "anotherrulegroup",
 				"no_data_state": "Alerting",
-				"exec_err_state": "Alerting"
+				"exec_err_state": "Alerting",
+				"is_paused": false
 			}
 		}]
 	}]
@@ -677,7 +682,8 @@ func TestRulerRulesFilterByDashboard(t *testing.T) {
 				"namespace_id": 1,
 				"rule_group": "anotherrulegroup",
 				"no_data_state": "NoData",
-				"exec_err_state": "Alerting"
+				"exec_err_state": "Alerting",
+				"is_paused": false
 			}
 		}]
 	}]
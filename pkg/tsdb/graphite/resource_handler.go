@@ -0,0 +1,165 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const (
+	// resourceCacheTTL is how long the responses of the metrics and tags resources are cached. It is short so that
+	// new metrics and tags show up quickly, but saves the repeated requests of the query editor.
+	resourceCacheTTL = time.Minute
+	// functionsCacheTTL is how long the list of functions is cached, as it only changes when Graphite is upgraded.
+	functionsCacheTTL = time.Hour
+)
+
+// cachedResource is a response of Graphite to a resource request.
+type cachedResource struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", s.handleResourceReq(resourceCacheTTL))
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleResourceReq(resourceCacheTTL))
+	mux.HandleFunc("/tags/autoComplete/values", s.handleResourceReq(resourceCacheTTL))
+	mux.HandleFunc("/functions", s.handleResourceReq(functionsCacheTTL))
+	return mux
+}
+
+// handleResourceReq returns a handler that sends the resource request to the same path of Graphite, with the
+// client of the data source, and caches the successful responses for the given duration.
+func (s *Service) handleResourceReq(ttl time.Duration) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		s.logger.Debug("Received resource call", "url", req.URL.String(), "method", req.Method)
+
+		if req.Method != http.MethodGet && req.Method != http.MethodPost {
+			writeResourceError(rw, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", req.Method))
+			return
+		}
+		if err := req.ParseForm(); err != nil {
+			writeResourceError(rw, http.StatusBadRequest, fmt.Sprintf("invalid parameters: %v", err))
+			return
+		}
+
+		pluginCtx := httpadapter.PluginConfigFromContext(req.Context())
+		dsInfo, err := s.getDSInfo(pluginCtx)
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+
+		key := req.URL.Path + "?" + req.Form.Encode()
+		if cached, ok := dsInfo.resourceCache.Get(key); ok {
+			writeResource(rw, cached.(*cachedResource))
+			return
+		}
+
+		ctx, span := s.tracer.Start(req.Context(), "graphite resource")
+		span.SetAttributes("path", req.URL.Path, attribute.Key("path").String(req.URL.Path))
+		span.SetAttributes("datasource_id", dsInfo.Id, attribute.Key("datasource_id").Int64(dsInfo.Id))
+		span.SetAttributes("org_id", pluginCtx.OrgID, attribute.Key("org_id").Int64(pluginCtx.OrgID))
+		defer span.End()
+
+		resource, err := s.getResource(ctx, span, dsInfo, req.Method, req.URL.Path, req.Form)
+		if err != nil {
+			s.logger.Error("Resource request failed", "path", req.URL.Path, "err", err)
+			writeResourceError(rw, http.StatusBadGateway, fmt.Sprintf("failed to request Graphite: %v", err))
+			return
+		}
+
+		if resource.status == http.StatusOK {
+			dsInfo.resourceCache.Set(key, resource, ttl)
+		}
+		writeResource(rw, resource)
+	}
+}
+
+func (s *Service) getResource(ctx context.Context, span tracing.Span, dsInfo *datasourceInfo, method, resourcePath string, params url.Values) (*cachedResource, error) {
+	graphiteReq, err := s.createResourceRequest(ctx, dsInfo, method, resourcePath, params)
+	if err != nil {
+		return nil, err
+	}
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := ctxhttp.Do(ctx, dsInfo.HTTPClient, graphiteReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			s.logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachedResource{
+		status:      res.StatusCode,
+		contentType: res.Header.Get("Content-Type"),
+		body:        body,
+	}, nil
+}
+
+func (s *Service) createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, method, resourcePath string, params url.Values) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+
+	var req *http.Request
+	if method == http.MethodPost {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(params.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		u.RawQuery = params.Encode()
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	}
+	if err != nil {
+		s.logger.Info("Failed to create request", "error", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return req, nil
+}
+
+func writeResource(rw http.ResponseWriter, resource *cachedResource) {
+	if resource.contentType != "" {
+		rw.Header().Set("Content-Type", resource.contentType)
+	}
+	rw.WriteHeader(resource.status)
+	_, _ = rw.Write(resource.body)
+}
+
+func writeResourceError(rw http.ResponseWriter, status int, message string) {
+	body, err := json.Marshal(map[string]string{"message": message})
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(body)
+}
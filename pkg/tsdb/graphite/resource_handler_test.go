@@ -0,0 +1,166 @@
+package graphite
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	if s.resp == nil {
+		s.resp = resp
+		return nil
+	}
+	s.resp.Body = append(s.resp.Body, resp.Body...)
+	return nil
+}
+
+type graphiteRequest struct {
+	method string
+	path   string
+	params url.Values
+}
+
+func TestResourceHandler(t *testing.T) {
+	var requests []graphiteRequest
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		requests = append(requests, graphiteRequest{method: r.Method, path: r.URL.Path, params: r.Form})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`["` + r.URL.Path + `"]`))
+	}))
+	defer server.Close()
+
+	tracer, err := tracing.InitializeTracerForTest()
+	require.NoError(t, err)
+
+	setup := func() *Service {
+		requests = nil
+		status = http.StatusOK
+		s := &Service{
+			logger: log.New("tsdb.graphite"),
+			tracer: tracer,
+			im: datasource.NewInstanceManager(func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+				return datasourceInfo{
+					HTTPClient:    server.Client(),
+					URL:           server.URL + "/graphite",
+					Id:            settings.ID,
+					resourceCache: localcache.New(resourceCacheTTL, 2*resourceCacheTTL),
+				}, nil
+			}),
+		}
+		s.resourceHandler = httpadapter.New(s.newResourceMux())
+		return s
+	}
+
+	call := func(t *testing.T, s *Service, method, rawURL string) *backend.CallResourceResponse {
+		t.Helper()
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		req := &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, UID: "graphite"},
+			},
+			Path:   strings.TrimPrefix(u.Path, "/"),
+			Method: method,
+			URL:    rawURL,
+		}
+		if method == http.MethodPost {
+			req.URL = u.Path
+			req.Body = []byte(u.RawQuery)
+			req.Headers = map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}}
+		}
+
+		sender := &fakeSender{}
+		require.NoError(t, s.CallResource(context.Background(), req, sender))
+		require.NotNil(t, sender.resp)
+		return sender.resp
+	}
+
+	t.Run("sends the resource requests to Graphite", func(t *testing.T) {
+		for _, path := range []string{"/metrics/find", "/tags/autoComplete/tags", "/tags/autoComplete/values", "/functions"} {
+			s := setup()
+			resp := call(t, s, http.MethodGet, path+"?expr=name%3Dapp&limit=10")
+			require.Equal(t, http.StatusOK, resp.Status, path)
+			require.Equal(t, `["/graphite`+path+`"]`, string(resp.Body), path)
+			require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"], path)
+
+			require.Len(t, requests, 1, path)
+			require.Equal(t, http.MethodGet, requests[0].method)
+			require.Equal(t, "/graphite"+path, requests[0].path)
+			require.Equal(t, url.Values{"expr": {"name=app"}, "limit": {"10"}}, requests[0].params)
+		}
+	})
+
+	t.Run("sends the parameters of POST requests in the body", func(t *testing.T) {
+		s := setup()
+		resp := call(t, s, http.MethodPost, "/metrics/find?query=app.*&from=-1h")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Equal(t, http.MethodPost, requests[0].method)
+		require.Equal(t, url.Values{"query": {"app.*"}, "from": {"-1h"}}, requests[0].params)
+	})
+
+	t.Run("caches the successful responses", func(t *testing.T) {
+		s := setup()
+		call(t, s, http.MethodGet, "/metrics/find?query=app.*")
+		resp := call(t, s, http.MethodGet, "/metrics/find?query=app.*")
+		require.Equal(t, `["/graphite/metrics/find"]`, string(resp.Body))
+		require.Len(t, requests, 1)
+
+		call(t, s, http.MethodGet, "/metrics/find?query=app.grafana.*")
+		require.Len(t, requests, 2)
+	})
+
+	t.Run("does not cache the errors of Graphite", func(t *testing.T) {
+		s := setup()
+		status = http.StatusInternalServerError
+		resp := call(t, s, http.MethodGet, "/functions")
+		require.Equal(t, http.StatusInternalServerError, resp.Status)
+		call(t, s, http.MethodGet, "/functions")
+		require.Len(t, requests, 2)
+	})
+
+	t.Run("rejects other methods and paths", func(t *testing.T) {
+		s := setup()
+		resp := call(t, s, http.MethodDelete, "/functions")
+		require.Equal(t, http.StatusMethodNotAllowed, resp.Status)
+		resp = call(t, s, http.MethodGet, "/render")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		require.Empty(t, requests)
+	})
+}
+
+func TestCreateResourceRequest(t *testing.T) {
+	s := &Service{logger: log.New("tsdb.graphite")}
+	req, err := s.createResourceRequest(context.Background(), &datasourceInfo{URL: "http://graphite:8080/"}, http.MethodGet, "/tags/autoComplete/tags", url.Values{"tagPrefix": {"na"}})
+	require.NoError(t, err)
+	require.Equal(t, "http://graphite:8080/tags/autoComplete/tags?tagPrefix=na", req.URL.String())
+
+	req, err = s.createResourceRequest(context.Background(), &datasourceInfo{URL: "http://graphite:8080"}, http.MethodPost, "/metrics/find", url.Values{"query": {"*"}})
+	require.NoError(t, err)
+	require.Equal(t, "http://graphite:8080/metrics/find", req.URL.String())
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, "query=%2A", string(body))
+}
@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/experimental"
 	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/logproto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,6 +33,8 @@ func TestSuccessResponse(t *testing.T) {
 		// you can produce Infinity by using `quantile_over_time(42,` (value larger than 1)
 		{name: "parse a matrix response with Infinity", filepath: "matrix_inf"},
 		{name: "parse a matrix response with very small step value", filepath: "matrix_small_step"},
+		{name: "parse a vector response", filepath: "vector_simple"},
+		{name: "parse a streams response", filepath: "streams_simple"},
 	}
 
 	for _, test := range tt {
@@ -41,7 +45,7 @@ func TestSuccessResponse(t *testing.T) {
 			bytes, err := os.ReadFile(responseFileName)
 			require.NoError(t, err)
 
-			frames, err := runQuery(makeMockedClient(200, "application/json", bytes), &lokiQuery{})
+			frames, err := runQuery(makeMockedClient(200, "application/json", bytes), &lokiQuery{RefID: "A", MaxLines: 100})
 			require.NoError(t, err)
 
 			dr := &backend.DataResponse{
@@ -111,13 +115,55 @@ func TestErrorResponse(t *testing.T) {
 	}
 }
 
+func TestRunQuery(t *testing.T) {
+	body := []byte(`{"status": "success", "data": {"resultType": "streams", "result": []}}`)
+
+	t.Run("sends range queries with the limit and the direction", func(t *testing.T) {
+		client := makeMockedClient(200, "application/json", body)
+		_, err := runQuery(client, &lokiQuery{
+			Expr:      `{app="backend"}`,
+			QueryType: QueryTypeRange,
+			Direction: logproto.BACKWARD,
+			MaxLines:  20,
+			Start:     time.Unix(100, 0),
+			End:       time.Unix(200, 0),
+			Step:      time.Second,
+		})
+		require.NoError(t, err)
+		req := mockedRequest(client)
+		require.Equal(t, "/loki/api/v1/query_range", req.URL.Path)
+		require.Equal(t, "20", req.URL.Query().Get("limit"))
+		require.Equal(t, "BACKWARD", req.URL.Query().Get("direction"))
+	})
+
+	t.Run("sends instant queries at the end of the time range", func(t *testing.T) {
+		client := makeMockedClient(200, "application/json", body)
+		_, err := runQuery(client, &lokiQuery{
+			Expr:      `count_over_time({app="backend"}[5m])`,
+			QueryType: QueryTypeInstant,
+			Direction: logproto.FORWARD,
+			MaxLines:  20,
+			Start:     time.Unix(100, 0),
+			End:       time.Unix(200, 0),
+		})
+		require.NoError(t, err)
+		req := mockedRequest(client)
+		require.Equal(t, "/loki/api/v1/query", req.URL.Path)
+		require.Equal(t, "200000000000", req.URL.Query().Get("time"))
+		require.Equal(t, "FORWARD", req.URL.Query().Get("direction"))
+	})
+}
+
 type MockedRoundTripper struct {
 	statusCode    int
 	responseBytes []byte
 	contentType   string
+	// request is the last request sent to the client
+	request *http.Request
 }
 
 func (mockedRT *MockedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	mockedRT.request = req
 	header := http.Header{}
 	header.Add("Content-Type", mockedRT.contentType)
 	return &http.Response{
@@ -128,12 +174,17 @@ func (mockedRT *MockedRoundTripper) RoundTrip(req *http.Request) (*http.Response
 }
 
 func makeMockedClient(statusCode int, contentType string, responseBytes []byte) *client.DefaultClient {
+	roundTripper := &MockedRoundTripper{statusCode: statusCode, responseBytes: responseBytes, contentType: contentType}
 	client := &client.DefaultClient{
 		Address: "http://localhost:9999",
 		Tripperware: func(t http.RoundTripper) http.RoundTripper {
-			return &MockedRoundTripper{statusCode: statusCode, responseBytes: responseBytes, contentType: contentType}
+			return roundTripper
 		},
 	}
 
 	return client
 }
+
+func mockedRequest(client *client.DefaultClient) *http.Request {
+	return client.Tripperware(nil).(*MockedRoundTripper).request
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,7 +20,6 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/loki/pkg/logcli/client"
 	"github.com/grafana/loki/pkg/loghttp"
-	"github.com/grafana/loki/pkg/logproto"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/prometheus/common/config"
@@ -44,13 +44,20 @@ var (
 	legendFormat = regexp.MustCompile(`\{\{\s*(.+?)\s*\}\}`)
 )
 
+// defaultMaxLines is the maximum number of log lines returned by a query if the data source does not set it.
+const defaultMaxLines = 1000
+
 type datasourceInfo struct {
 	HTTPClient        *http.Client
 	URL               string
 	TLSClientConfig   *tls.Config
 	BasicAuthUser     string
 	BasicAuthPassword string
-	TimeInterval      string `json:"timeInterval"`
+	// Headers are the custom HTTP headers of the data source, which are also sent when tailing logs.
+	Headers      map[string]string
+	TimeInterval string `json:"timeInterval"`
+	// MaxLines is the maximum number of log lines returned by a query.
+	MaxLines int
 }
 
 type QueryModel struct {
@@ -60,6 +67,12 @@ type QueryModel struct {
 	Interval     string `json:"interval"`
 	IntervalMS   int    `json:"intervalMS"`
 	Resolution   int64  `json:"resolution"`
+	MaxLines     int    `json:"maxLines"`
+	// Direction is the order of the log lines: backward returns the newest lines first and forward the oldest ones.
+	Direction string `json:"direction"`
+	// Instant and Range are used by older queries instead of the query type.
+	Instant bool `json:"instant"`
+	Range   bool `json:"range"`
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -79,12 +92,23 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
-		jsonData := datasourceInfo{}
+		jsonData := struct {
+			TimeInterval string `json:"timeInterval"`
+			MaxLines     string `json:"maxLines"`
+		}{}
 		err = json.Unmarshal(settings.JSONData, &jsonData)
 		if err != nil {
 			return nil, fmt.Errorf("error reading settings: %w", err)
 		}
 
+		maxLines := defaultMaxLines
+		if jsonData.MaxLines != "" {
+			// the frontend also falls back to the default if the setting is invalid
+			if n, err := strconv.Atoi(jsonData.MaxLines); err == nil && n > 0 {
+				maxLines = n
+			}
+		}
+
 		model := &datasourceInfo{
 			HTTPClient:        client,
 			URL:               settings.URL,
@@ -92,6 +116,8 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			TimeInterval:      jsonData.TimeInterval,
 			BasicAuthUser:     settings.BasicAuthUser,
 			BasicAuthPassword: settings.DecryptedSecureJSONData["basicAuthPassword"],
+			Headers:           opts.Headers,
+			MaxLines:          maxLines,
 		}
 		return model, nil
 	}
@@ -123,6 +149,11 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	}
 
 	for _, query := range queries {
+		// the maximum number of lines of the data source is also the default of the queries
+		if query.MaxLines <= 0 || query.MaxLines > dsInfo.MaxLines {
+			query.MaxLines = dsInfo.MaxLines
+		}
+
 		s.plog.Debug("Sending query", "start", query.Start, "end", query.End, "step", query.Step, "query", query.Expr)
 		_, span := s.tracer.Start(ctx, "alerting.loki")
 		span.SetAttributes("expr", query.Expr, attribute.Key("expr").String(query.Expr))
@@ -165,13 +196,20 @@ func formatLegend(metric model.Metric, query *lokiQuery) string {
 }
 
 func parseResponse(value *loghttp.QueryResponse, query *lokiQuery) (data.Frames, error) {
-	frames := data.Frames{}
-
-	//We are currently processing only matrix results (for alerting)
-	matrix, ok := value.Data.Result.(loghttp.Matrix)
-	if !ok {
-		return frames, fmt.Errorf("unsupported result format: %q", value.Data.ResultType)
+	switch result := value.Data.Result.(type) {
+	case loghttp.Matrix:
+		return parseMatrix(result, query), nil
+	case loghttp.Vector:
+		return parseVector(result, query), nil
+	case loghttp.Streams:
+		return parseStreams(result, query), nil
+	default:
+		return data.Frames{}, fmt.Errorf("unsupported result format: %q", value.Data.ResultType)
 	}
+}
+
+func parseMatrix(matrix loghttp.Matrix, query *lokiQuery) data.Frames {
+	frames := data.Frames{}
 
 	for _, v := range matrix {
 		name := formatLegend(v.Metric, query)
@@ -195,19 +233,42 @@ func parseResponse(value *loghttp.QueryResponse, query *lokiQuery) (data.Frames,
 		frames = append(frames, data.NewFrame(name, timeField, valueField))
 	}
 
-	return frames, nil
+	return frames
 }
 
-// we extracted this part of the functionality to make it easy to unit-test it
-func runQuery(client *client.DefaultClient, query *lokiQuery) (data.Frames, error) {
-	// `limit` only applies to log-producing queries, and we
-	// currently only support metric queries, so this can be set to any value.
-	limit := 1
+// parseVector returns a frame with a single row for each sample of the result of an instant query.
+func parseVector(vector loghttp.Vector, query *lokiQuery) data.Frames {
+	frames := data.Frames{}
 
-	// we do not use `interval`, so we set it to zero
-	interval := time.Duration(0)
+	for _, v := range vector {
+		name := formatLegend(v.Metric, query)
+		tags := make(map[string]string, len(v.Metric))
+		for k, v := range v.Metric {
+			tags[string(k)] = string(v)
+		}
+
+		timeField := data.NewField("time", nil, []time.Time{v.Timestamp.Time().UTC()})
+		valueField := data.NewField("value", tags, []float64{float64(v.Value)}).SetConfig(&data.FieldConfig{DisplayNameFromDS: name})
+
+		frames = append(frames, data.NewFrame(name, timeField, valueField))
+	}
+
+	return frames
+}
 
-	value, err := client.QueryRange(query.Expr, limit, query.Start, query.End, logproto.BACKWARD, query.Step, interval, false)
+// we extracted this part of the functionality to make it easy to unit-test it
+func runQuery(client *client.DefaultClient, query *lokiQuery) (data.Frames, error) {
+	var (
+		value *loghttp.QueryResponse
+		err   error
+	)
+	if query.QueryType == QueryTypeInstant {
+		value, err = client.Query(query.Expr, query.MaxLines, query.End, query.Direction, false)
+	} else {
+		// we do not use `interval`, so we set it to zero
+		interval := time.Duration(0)
+		value, err = client.QueryRange(query.Expr, query.MaxLines, query.Start, query.End, query.Direction, query.Step, interval, false)
+	}
 	if err != nil {
 		return data.Frames{}, err
 	}
@@ -41,11 +41,12 @@ func TestLoki(t *testing.T) {
 }
 
 func TestParseResponse(t *testing.T) {
-	t.Run("value is not of type matrix", func(t *testing.T) {
+	t.Run("value is of an unsupported type", func(t *testing.T) {
 		queryRes := data.Frames{}
 		value := loghttp.QueryResponse{
 			Data: loghttp.QueryResponseData{
-				Result: loghttp.Vector{},
+				ResultType: loghttp.ResultTypeScalar,
+				Result:     loghttp.Scalar{},
 			},
 		}
 		res, err := parseResponse(&value, nil)
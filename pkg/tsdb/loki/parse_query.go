@@ -2,6 +2,7 @@ package loki
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+	"github.com/grafana/loki/pkg/logproto"
 )
 
 const (
@@ -52,6 +54,32 @@ func interpolateVariables(expr string, interval time.Duration, timeRange time.Du
 	return expr
 }
 
+func parseQueryType(model *QueryModel) (QueryType, error) {
+	switch QueryType(model.QueryType) {
+	case QueryTypeRange, QueryTypeInstant:
+		return QueryType(model.QueryType), nil
+	case "":
+		// older queries have a flag instead of the query type
+		if model.Instant && !model.Range {
+			return QueryTypeInstant, nil
+		}
+		return QueryTypeRange, nil
+	default:
+		return "", fmt.Errorf("unsupported query type: %q", model.QueryType)
+	}
+}
+
+func parseDirection(direction string) (logproto.Direction, error) {
+	switch strings.ToLower(direction) {
+	case "", "backward":
+		return logproto.BACKWARD, nil
+	case "forward":
+		return logproto.FORWARD, nil
+	default:
+		return logproto.BACKWARD, fmt.Errorf("unsupported direction: %q", direction)
+	}
+}
+
 func parseQuery(queryContext *backend.QueryDataRequest) ([]*lokiQuery, error) {
 	qs := []*lokiQuery{}
 	for _, query := range queryContext.Queries {
@@ -76,8 +104,21 @@ func parseQuery(queryContext *backend.QueryDataRequest) ([]*lokiQuery, error) {
 
 		expr := interpolateVariables(model.Expr, interval, timeRange)
 
+		queryType, err := parseQueryType(model)
+		if err != nil {
+			return nil, err
+		}
+
+		direction, err := parseDirection(model.Direction)
+		if err != nil {
+			return nil, err
+		}
+
 		qs = append(qs, &lokiQuery{
 			Expr:         expr,
+			QueryType:    queryType,
+			Direction:    direction,
+			MaxLines:     model.MaxLines,
 			Step:         step,
 			LegendFormat: model.LegendFormat,
 			Start:        start,
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/loki/pkg/logproto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,6 +34,31 @@ func TestParseQuery(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, time.Second*15, models[0].Step)
 		require.Equal(t, "go_goroutines 15s 15000 3000s 3000 3000000", models[0].Expr)
+		require.Equal(t, QueryTypeRange, models[0].QueryType)
+		require.Equal(t, logproto.BACKWARD, models[0].Direction)
+	})
+	t.Run("parsing query type, direction and max lines", func(t *testing.T) {
+		parse := func(json string) ([]*lokiQuery, error) {
+			return parseQuery(&backend.QueryDataRequest{
+				Queries: []backend.DataQuery{{JSON: []byte(json), RefID: "A"}},
+			})
+		}
+
+		models, err := parse(`{"expr": "{app=\"backend\"}", "queryType": "instant", "direction": "FORWARD", "maxLines": 20}`)
+		require.NoError(t, err)
+		require.Equal(t, QueryTypeInstant, models[0].QueryType)
+		require.Equal(t, logproto.FORWARD, models[0].Direction)
+		require.Equal(t, 20, models[0].MaxLines)
+
+		models, err = parse(`{"expr": "{app=\"backend\"}", "instant": true}`)
+		require.NoError(t, err)
+		require.Equal(t, QueryTypeInstant, models[0].QueryType)
+
+		_, err = parse(`{"expr": "{app=\"backend\"}", "queryType": "stream"}`)
+		require.EqualError(t, err, `unsupported query type: "stream"`)
+
+		_, err = parse(`{"expr": "{app=\"backend\"}", "direction": "sideways"}`)
+		require.EqualError(t, err, `unsupported direction: "sideways"`)
 	})
 	t.Run("interpolate variables, range between 1s and 0.5s", func(t *testing.T) {
 		expr := "go_goroutines $__interval $__interval_ms $__range $__range_s $__range_ms"
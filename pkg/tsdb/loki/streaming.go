@@ -0,0 +1,249 @@
+package loki
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+// tailPathPrefix is the prefix of the paths of the streams tailing the logs of a query. The rest of the path is
+// the hash of the query, so that all the subscribers of the same query share a single connection to Loki.
+const tailPathPrefix = "tail/"
+
+// tailQuery is the query of a tail stream, sent as the data of the subscription.
+type tailQuery struct {
+	QueryModel
+	RefID string `json:"refId"`
+}
+
+func (s *Service) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if !strings.HasPrefix(req.Path, tailPathPrefix) {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, nil
+	}
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := parseTailQuery(req.Path, req.Data, dsInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	initialData, err := backend.NewInitialFrame(newTailFrame(query), data.IncludeSchemaOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backend.SubscribeStreamResponse{
+		Status:      backend.SubscribeStreamStatusOK,
+		InitialData: initialData,
+	}, nil
+}
+
+func (s *Service) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return &backend.PublishStreamResponse{
+		Status: backend.PublishStreamStatusPermissionDenied,
+	}, nil
+}
+
+// RunStream tails the logs of the query of the stream, and sends the lines received from Loki as frames appended
+// to the frames of the stream.
+func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	if !strings.HasPrefix(req.Path, tailPathPrefix) {
+		return fmt.Errorf("loki plugin does not support path: %s", req.Path)
+	}
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	query, err := parseTailQuery(req.Path, req.Data, dsInfo)
+	if err != nil {
+		return err
+	}
+
+	s.plog.Debug("Tailing logs", "path", req.Path, "query", query.Expr)
+	conn, err := dialTail(ctx, dsInfo, query, time.Now())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			s.plog.Warn("Failed to close the connection to Loki", "err", err)
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		for {
+			var resp loghttp.TailResponse
+			if err := conn.ReadJSON(&resp); err != nil {
+				done <- fmt.Errorf("failed to read the logs tailed from Loki: %w", err)
+				return
+			}
+			if len(resp.DroppedStreams) > 0 {
+				s.plog.Warn("Loki dropped log lines of the tailed streams", "path", req.Path, "streams", len(resp.DroppedStreams))
+			}
+
+			frame := parseTailStreams(resp.Streams, query)
+			if frame.Rows() == 0 {
+				continue
+			}
+			if err := sender.SendFrame(frame, data.IncludeDataOnly); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		if err := conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second)); err != nil {
+			s.plog.Debug("Failed to send the close message to Loki", "err", err)
+		}
+		return nil
+	case err := <-done:
+		return err
+	}
+}
+
+// parseTailQuery returns the query of the stream with the given path. The path must be the path of the query, so
+// that a stream shared by several subscribers always tails the logs of the same query.
+func parseTailQuery(path string, raw json.RawMessage, dsInfo *datasourceInfo) (*lokiQuery, error) {
+	model := &tailQuery{}
+	if err := json.Unmarshal(raw, model); err != nil {
+		return nil, fmt.Errorf("failed to read the query of the stream: %w", err)
+	}
+	if model.Expr == "" {
+		return nil, fmt.Errorf("the query of the stream has no expression")
+	}
+	if path != tailPath(model) {
+		return nil, fmt.Errorf("the path %s of the stream does not match its query", path)
+	}
+
+	maxLines := model.MaxLines
+	if maxLines <= 0 || maxLines > dsInfo.MaxLines {
+		maxLines = dsInfo.MaxLines
+	}
+
+	return &lokiQuery{
+		Expr:     model.Expr,
+		MaxLines: maxLines,
+		RefID:    model.RefID,
+	}, nil
+}
+
+// tailPath returns the path of the stream tailing the logs of the query: the hex-encoded SHA-256 hash of the
+// expression, maximum number of lines and RefID of the query, separated by new lines.
+func tailPath(model *tailQuery) string {
+	h := sha256.Sum256([]byte(model.Expr + "\n" + strconv.Itoa(model.MaxLines) + "\n" + model.RefID))
+	return tailPathPrefix + hex.EncodeToString(h[:])
+}
+
+// dialTail opens a websocket connection to the tail endpoint of Loki, with the TLS settings, the basic
+// authentication and the custom headers of the data source.
+func dialTail(ctx context.Context, dsInfo *datasourceInfo, query *lokiQuery, start time.Time) (*websocket.Conn, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL of the data source: %w", err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/loki/api/v1/tail"
+
+	params := url.Values{}
+	params.Set("query", query.Expr)
+	params.Set("limit", strconv.Itoa(query.MaxLines))
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	u.RawQuery = params.Encode()
+
+	header := http.Header{}
+	for name, value := range dsInfo.Headers {
+		header.Set(name, value)
+	}
+	if dsInfo.BasicAuthUser != "" {
+		credentials := dsInfo.BasicAuthUser + ":" + dsInfo.BasicAuthPassword
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 45 * time.Second,
+		TLSClientConfig:  dsInfo.TLSClientConfig,
+	}
+	conn, resp, err := dialer.DialContext(ctx, u.String(), header)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to tail the logs of Loki: %w (status %s)", err, resp.Status)
+		}
+		return nil, fmt.Errorf("failed to tail the logs of Loki: %w", err)
+	}
+	return conn, nil
+}
+
+// newTailFrame returns an empty frame of the lines of a tail stream. Unlike the frames of log queries, all the
+// streams share the same frame so that its schema does not change, and the labels of each line are in a field.
+func newTailFrame(query *lokiQuery) *data.Frame {
+	frame := data.NewFrame("",
+		data.NewField("ts", nil, []time.Time{}).SetConfig(&data.FieldConfig{DisplayName: "Time"}),
+		data.NewField("line", nil, []string{}),
+		data.NewField("id", nil, []string{}),
+		data.NewField("tsNs", nil, []string{}).SetConfig(&data.FieldConfig{DisplayName: "Time ns"}),
+		data.NewField("labels", nil, []string{}),
+	)
+	frame.RefID = query.RefID
+	frame.Meta = &data.FrameMeta{
+		PreferredVisualization: data.VisTypeLogs,
+		Custom:                 map[string]interface{}{"limit": query.MaxLines},
+	}
+	return frame
+}
+
+// parseTailStreams returns a frame with the lines of all the streams of a message of the tail endpoint.
+func parseTailStreams(streams []loghttp.Stream, query *lokiQuery) *data.Frame {
+	frame := newTailFrame(query)
+
+	for _, stream := range streams {
+		labelsString := idLabels(stream.Labels)
+		usedIDs := make(map[string]int, len(stream.Entries))
+
+		for _, entry := range stream.Entries {
+			ts := strconv.FormatInt(entry.Timestamp.UnixNano(), 10)
+			frame.AppendRow(
+				entry.Timestamp.UTC(),
+				entry.Line,
+				lineID(ts, labelsString, entry.Line, query.RefID, usedIDs),
+				ts,
+				stream.Labels.String(),
+			)
+		}
+	}
+
+	return frame
+}
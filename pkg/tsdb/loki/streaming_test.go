@@ -0,0 +1,175 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/stretchr/testify/require"
+)
+
+type packetRecorder struct {
+	mu      sync.Mutex
+	packets []*backend.StreamPacket
+}
+
+func (r *packetRecorder) Send(packet *backend.StreamPacket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.packets = append(r.packets, packet)
+	return nil
+}
+
+func (r *packetRecorder) received() []*backend.StreamPacket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*backend.StreamPacket{}, r.packets...)
+}
+
+func newStreamingService(dsInfo *datasourceInfo) (*Service, backend.PluginContext) {
+	s := &Service{
+		im: datasource.NewInstanceManager(func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return dsInfo, nil
+		}),
+		plog: log.New("test"),
+	}
+	pCtx := backend.PluginContext{
+		OrgID:                      1,
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, UID: "loki"},
+	}
+	return s, pCtx
+}
+
+func TestSubscribeStream(t *testing.T) {
+	s, pCtx := newStreamingService(&datasourceInfo{URL: "http://localhost:3100", MaxLines: 100})
+
+	t.Run("accepts the tail path of the query", func(t *testing.T) {
+		resp, err := s.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{
+			PluginContext: pCtx,
+			Path:          tailPath(&tailQuery{QueryModel: QueryModel{Expr: `{job="app"}`}, RefID: "A"}),
+			Data:          json.RawMessage(`{"expr": "{job=\"app\"}", "refId": "A"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.SubscribeStreamStatusOK, resp.Status)
+		require.NotNil(t, resp.InitialData)
+	})
+
+	t.Run("rejects tail paths of other queries", func(t *testing.T) {
+		for _, path := range []string{
+			"tail/abc",
+			tailPath(&tailQuery{QueryModel: QueryModel{Expr: `{job="other"}`}, RefID: "A"}),
+			tailPath(&tailQuery{QueryModel: QueryModel{Expr: `{job="app"}`, MaxLines: 10}, RefID: "A"}),
+		} {
+			_, err := s.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{
+				PluginContext: pCtx,
+				Path:          path,
+				Data:          json.RawMessage(`{"expr": "{job=\"app\"}", "refId": "A"}`),
+			})
+			require.Error(t, err, path)
+		}
+	})
+
+	t.Run("rejects unknown paths", func(t *testing.T) {
+		resp, err := s.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{
+			PluginContext: pCtx,
+			Path:          "metrics/abc",
+			Data:          json.RawMessage(`{"expr": "{job=\"app\"}"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.SubscribeStreamStatusNotFound, resp.Status)
+	})
+
+	t.Run("rejects queries without expression", func(t *testing.T) {
+		_, err := s.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{
+			PluginContext: pCtx,
+			Path:          "tail/abc",
+			Data:          json.RawMessage(`{"refId": "A"}`),
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestRunStream(t *testing.T) {
+	var request *http.Request
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		message := `{"streams": [{"stream": {"job": "app"}, "values": [["1645030244810757120", "first line"], ["1645030245810757120", "second line"]]}]}`
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+			return
+		}
+		// keep the connection open until the stream is stopped
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	s, pCtx := newStreamingService(&datasourceInfo{
+		URL:               server.URL + "/",
+		MaxLines:          100,
+		BasicAuthUser:     "user",
+		BasicAuthPassword: "password",
+		Headers:           map[string]string{"X-Scope-OrgID": "tenant"},
+	})
+
+	recorder := &packetRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.RunStream(ctx, &backend.RunStreamRequest{
+			PluginContext: pCtx,
+			Path:          tailPath(&tailQuery{QueryModel: QueryModel{Expr: `{job="app"}`, MaxLines: 500}, RefID: "A"}),
+			Data:          json.RawMessage(`{"expr": "{job=\"app\"}", "maxLines": 500, "refId": "A"}`),
+		}, backend.NewStreamSender(recorder))
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(recorder.received()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	require.Equal(t, "/loki/api/v1/tail", request.URL.Path)
+	require.Equal(t, `{job="app"}`, request.URL.Query().Get("query"))
+	require.Equal(t, "100", request.URL.Query().Get("limit"))
+	require.NotEmpty(t, request.URL.Query().Get("start"))
+	user, password, ok := request.BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "user", user)
+	require.Equal(t, "password", password)
+	require.Equal(t, "tenant", request.Header.Get("X-Scope-OrgID"))
+
+	packets := recorder.received()
+	require.Len(t, packets, 1)
+	frame := string(packets[0].Data)
+	require.True(t, strings.Contains(frame, "first line"))
+	require.True(t, strings.Contains(frame, "second line"))
+	require.True(t, strings.Contains(frame, `{job=\"app\"}`))
+}
+
+func TestRunStreamUnknownPath(t *testing.T) {
+	s, pCtx := newStreamingService(&datasourceInfo{URL: "http://localhost:3100", MaxLines: 100})
+	err := s.RunStream(context.Background(), &backend.RunStreamRequest{
+		PluginContext: pCtx,
+		Path:          "metrics/abc",
+	}, backend.NewStreamSender(&packetRecorder{}))
+	require.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package loki
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+// lineIDNamespace is the namespace of the ids of the log lines, which is the same as in the frontend
+// so that a line has the same id whether it was queried by the frontend or the backend.
+var lineIDNamespace = uuid.MustParse("6ec946da-0f49-47a8-983a-1d76d17e7c92")
+
+// parseStreams returns a frame for each stream of the result of a log query, with the same fields as the
+// frames of the frontend: the time, the line with the labels of the stream, the id and the time in nanoseconds.
+func parseStreams(streams loghttp.Streams, query *lokiQuery) data.Frames {
+	frames := data.Frames{}
+
+	for _, stream := range streams {
+		labels := make(map[string]string, len(stream.Labels))
+		for k, v := range stream.Labels {
+			labels[k] = v
+		}
+		labelsString := idLabels(stream.Labels)
+
+		timeVector := make([]time.Time, 0, len(stream.Entries))
+		timeNsVector := make([]string, 0, len(stream.Entries))
+		lines := make([]string, 0, len(stream.Entries))
+		ids := make([]string, 0, len(stream.Entries))
+		usedIDs := make(map[string]int, len(stream.Entries))
+
+		for _, entry := range stream.Entries {
+			ts := strconv.FormatInt(entry.Timestamp.UnixNano(), 10)
+			timeVector = append(timeVector, entry.Timestamp.UTC())
+			timeNsVector = append(timeNsVector, ts)
+			lines = append(lines, entry.Line)
+			ids = append(ids, lineID(ts, labelsString, entry.Line, query.RefID, usedIDs))
+		}
+
+		timeField := data.NewField("ts", nil, timeVector).SetConfig(&data.FieldConfig{DisplayName: "Time"})
+		lineField := data.NewField("line", labels, lines)
+		idField := data.NewField("id", nil, ids)
+		timeNsField := data.NewField("tsNs", nil, timeNsVector).SetConfig(&data.FieldConfig{DisplayName: "Time ns"})
+
+		frame := data.NewFrame("", timeField, lineField, idField, timeNsField)
+		frame.RefID = query.RefID
+		frame.Meta = &data.FrameMeta{
+			PreferredVisualization: data.VisTypeLogs,
+			Custom:                 map[string]interface{}{"limit": query.MaxLines},
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+// idLabels returns the labels of a stream in the format used in the ids of its lines.
+func idLabels(labels loghttp.LabelSet) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"=\""+v+"\"")
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "")
+}
+
+// lineID returns a stable id of a log line from its timestamp, the labels of its stream and its content.
+// Identical lines in the same stream get a suffix with the number of the previous identical lines.
+func lineID(ts, labelsString, line, refID string, usedIDs map[string]int) string {
+	id := uuid.NewSHA1(lineIDNamespace, []byte(ts+"_"+labelsString+"_"+line)).String()
+
+	if n, ok := usedIDs[id]; ok {
+		usedIDs[id] = n + 1
+		id = fmt.Sprintf("%s_%d", id, n+1)
+	} else {
+		usedIDs[id] = 0
+	}
+
+	if refID != "" {
+		return id + "_" + refID
+	}
+	return id
+}
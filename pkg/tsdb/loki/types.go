@@ -1,9 +1,23 @@
 package loki
 
-import "time"
+import (
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+type QueryType string
+
+const (
+	QueryTypeRange   QueryType = "range"
+	QueryTypeInstant QueryType = "instant"
+)
 
 type lokiQuery struct {
 	Expr         string
+	QueryType    QueryType
+	Direction    logproto.Direction
+	MaxLines     int
 	Step         time.Duration
 	LegendFormat string
 	Start        time.Time
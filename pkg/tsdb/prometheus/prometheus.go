@@ -12,7 +12,9 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
@@ -30,15 +32,18 @@ type Service struct {
 	intervalCalculator intervalv2.Calculator
 	im                 instancemgmt.InstanceManager
 	tracer             tracing.Tracer
+	resourceHandler    backend.CallResourceHandler
 }
 
 func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Tracer) *Service {
 	plog.Debug("initializing")
-	return &Service{
+	s := &Service{
 		intervalCalculator: intervalv2.NewCalculator(),
 		im:                 datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 		tracer:             tracer,
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -60,17 +65,53 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		oauthPassThru, err := maputil.GetBoolOptional(jsonData, "oauthPassThru")
+		if err != nil {
+			return nil, err
+		}
+
+		queryCache, err := newQueryCacheFromSettings(jsonData)
+		if err != nil {
+			return nil, err
+		}
+
 		mdl := DatasourceInfo{
-			ID:           settings.ID,
-			URL:          settings.URL,
-			TimeInterval: timeInterval,
-			getClient:    pc.GetClient,
+			ID:            settings.ID,
+			URL:           settings.URL,
+			TimeInterval:  timeInterval,
+			OAuthPassThru: oauthPassThru,
+			getClient:     pc.GetClient,
+			resourceCache: localcache.New(resourceCacheTTL, 2*resourceCacheTTL),
+			queryCache:    queryCache,
 		}
 
 		return mdl, nil
 	}
 }
 
+// newQueryCacheFromSettings returns the cache of the range queries of a data source, or nil if the data source does
+// not enable incremental querying.
+func newQueryCacheFromSettings(jsonData map[string]interface{}) (*queryCache, error) {
+	incrementalQuerying, err := maputil.GetBoolOptional(jsonData, "incrementalQuerying")
+	if err != nil || !incrementalQuerying {
+		return nil, err
+	}
+
+	overlap := defaultIncrementalQueryOverlap
+	overlapWindow, err := maputil.GetStringOptional(jsonData, "incrementalQueryOverlapWindow")
+	if err != nil {
+		return nil, err
+	}
+	if overlapWindow != "" {
+		overlap, err = intervalv2.ParseIntervalStringToTimeDuration(overlapWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid incremental query overlap window: %w", err)
+		}
+	}
+
+	return newQueryCache(overlap)
+}
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	if len(req.Queries) == 0 {
 		return &backend.QueryDataResponse{}, fmt.Errorf("query contains no queries")
@@ -93,6 +134,10 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return result, err
 }
 
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
 func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*DatasourceInfo, error) {
 	i, err := s.im.Get(pluginCtx)
 	if err != nil {
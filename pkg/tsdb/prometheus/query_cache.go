@@ -0,0 +1,169 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// defaultIncrementalQueryOverlap is how much of the end of the cached samples of a query is requested again, as
+// the most recent samples can still change, for example when targets are scraped late.
+const defaultIncrementalQueryOverlap = 10 * time.Minute
+
+// queryCacheSize is the maximum number of range queries whose samples are cached for a data source.
+const queryCacheSize = 200
+
+// queryCache keeps the samples of the range queries of a data source, so that a query whose time range moves
+// forward, like the queries of a refreshing dashboard, only requests the samples after the cached ones.
+type queryCache struct {
+	overlap time.Duration
+	entries *lru.Cache
+}
+
+// cachedRange is the result of a range query for a time range aligned to its step.
+type cachedRange struct {
+	start  time.Time
+	end    time.Time
+	series model.Matrix
+}
+
+func newQueryCache(overlap time.Duration) (*queryCache, error) {
+	entries, err := lru.New(queryCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &queryCache{
+		overlap: overlap,
+		entries: entries,
+	}, nil
+}
+
+// client returns a client whose range queries use the cache. The headers are part of the keys of the queries, as
+// users with a different OAuth identity can get different results.
+func (c *queryCache) client(client apiv1.API, headers map[string]string) apiv1.API {
+	return &cachingClient{
+		API:        client,
+		cache:      c,
+		headersKey: hashHeaders(headers),
+	}
+}
+
+type cachingClient struct {
+	apiv1.API
+	cache      *queryCache
+	headersKey string
+}
+
+// QueryRange requests only the samples after the cached samples of the query, minus the overlap, and merges them
+// with the cached samples of each series.
+func (c *cachingClient) QueryRange(ctx context.Context, query string, r apiv1.Range) (model.Value, apiv1.Warnings, error) {
+	// The start of the range is aligned to the step with the UTC offset of the query, so queries of the same step
+	// with a different offset return samples at different times and are cached apart.
+	var alignment time.Duration
+	if r.Step > 0 {
+		alignment = time.Duration(r.Start.UnixNano() % int64(r.Step))
+	}
+	key := fmt.Sprintf("%s\n%s\n%s\n%s", query, r.Step, alignment, c.headersKey)
+
+	var cached *cachedRange
+	fetch := r
+	if v, ok := c.cache.entries.Get(key); ok {
+		entry := v.(*cachedRange)
+		if start, ok := entry.incrementalStart(r, c.cache.overlap); ok {
+			cached = entry
+			fetch.Start = start
+		}
+	}
+
+	value, warnings, err := c.API.QueryRange(ctx, query, fetch)
+	if err != nil {
+		return value, warnings, err
+	}
+
+	matrix, ok := value.(model.Matrix)
+	if !ok {
+		c.cache.entries.Remove(key)
+		return value, warnings, nil
+	}
+	if cached != nil {
+		matrix = mergeMatrices(cached.series, matrix, r.Start, fetch.Start)
+	}
+
+	// partial responses are not cached, so that the missing samples are requested again
+	if len(warnings) == 0 {
+		c.cache.entries.Add(key, &cachedRange{start: r.Start, end: r.End, series: matrix})
+	} else {
+		c.cache.entries.Remove(key)
+	}
+	return matrix, warnings, nil
+}
+
+// incrementalStart returns the start of the range to request for the given range. It is false if the cached range
+// does not cover the start of the given range, ends after it, or if the whole range would be requested anyway.
+func (e *cachedRange) incrementalStart(r apiv1.Range, overlap time.Duration) (time.Time, bool) {
+	if r.Step <= 0 || r.Start.Before(e.start) || e.end.Before(r.Start) || r.End.Before(e.end) {
+		return time.Time{}, false
+	}
+
+	start := e.end.Add(-overlap)
+	if !start.After(r.Start) {
+		return time.Time{}, false
+	}
+
+	// the start must be aligned to the step of the range, so that the fetched samples have the same timestamps as
+	// the samples of a query of the whole range
+	steps := start.Sub(r.Start) / r.Step
+	return r.Start.Add(steps * r.Step), true
+}
+
+// mergeMatrices returns the series of the range from start, with the cached samples before the start of the
+// fetched range and the fetched samples. Series are identified by their label set, and the series that are no
+// longer returned by Prometheus are kept while they have samples in the range.
+func mergeMatrices(cached, fetched model.Matrix, start, fetchStart time.Time) model.Matrix {
+	from := model.TimeFromUnixNano(start.UnixNano())
+	until := model.TimeFromUnixNano(fetchStart.UnixNano())
+
+	cachedSeries := make(map[model.Fingerprint]*model.SampleStream, len(cached))
+	for _, s := range cached {
+		cachedSeries[s.Metric.Fingerprint()] = s
+	}
+
+	merged := make(model.Matrix, 0, len(fetched)+len(cached))
+	seen := make(map[model.Fingerprint]bool, len(fetched))
+	for _, s := range fetched {
+		fp := s.Metric.Fingerprint()
+		seen[fp] = true
+		values := samplesBetween(cachedSeries[fp], from, until)
+		merged = append(merged, &model.SampleStream{Metric: s.Metric, Values: append(values, s.Values...)})
+	}
+	for _, s := range cached {
+		if seen[s.Metric.Fingerprint()] {
+			continue
+		}
+		if values := samplesBetween(s, from, until); len(values) > 0 {
+			merged = append(merged, &model.SampleStream{Metric: s.Metric, Values: values})
+		}
+	}
+
+	return merged
+}
+
+// samplesBetween returns a copy of the samples of the series from the given time until the other one, excluded.
+func samplesBetween(s *model.SampleStream, from, until model.Time) []model.SamplePair {
+	if s == nil {
+		return nil
+	}
+
+	values := make([]model.SamplePair, 0, len(s.Values))
+	for _, v := range s.Values {
+		if v.Timestamp >= from && v.Timestamp < until {
+			values = append(values, v)
+		}
+	}
+	return values
+}
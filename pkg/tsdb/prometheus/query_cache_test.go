@@ -0,0 +1,158 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRangeClient struct {
+	apiv1.API
+	ranges   []apiv1.Range
+	series   []model.Metric
+	warnings apiv1.Warnings
+}
+
+// QueryRange returns a sample for each step of the range for each series, whose value is the time in seconds.
+func (c *fakeRangeClient) QueryRange(_ context.Context, _ string, r apiv1.Range) (model.Value, apiv1.Warnings, error) {
+	c.ranges = append(c.ranges, r)
+	matrix := model.Matrix{}
+	for _, metric := range c.series {
+		matrix = append(matrix, &model.SampleStream{Metric: metric, Values: samples(r)})
+	}
+	return matrix, c.warnings, nil
+}
+
+func samples(r apiv1.Range) []model.SamplePair {
+	values := []model.SamplePair{}
+	for t := r.Start; !t.After(r.End); t = t.Add(r.Step) {
+		values = append(values, model.SamplePair{Timestamp: model.TimeFromUnixNano(t.UnixNano()), Value: model.SampleValue(t.Unix())})
+	}
+	return values
+}
+
+func TestQueryCache(t *testing.T) {
+	seriesA := model.Metric{"job": "a"}
+	seriesB := model.Metric{"job": "b"}
+	at := func(minutes int) time.Time {
+		return time.Unix(1600000000, 0).Truncate(time.Minute).Add(time.Duration(minutes) * time.Minute)
+	}
+	rangeOf := func(from, to int) apiv1.Range {
+		return apiv1.Range{Start: at(from), End: at(to), Step: time.Minute}
+	}
+
+	setup := func() (*queryCache, *fakeRangeClient) {
+		cache, err := newQueryCache(2 * time.Minute)
+		require.NoError(t, err)
+		return cache, &fakeRangeClient{series: []model.Metric{seriesA, seriesB}}
+	}
+
+	t.Run("requests only the samples after the cached ones", func(t *testing.T) {
+		cache, fake := setup()
+		client := cache.client(fake, nil)
+
+		_, _, err := client.QueryRange(context.Background(), "up", rangeOf(0, 10))
+		require.NoError(t, err)
+
+		// series b is no longer returned by Prometheus
+		fake.series = []model.Metric{seriesA}
+		value, _, err := client.QueryRange(context.Background(), "up", rangeOf(3, 13))
+		require.NoError(t, err)
+
+		require.Equal(t, []apiv1.Range{rangeOf(0, 10), rangeOf(8, 13)}, fake.ranges)
+		require.Equal(t, model.Matrix{
+			{Metric: seriesA, Values: samples(rangeOf(3, 13))},
+			{Metric: seriesB, Values: samples(rangeOf(3, 7))},
+		}, value)
+
+		// the merged samples are cached
+		_, _, err = client.QueryRange(context.Background(), "up", rangeOf(4, 14))
+		require.NoError(t, err)
+		require.Equal(t, rangeOf(11, 14), fake.ranges[2])
+	})
+
+	t.Run("requests the whole range if the cached samples cannot be used", func(t *testing.T) {
+		testCases := map[string]apiv1.Range{
+			"range starting before the cached range": rangeOf(-1, 12),
+			"range starting after the cached range":  rangeOf(11, 20),
+			"range ending before the cached range":   rangeOf(1, 9),
+			"range within the overlap":               rangeOf(9, 11),
+			"different step":                         {Start: at(1), End: at(11), Step: 2 * time.Minute},
+		}
+		for name, r := range testCases {
+			t.Run(name, func(t *testing.T) {
+				cache, fake := setup()
+				client := cache.client(fake, nil)
+
+				_, _, err := client.QueryRange(context.Background(), "up", rangeOf(0, 10))
+				require.NoError(t, err)
+				_, _, err = client.QueryRange(context.Background(), "up", r)
+				require.NoError(t, err)
+				require.Equal(t, r, fake.ranges[1])
+			})
+		}
+	})
+
+	t.Run("keeps the queries of different users and expressions apart", func(t *testing.T) {
+		cache, fake := setup()
+
+		_, _, err := cache.client(fake, map[string]string{"Authorization": "Bearer a"}).QueryRange(context.Background(), "up", rangeOf(0, 10))
+		require.NoError(t, err)
+		_, _, err = cache.client(fake, map[string]string{"Authorization": "Bearer b"}).QueryRange(context.Background(), "up", rangeOf(1, 11))
+		require.NoError(t, err)
+		_, _, err = cache.client(fake, map[string]string{"Authorization": "Bearer a"}).QueryRange(context.Background(), "go_goroutines", rangeOf(1, 11))
+		require.NoError(t, err)
+
+		require.Equal(t, []apiv1.Range{rangeOf(0, 10), rangeOf(1, 11), rangeOf(1, 11)}, fake.ranges)
+	})
+
+	t.Run("keeps the queries aligned with a different offset apart", func(t *testing.T) {
+		cache, fake := setup()
+		client := cache.client(fake, nil)
+
+		_, _, err := client.QueryRange(context.Background(), "up", rangeOf(0, 10))
+		require.NoError(t, err)
+		// the range of a query aligned to the step with a UTC offset of 30 seconds
+		shifted := apiv1.Range{Start: at(1).Add(30 * time.Second), End: at(11).Add(30 * time.Second), Step: time.Minute}
+		value, _, err := client.QueryRange(context.Background(), "up", shifted)
+		require.NoError(t, err)
+
+		require.Equal(t, shifted, fake.ranges[1])
+		require.Equal(t, samples(shifted), value.(model.Matrix)[0].Values)
+	})
+
+	t.Run("does not cache partial responses", func(t *testing.T) {
+		cache, fake := setup()
+		client := cache.client(fake, nil)
+
+		fake.warnings = apiv1.Warnings{"partial response"}
+		_, _, err := client.QueryRange(context.Background(), "up", rangeOf(0, 10))
+		require.NoError(t, err)
+		fake.warnings = nil
+		_, _, err = client.QueryRange(context.Background(), "up", rangeOf(1, 11))
+		require.NoError(t, err)
+
+		require.Equal(t, rangeOf(1, 11), fake.ranges[1])
+	})
+}
+
+func TestNewQueryCacheFromSettings(t *testing.T) {
+	cache, err := newQueryCacheFromSettings(map[string]interface{}{})
+	require.NoError(t, err)
+	require.Nil(t, cache)
+
+	cache, err = newQueryCacheFromSettings(map[string]interface{}{"incrementalQuerying": true})
+	require.NoError(t, err)
+	require.Equal(t, defaultIncrementalQueryOverlap, cache.overlap)
+
+	cache, err = newQueryCacheFromSettings(map[string]interface{}{"incrementalQuerying": true, "incrementalQueryOverlapWindow": "30m"})
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, cache.overlap)
+
+	_, err = newQueryCacheFromSettings(map[string]interface{}{"incrementalQuerying": true, "incrementalQueryOverlapWindow": "later"})
+	require.Error(t, err)
+}
@@ -0,0 +1,250 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// resourceCacheTTL is how long the responses of the metadata resources are cached. It is short so that new metrics
+// and labels show up quickly, but saves the repeated requests of the query editor.
+const resourceCacheTTL = 30 * time.Second
+
+// oauthHeaders are the headers of the resource requests forwarded to Prometheus with Forward OAuth Identity.
+var oauthHeaders = []string{"Authorization", "X-ID-Token"}
+
+var errInvalidResourceRequest = errors.New("invalid request")
+
+// resourceFunc requests a metadata resource from Prometheus with the parameters of a resource request.
+type resourceFunc func(ctx context.Context, client apiv1.API, req *http.Request) (interface{}, apiv1.Warnings, error)
+
+// resourceResponse is the envelope of the responses of the Prometheus API, so that the resources can be used in
+// place of the same requests through the data source proxy.
+type resourceResponse struct {
+	Status    string         `json:"status"`
+	Data      interface{}    `json:"data,omitempty"`
+	Warnings  apiv1.Warnings `json:"warnings,omitempty"`
+	ErrorType string         `json:"errorType,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/labels", s.handleResourceReq(labelNames))
+	mux.HandleFunc("/label/", s.handleResourceReq(labelValues))
+	mux.HandleFunc("/series", s.handleResourceReq(series))
+	mux.HandleFunc("/metadata", s.handleResourceReq(metadata))
+	return mux
+}
+
+func (s *Service) handleResourceReq(fn resourceFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		plog.Debug("Received resource call", "url", req.URL.String(), "method", req.Method)
+
+		if req.Method != http.MethodGet && req.Method != http.MethodPost {
+			writeResourceError(rw, http.StatusMethodNotAllowed, "bad_data", fmt.Errorf("method %s is not allowed", req.Method))
+			return
+		}
+		if err := req.ParseForm(); err != nil {
+			writeResourceError(rw, http.StatusBadRequest, "bad_data", err)
+			return
+		}
+
+		dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, "internal", err)
+			return
+		}
+
+		headers := resourceHeaders(req, dsInfo)
+		key := resourceCacheKey(req, headers)
+		if body, ok := dsInfo.resourceCache.Get(key); ok {
+			writeResourceBody(rw, http.StatusOK, body.([]byte))
+			return
+		}
+
+		client, err := dsInfo.getClient(headers)
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, "internal", err)
+			return
+		}
+
+		data, warnings, err := fn(req.Context(), client, req)
+		if err != nil {
+			status, errorType := resourceErrorStatus(err)
+			if status != http.StatusBadRequest {
+				plog.Error("Resource request failed", "path", req.URL.Path, "err", err)
+			}
+			writeResourceError(rw, status, errorType, ConvertAPIError(err))
+			return
+		}
+
+		body, err := json.Marshal(resourceResponse{Status: "success", Data: data, Warnings: warnings})
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, "internal", err)
+			return
+		}
+		dsInfo.resourceCache.SetDefault(key, body)
+		writeResourceBody(rw, http.StatusOK, body)
+	}
+}
+
+func labelNames(ctx context.Context, client apiv1.API, req *http.Request) (interface{}, apiv1.Warnings, error) {
+	start, end, err := parseResourceTimeRange(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.LabelNames(ctx, req.Form["match[]"], start, end)
+}
+
+func labelValues(ctx context.Context, client apiv1.API, req *http.Request) (interface{}, apiv1.Warnings, error) {
+	name := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/label/"), "/values")
+	if name == "" || strings.Contains(name, "/") || !strings.HasSuffix(req.URL.Path, "/values") {
+		return nil, nil, fmt.Errorf("%w: unknown resource %s", errInvalidResourceRequest, req.URL.Path)
+	}
+	start, end, err := parseResourceTimeRange(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.LabelValues(ctx, name, req.Form["match[]"], start, end)
+}
+
+func series(ctx context.Context, client apiv1.API, req *http.Request) (interface{}, apiv1.Warnings, error) {
+	matches := req.Form["match[]"]
+	if len(matches) == 0 {
+		return nil, nil, fmt.Errorf("%w: no match[] parameter provided", errInvalidResourceRequest)
+	}
+	start, end, err := parseResourceTimeRange(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.Series(ctx, matches, start, end)
+}
+
+func metadata(ctx context.Context, client apiv1.API, req *http.Request) (interface{}, apiv1.Warnings, error) {
+	limit := req.Form.Get("limit")
+	if limit != "" {
+		if _, err := strconv.Atoi(limit); err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid limit %q", errInvalidResourceRequest, limit)
+		}
+	}
+	result, err := client.Metadata(ctx, req.Form.Get("metric"), limit)
+	return result, nil, err
+}
+
+// parseResourceTimeRange returns the time range of a resource request. The times are zero if the request has no
+// time range, so that Prometheus uses its default time range.
+func parseResourceTimeRange(req *http.Request) (time.Time, time.Time, error) {
+	start, err := parseResourceTime(req.Form.Get("start"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := parseResourceTime(req.Form.Get("end"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: end timestamp must not be before start time", errInvalidResourceRequest)
+	}
+	return start, end, nil
+}
+
+// parseResourceTime parses a time in the formats of the Prometheus API, a Unix timestamp in seconds or RFC 3339.
+func parseResourceTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(t)
+		return time.Unix(int64(sec), int64(math.Round(frac*float64(time.Second)))).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: cannot parse %q to a valid timestamp", errInvalidResourceRequest, s)
+}
+
+// resourceHeaders returns the headers of the resource request to send to Prometheus. The headers are the ones of the
+// request to Grafana, so they are only forwarded if the data source forwards the OAuth identity of the users.
+func resourceHeaders(req *http.Request, dsInfo *DatasourceInfo) map[string]string {
+	headers := map[string]string{}
+	if !dsInfo.OAuthPassThru {
+		return headers
+	}
+	for _, name := range oauthHeaders {
+		if v := req.Header.Get(name); v != "" {
+			headers[name] = v
+		}
+	}
+	return headers
+}
+
+// resourceCacheKey returns the key of the response of a resource request in the cache. The headers are part of the
+// key because users with a different OAuth identity can get different responses.
+func resourceCacheKey(req *http.Request, headers map[string]string) string {
+	return req.URL.Path + "?" + req.Form.Encode() + "#" + hashHeaders(headers)
+}
+
+// hashHeaders returns a hash of the headers sent to Prometheus, to use in cache keys without keeping the tokens of
+// the users in memory.
+func hashHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		_, _ = fmt.Fprintf(h, "%s: %s\n", name, headers[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func resourceErrorStatus(err error) (int, string) {
+	if errors.Is(err, errInvalidResourceRequest) {
+		return http.StatusBadRequest, string(apiv1.ErrBadData)
+	}
+	var apiErr *apiv1.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Type {
+		case apiv1.ErrBadData:
+			return http.StatusBadRequest, string(apiErr.Type)
+		case apiv1.ErrTimeout:
+			return http.StatusGatewayTimeout, string(apiErr.Type)
+		default:
+			return http.StatusBadGateway, string(apiErr.Type)
+		}
+	}
+	return http.StatusBadGateway, "unavailable"
+}
+
+func writeResourceError(rw http.ResponseWriter, status int, errorType string, err error) {
+	body, marshalErr := json.Marshal(resourceResponse{Status: "error", ErrorType: errorType, Error: err.Error()})
+	if marshalErr != nil {
+		plog.Error("Failed to marshal the error of a resource request", "err", marshalErr)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeResourceBody(rw, status, body)
+}
+
+func writeResourceBody(rw http.ResponseWriter, status int, body []byte) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	if _, err := rw.Write(body); err != nil {
+		plog.Error("Failed to write the response of a resource request", "err", err)
+	}
+}
@@ -0,0 +1,227 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResourceClient struct {
+	apiv1.API
+	calls   int
+	label   string
+	matches []string
+	start   time.Time
+	end     time.Time
+	err     error
+}
+
+func (c *fakeResourceClient) LabelNames(_ context.Context, matches []string, start, end time.Time) ([]string, apiv1.Warnings, error) {
+	c.calls++
+	c.matches, c.start, c.end = matches, start, end
+	return []string{"__name__", "job"}, nil, c.err
+}
+
+func (c *fakeResourceClient) LabelValues(_ context.Context, label string, matches []string, start, end time.Time) (model.LabelValues, apiv1.Warnings, error) {
+	c.calls++
+	c.label, c.matches, c.start, c.end = label, matches, start, end
+	return model.LabelValues{"grafana", "prometheus"}, apiv1.Warnings{"partial response"}, c.err
+}
+
+func (c *fakeResourceClient) Series(_ context.Context, matches []string, start, end time.Time) ([]model.LabelSet, apiv1.Warnings, error) {
+	c.calls++
+	c.matches, c.start, c.end = matches, start, end
+	return []model.LabelSet{{"__name__": "up", "job": "grafana"}}, nil, c.err
+}
+
+func (c *fakeResourceClient) Metadata(_ context.Context, metric string, _ string) (map[string][]apiv1.Metadata, error) {
+	c.calls++
+	c.label = metric
+	return map[string][]apiv1.Metadata{"up": {{Type: apiv1.MetricTypeGauge, Help: "up", Unit: ""}}}, c.err
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	if s.resp == nil {
+		s.resp = resp
+		return nil
+	}
+	s.resp.Body = append(s.resp.Body, resp.Body...)
+	return nil
+}
+
+type resourceTestContext struct {
+	service *Service
+	client  *fakeResourceClient
+	headers map[string]string
+	pCtx    backend.PluginContext
+}
+
+func setupResourceTest(oauthPassThru bool) *resourceTestContext {
+	tc := &resourceTestContext{client: &fakeResourceClient{}}
+	tc.service = &Service{
+		im: datasource.NewInstanceManager(func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return DatasourceInfo{
+				ID:            settings.ID,
+				OAuthPassThru: oauthPassThru,
+				getClient: func(headers map[string]string) (apiv1.API, error) {
+					tc.headers = headers
+					return tc.client, nil
+				},
+				resourceCache: localcache.New(resourceCacheTTL, 2*resourceCacheTTL),
+			}, nil
+		}),
+	}
+	tc.service.resourceHandler = httpadapter.New(tc.service.newResourceMux())
+	tc.pCtx = backend.PluginContext{
+		OrgID:                      1,
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, UID: "prometheus"},
+	}
+	return tc
+}
+
+func (tc *resourceTestContext) call(t *testing.T, method, rawURL string, headers map[string][]string) (int, map[string]interface{}) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+
+	req := &backend.CallResourceRequest{
+		PluginContext: tc.pCtx,
+		Path:          strings.TrimPrefix(u.Path, "/"),
+		Method:        method,
+		URL:           rawURL,
+		Headers:       headers,
+	}
+	// the parameters of POST requests are sent in the body, as the frontend does
+	if method == http.MethodPost {
+		req.URL = u.Path
+		req.Body = []byte(u.RawQuery)
+		req.Headers = map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}}
+		for k, v := range headers {
+			req.Headers[k] = v
+		}
+	}
+
+	sender := &fakeSender{}
+	err = tc.service.CallResource(context.Background(), req, sender)
+	require.NoError(t, err)
+	resp := sender.resp
+	require.NotNil(t, resp)
+
+	body := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(resp.Body, &body))
+	return resp.Status, body
+}
+
+func TestResourceHandler(t *testing.T) {
+	t.Run("returns the label names", func(t *testing.T) {
+		tc := setupResourceTest(false)
+		status, body := tc.call(t, http.MethodGet, "/labels?start=1600000000&end=1600003600.5&match[]=up", nil)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "success", body["status"])
+		require.Equal(t, []interface{}{"__name__", "job"}, body["data"])
+		require.Equal(t, []string{"up"}, tc.client.matches)
+		require.Equal(t, time.Unix(1600000000, 0).UTC(), tc.client.start)
+		require.Equal(t, time.Unix(1600003600, int64(500*time.Millisecond)).UTC(), tc.client.end)
+	})
+
+	t.Run("returns the values of a label with the warnings", func(t *testing.T) {
+		tc := setupResourceTest(false)
+		status, body := tc.call(t, http.MethodGet, "/label/job/values", nil)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "job", tc.client.label)
+		require.Equal(t, []interface{}{"grafana", "prometheus"}, body["data"])
+		require.Equal(t, []interface{}{"partial response"}, body["warnings"])
+	})
+
+	t.Run("returns the series", func(t *testing.T) {
+		tc := setupResourceTest(false)
+		status, body := tc.call(t, http.MethodPost, "/series?match[]=up&match[]=go_goroutines", nil)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{"up", "go_goroutines"}, tc.client.matches)
+		require.Equal(t, []interface{}{map[string]interface{}{"__name__": "up", "job": "grafana"}}, body["data"])
+	})
+
+	t.Run("returns the metadata of the metrics", func(t *testing.T) {
+		tc := setupResourceTest(false)
+		status, body := tc.call(t, http.MethodGet, "/metadata?metric=up", nil)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "up", tc.client.label)
+		require.Contains(t, body["data"], "up")
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		tc := setupResourceTest(false)
+		for _, u := range []string{
+			"/series",
+			"/labels?start=yesterday",
+			"/labels?start=1600003600&end=1600000000",
+			"/label/job",
+			"/metadata?limit=ten",
+		} {
+			status, body := tc.call(t, http.MethodGet, u, nil)
+			require.Equal(t, http.StatusBadRequest, status, u)
+			require.Equal(t, "error", body["status"], u)
+		}
+		require.Equal(t, 0, tc.client.calls)
+	})
+
+	t.Run("returns the errors of Prometheus", func(t *testing.T) {
+		tc := setupResourceTest(false)
+		tc.client.err = &apiv1.Error{Type: apiv1.ErrServer, Msg: "server error", Detail: "storage unavailable"}
+		status, body := tc.call(t, http.MethodGet, "/labels", nil)
+		require.Equal(t, http.StatusBadGateway, status)
+		require.Equal(t, "server_error", body["errorType"])
+		require.Equal(t, "server error: storage unavailable", body["error"])
+
+		// errors are not cached
+		tc.call(t, http.MethodGet, "/labels", nil)
+		require.Equal(t, 2, tc.client.calls)
+	})
+
+	t.Run("caches the responses", func(t *testing.T) {
+		tc := setupResourceTest(false)
+		tc.call(t, http.MethodGet, "/labels?match[]=up", nil)
+		tc.call(t, http.MethodGet, "/labels?match[]=up", nil)
+		require.Equal(t, 1, tc.client.calls)
+
+		tc.call(t, http.MethodGet, "/labels?match[]=go_goroutines", nil)
+		require.Equal(t, 2, tc.client.calls)
+	})
+
+	t.Run("forwards the OAuth identity only with Forward OAuth Identity", func(t *testing.T) {
+		headers := map[string][]string{
+			"Authorization": {"Bearer token"},
+			"X-Id-Token":    {"id-token"},
+			"Cookie":        {"grafana_session=abc"},
+		}
+
+		tc := setupResourceTest(false)
+		tc.call(t, http.MethodGet, "/labels", headers)
+		require.Empty(t, tc.headers)
+
+		tc = setupResourceTest(true)
+		tc.call(t, http.MethodGet, "/labels", headers)
+		require.Equal(t, map[string]string{"Authorization": "Bearer token", "X-ID-Token": "id-token"}, tc.headers)
+
+		// users with another identity do not get the cached response
+		tc.call(t, http.MethodGet, "/labels", map[string][]string{"Authorization": {"Bearer other-token"}})
+		require.Equal(t, 2, tc.client.calls)
+	})
+}
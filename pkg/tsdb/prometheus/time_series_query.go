@@ -119,6 +119,9 @@ func (s *Service) executeTimeSeriesQuery(ctx context.Context, req *backend.Query
 	if err != nil {
 		return nil, err
 	}
+	if dsInfo.queryCache != nil {
+		client = dsInfo.queryCache.client(client, req.Headers)
+	}
 
 	queries, err := s.parseTimeSeriesQuery(req, dsInfo)
 	if err != nil {
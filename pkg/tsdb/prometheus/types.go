@@ -3,6 +3,7 @@ package prometheus
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
@@ -10,8 +11,14 @@ type DatasourceInfo struct {
 	ID           int64
 	URL          string
 	TimeInterval string
+	// OAuthPassThru is true if the data source forwards the OAuth identity of the users to Prometheus.
+	OAuthPassThru bool
 
 	getClient clientGetter
+	// resourceCache caches the responses of the metadata resources of the data source.
+	resourceCache *localcache.CacheService
+	// queryCache caches the samples of the range queries if incremental querying is enabled, otherwise it is nil.
+	queryCache *queryCache
 }
 
 type clientGetter func(map[string]string) (apiv1.API, error)
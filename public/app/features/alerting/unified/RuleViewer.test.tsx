@@ -10,9 +10,11 @@ import { useCombinedRule } from './hooks/useCombinedRule';
 import { GrafanaRouteComponentProps } from 'app/core/navigation/types';
 import { GRAFANA_RULES_SOURCE_NAME } from './utils/datasource';
 import { CombinedRule } from 'app/types/unified-alerting';
-import { GrafanaAlertStateDecision } from 'app/types/unified-alerting-dto';
+import { GrafanaAlertState, GrafanaAlertStateDecision } from 'app/types/unified-alerting-dto';
+import { fetchGrafanaRuleStateHistory } from './api/ruler';
 
 jest.mock('./hooks/useCombinedRule');
+jest.mock('./api/ruler');
 jest.mock('@grafana/runtime', () => ({
   ...(jest.requireActual('@grafana/runtime') as any),
   getDataSourceSrv: () => {
@@ -57,6 +59,48 @@ describe('RuleViewer', () => {
     expect(screen.getByText('Test alert')).toBeInTheDocument();
   });
 
+  it('should render the state history of the instances of a grafana alert', async () => {
+    jest.mocked(useCombinedRule).mockReturnValue({
+      result: mockGrafanaRule as CombinedRule,
+      loading: false,
+      dispatched: true,
+      requestId: 'A',
+      error: undefined,
+    });
+    jest.mocked(fetchGrafanaRuleStateHistory).mockResolvedValue([
+      {
+        labels: { instance: 'web01' },
+        history: [
+          {
+            previousState: GrafanaAlertState.Normal,
+            state: GrafanaAlertState.Error,
+            error: 'query timed out',
+            timestamp: '2022-01-01T10:00:00Z',
+          },
+        ],
+      },
+      {
+        labels: { instance: 'web02' },
+        history: [
+          {
+            previousState: GrafanaAlertState.Normal,
+            state: GrafanaAlertState.Pending,
+            values: { B: '42' },
+            timestamp: '2022-01-01T09:00:00Z',
+          },
+        ],
+      },
+    ]);
+    await renderRuleViewer();
+
+    expect(fetchGrafanaRuleStateHistory).toHaveBeenCalledWith('asdf23', []);
+    expect(screen.getByText('State history')).toBeInTheDocument();
+    expect(screen.getByText('instance=web01')).toBeInTheDocument();
+    expect(screen.getByText('instance=web02')).toBeInTheDocument();
+    expect(screen.getByText('query timed out')).toBeInTheDocument();
+    expect(screen.getByText('B=42')).toBeInTheDocument();
+  });
+
   it('should render page with cloud alert', async () => {
     jest.mocked(useCombinedRule).mockReturnValue({
       result: mockCloudRule as CombinedRule,
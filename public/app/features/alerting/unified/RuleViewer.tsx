@@ -21,6 +21,7 @@ import { RuleHealth } from './components/rules/RuleHealth';
 import { RuleViewerVisualization } from './components/rule-viewer/RuleViewerVisualization';
 import { RuleDetailsActionButtons } from './components/rules/RuleDetailsActionButtons';
 import { RuleDetailsMatchingInstances } from './components/rules/RuleDetailsMatchingInstances';
+import { RuleDetailsStateHistory } from './components/rules/RuleDetailsStateHistory';
 import { RuleDetailsDataSources } from './components/rules/RuleDetailsDataSources';
 import { RuleViewerLayout, RuleViewerLayoutContent } from './components/rule-viewer/RuleViewerLayout';
 import { AlertLabels } from './components/AlertLabels';
@@ -149,6 +150,9 @@ export function RuleViewer({ match }: RuleViewerProps) {
         <div>
           <RuleDetailsMatchingInstances promRule={rule.promRule} />
         </div>
+        <div>
+          <RuleDetailsStateHistory rule={rule} />
+        </div>
       </RuleViewerLayoutContent>
       {data && Object.keys(data).length > 0 && (
         <>
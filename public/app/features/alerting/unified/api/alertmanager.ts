@@ -259,6 +259,6 @@ export async function fetchExternalAlertmanagerConfig(): Promise<{ alertmanagers
   return result.data;
 }
 
-function escapeQuotes(value: string): string {
+export function escapeQuotes(value: string): string {
   return value.replace(/"/g, '\\"');
 }
@@ -1,9 +1,17 @@
 import { lastValueFrom } from 'rxjs';
 import { FetchResponse, getBackendSrv } from '@grafana/runtime';
 
-import { PostableRulerRuleGroupDTO, RulerRuleGroupDTO, RulerRulesConfigDTO } from 'app/types/unified-alerting-dto';
+import {
+  InstanceStateHistoryDTO,
+  Labels,
+  PostableRulerRuleGroupDTO,
+  RuleStateHistoryDTO,
+  RulerRuleGroupDTO,
+  RulerRulesConfigDTO,
+} from 'app/types/unified-alerting-dto';
 import { getDatasourceAPIId, GRAFANA_RULES_SOURCE_NAME } from '../utils/datasource';
 import { RULER_NOT_SUPPORTED_MSG } from '../utils/constants';
+import { escapeQuotes } from './alertmanager';
 
 interface ErrorResponseMessage {
   message?: string;
@@ -154,3 +162,40 @@ export async function deleteNamespace(dataSourceName: string, namespace: string)
     })
   );
 }
+
+// fetch the state history of the alert instances of a Grafana managed rule. If label sets are given, the history of
+// the instance with each label set is fetched, otherwise the history of all the instances of the rule.
+export async function fetchGrafanaRuleStateHistory(
+  ruleUID: string,
+  labelSets: Labels[] = []
+): Promise<InstanceStateHistoryDTO[]> {
+  const matcherSets = labelSets.length ? labelSets.map(labelsToMatchers) : [[]];
+  const responses = await Promise.all(
+    matcherSets.map((matchers) =>
+      lastValueFrom(
+        getBackendSrv().fetch<RuleStateHistoryDTO>({
+          url: `/api/ruler/grafana/api/v1/rule/${encodeURIComponent(ruleUID)}/history`,
+          params: { matcher: matchers },
+          showErrorAlert: false,
+          showSuccessAlert: false,
+        })
+      )
+    )
+  );
+
+  // an instance is returned once for every label set it matches
+  const instances = new Map<string, InstanceStateHistoryDTO>();
+  responses
+    .flatMap((response) => response.data.instances)
+    .forEach((instance) => instances.set(labelsToMatchers(instance.labels).sort().join(','), instance));
+
+  return Array.from(instances.values()).sort((a, b) => lastChange(b.history) - lastChange(a.history));
+}
+
+function labelsToMatchers(labels: Labels): string[] {
+  return Object.entries(labels).map(([name, value]) => `${name}="${escapeQuotes(value)}"`);
+}
+
+function lastChange(history: InstanceStateHistoryDTO['history']): number {
+  return history.length ? new Date(history[0].timestamp).getTime() : 0;
+}
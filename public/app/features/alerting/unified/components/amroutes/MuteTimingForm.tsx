@@ -16,9 +16,10 @@ import { updateAlertManagerConfigAction } from '../../state/actions';
 import { useUnifiedAlertingSelector } from '../../hooks/useUnifiedAlertingSelector';
 import { initialAsyncRequestState } from '../../utils/redux';
 import { MuteTimingFields } from '../../types/mute-timing-form';
-import { createMuteTiming, defaultTimeInterval } from '../../utils/mute-timings';
+import { createMuteTiming, dateRangeToString, defaultTimeInterval } from '../../utils/mute-timings';
 import { makeAMLink } from '../../utils/misc';
 import { renameMuteTimings } from '../../utils/alertmanager';
+import { GRAFANA_RULES_SOURCE_NAME } from '../../utils/datasource';
 import { MuteTimingTimeInterval } from './MuteTimingTimeInterval';
 
 interface Props {
@@ -43,6 +44,8 @@ const useDefaultValues = (muteTiming?: MuteTimeInterval): MuteTimingFields => {
       days_of_month: interval?.days_of_month?.join(', ') ?? defaultTimeInterval.days_of_month,
       months: interval?.months?.join(', ') ?? defaultTimeInterval.months,
       years: interval?.years?.join(', ') ?? defaultTimeInterval.years,
+      location: interval?.location ?? defaultTimeInterval.location,
+      date_ranges: interval?.date_ranges?.map(dateRangeToString).join(', ') ?? defaultTimeInterval.date_ranges,
     }));
 
     return {
@@ -126,7 +129,7 @@ const MuteTimingForm = ({ muteTiming, showError }: Props) => {
                   data-testid={'mute-timing-name'}
                 />
               </Field>
-              <MuteTimingTimeInterval />
+              <MuteTimingTimeInterval isGrafanaAlertmanager={alertManagerSourceName === GRAFANA_RULES_SOURCE_NAME} />
               <LinkButton
                 type="button"
                 variant="secondary"
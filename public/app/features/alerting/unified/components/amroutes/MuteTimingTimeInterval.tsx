@@ -3,11 +3,22 @@ import { GrafanaTheme2 } from '@grafana/data';
 import { Button, Input, Field, FieldSet, useStyles2 } from '@grafana/ui';
 import { css } from '@emotion/css';
 import { useFormContext, useFieldArray } from 'react-hook-form';
-import { DAYS_OF_THE_WEEK, MONTHS, validateArrayField, defaultTimeInterval } from '../../utils/mute-timings';
+import {
+  DAYS_OF_THE_WEEK,
+  MONTHS,
+  validateArrayField,
+  defaultTimeInterval,
+  isValidDate,
+} from '../../utils/mute-timings';
 import { MuteTimingFields } from '../../types/mute-timing-form';
 import { MuteTimingTimeRange } from './MuteTimingTimeRange';
 
-export const MuteTimingTimeInterval = () => {
+interface Props {
+  // time zones and date ranges are only supported by the Grafana Alertmanager
+  isGrafanaAlertmanager?: boolean;
+}
+
+export const MuteTimingTimeInterval = ({ isGrafanaAlertmanager }: Props) => {
   const styles = useStyles2(getStyles);
   const { formState, register } = useFormContext();
   const {
@@ -116,6 +127,42 @@ export const MuteTimingTimeInterval = () => {
                   data-testid="mute-timing-years"
                 />
               </Field>
+              {isGrafanaAlertmanager && (
+                <>
+                  <Field
+                    label="Dates"
+                    description="Dates in the format YYYY-MM-DD, or inclusive ranges of dates"
+                    invalid={!!errors.time_intervals?.[timeIntervalIndex]?.date_ranges}
+                    error={errors.time_intervals?.[timeIntervalIndex]?.date_ranges?.message ?? ''}
+                  >
+                    <Input
+                      {...register(`time_intervals.${timeIntervalIndex}.date_ranges`, {
+                        validate: (value) => validateArrayField(value, isValidDate, 'Invalid date'),
+                      })}
+                      className={styles.input}
+                      placeholder="Example: 2022-12-24:2022-12-26, 2023-01-01"
+                      // @ts-ignore react-hook-form doesn't handle nested field arrays well
+                      defaultValue={timeInterval.date_ranges}
+                      data-testid="mute-timing-date-ranges"
+                    />
+                  </Field>
+                  <Field
+                    label="Location"
+                    description="The time zone in which the time interval is evaluated, UTC if left blank"
+                    invalid={!!errors.time_intervals?.[timeIntervalIndex]?.location}
+                    error={errors.time_intervals?.[timeIntervalIndex]?.location?.message ?? ''}
+                  >
+                    <Input
+                      {...register(`time_intervals.${timeIntervalIndex}.location`)}
+                      className={styles.input}
+                      placeholder="Example: Europe/Berlin"
+                      // @ts-ignore react-hook-form doesn't handle nested field arrays well
+                      defaultValue={timeInterval.location}
+                      data-testid="mute-timing-location"
+                    />
+                  </Field>
+                </>
+              )}
               <Button
                 type="button"
                 variant="destructive"
@@ -41,9 +41,9 @@ export const MuteTimingTimeRange: FC<Props> = ({ intervalIndex }) => {
       <Field
         className={styles.field}
         label="Time range"
-        description="The time inclusive of the starting time and exclusive of the end time in UTC"
+        description="The time inclusive of the starting time and exclusive of the end time, in UTC unless the time interval has a location"
         invalid={timeRangeInvalid}
-        error={timeRangeInvalid ? 'Times must be between 00:00 and 24:00 UTC' : ''}
+        error={timeRangeInvalid ? 'Times must be between 00:00 and 24:00' : ''}
       >
         <>
           {timeRanges.map((timeRange, index) => {
@@ -15,6 +15,7 @@ import {
   getDaysOfMonthString,
   getMonthsString,
   getYearsString,
+  getDateRangesString,
 } from '../../utils/alertmanager';
 import { EmptyAreaWithCTA } from '../EmptyAreaWithCTA';
 
@@ -134,12 +135,13 @@ function useColumns(alertManagerSourceName: string, hideActions = false, setMute
 
 function renderTimeIntervals(timeIntervals: TimeInterval[]) {
   return timeIntervals.map((interval, index) => {
-    const { times, weekdays, days_of_month, months, years } = interval;
-    const timeString = getTimeString(times);
+    const { times, weekdays, days_of_month, months, years, location, date_ranges } = interval;
+    const timeString = getTimeString(times, location);
     const weekdayString = getWeekdayString(weekdays);
     const daysString = getDaysOfMonthString(days_of_month);
     const monthsString = getMonthsString(months);
     const yearsString = getYearsString(years);
+    const dateRangesString = getDateRangesString(date_ranges);
 
     return (
       <React.Fragment key={JSON.stringify(interval) + index}>
@@ -147,6 +149,12 @@ function renderTimeIntervals(timeIntervals: TimeInterval[]) {
         <br />
         {[daysString, monthsString, yearsString].join(' | ')}
         <br />
+        {date_ranges && (
+          <>
+            {dateRangesString}
+            <br />
+          </>
+        )}
       </React.Fragment>
     );
   });
@@ -14,6 +14,7 @@ const options: SelectableValue[] = [
   { value: GrafanaAlertStateDecision.NoData, label: 'No Data' },
   { value: GrafanaAlertStateDecision.OK, label: 'OK' },
   { value: GrafanaAlertStateDecision.Error, label: 'Error' },
+  { value: GrafanaAlertStateDecision.KeepPreviousState, label: 'Keep Last State' },
 ];
 
 export const GrafanaAlertStatePicker: FC<Props> = ({ includeNoData, includeError, ...props }) => {
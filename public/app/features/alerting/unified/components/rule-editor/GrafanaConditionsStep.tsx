@@ -22,6 +22,10 @@ const forValidationOptions: RegisterOptions = {
   pattern: durationValidationPattern,
 };
 
+const keepFiringForValidationOptions: RegisterOptions = {
+  pattern: durationValidationPattern,
+};
+
 const evaluateEveryValidationOptions: RegisterOptions = {
   required: {
     value: true,
@@ -53,6 +57,7 @@ export const GrafanaConditionsStep: FC = () => {
 
   const evaluateEveryId = 'eval-every-input';
   const evaluateForId = 'eval-for-input';
+  const keepFiringForId = 'keep-firing-for-input';
 
   return (
     <RuleEditorSection stepNo={3} title="Define alert conditions">
@@ -89,6 +94,26 @@ export const GrafanaConditionsStep: FC = () => {
           >
             <Input id={evaluateForId} width={8} {...register('evaluateFor', forValidationOptions)} />
           </Field>
+          <InlineLabel
+            htmlFor={keepFiringForId}
+            width={16}
+            tooltip="Once the condition is no longer breached, a firing alert will keep firing for this duration before it is resolved. Leave it empty to resolve it immediately."
+          >
+            Keep firing for
+          </InlineLabel>
+          <Field
+            className={styles.inlineField}
+            error={errors.keepFiringFor?.message}
+            invalid={!!errors.keepFiringFor?.message}
+            validationMessageHorizontalOverflow={true}
+          >
+            <Input
+              id={keepFiringForId}
+              width={8}
+              placeholder="0s"
+              {...register('keepFiringFor', keepFiringForValidationOptions)}
+            />
+          </Field>
         </div>
       </Field>
       <GrafanaConditionEvalWarning />
@@ -0,0 +1,138 @@
+import React, { useMemo, useState } from 'react';
+import { useAsync } from 'react-use';
+import { css } from '@emotion/css';
+import { dateTimeFormat, GrafanaTheme2, SelectableValue } from '@grafana/data';
+import { Alert, Icon, LoadingPlaceholder, MultiSelect, useStyles2 } from '@grafana/ui';
+import { CombinedRule } from 'app/types/unified-alerting';
+import { Labels, StateHistoryEntryDTO } from 'app/types/unified-alerting-dto';
+import { fetchGrafanaRuleStateHistory } from '../../api/ruler';
+import { isAlertingRule, isGrafanaRulerRule } from '../../utils/rules';
+import { DetailsField } from '../DetailsField';
+import { DynamicTable, DynamicTableColumnProps, DynamicTableItemProps } from '../DynamicTable';
+import { AlertLabels } from '../AlertLabels';
+import { AlertLabel } from '../AlertLabel';
+import { AlertStateTag } from './AlertStateTag';
+
+type StateHistoryRow = DynamicTableItemProps<StateHistoryEntryDTO>;
+
+type Props = {
+  rule: CombinedRule;
+};
+
+export function RuleDetailsStateHistory({ rule }: Props): JSX.Element | null {
+  const styles = useStyles2(getStyles);
+  const [selectedInstances, setSelectedInstances] = useState<string[]>([]);
+  const ruleUID = isGrafanaRulerRule(rule.rulerRule) ? rule.rulerRule.grafana_alert.uid : undefined;
+
+  // the label sets of the current instances of the rule, by their readable form
+  const instanceLabels = useMemo(() => {
+    const labelSets = new Map<string, Labels>();
+    if (isAlertingRule(rule.promRule)) {
+      rule.promRule.alerts?.forEach(({ labels }) => labelSets.set(formatLabels(labels), labels));
+    }
+    return labelSets;
+  }, [rule.promRule]);
+
+  const instanceOptions = useMemo(
+    () =>
+      Array.from(instanceLabels.keys()).map<SelectableValue<string>>((key) => ({
+        label: key,
+        value: key,
+      })),
+    [instanceLabels]
+  );
+
+  const {
+    loading,
+    error,
+    value: instances = [],
+  } = useAsync(async () => {
+    if (!ruleUID) {
+      return [];
+    }
+    const labelSets = selectedInstances.flatMap((key) => instanceLabels.get(key) ?? []);
+    return fetchGrafanaRuleStateHistory(ruleUID, labelSets);
+  }, [ruleUID, selectedInstances, instanceLabels]);
+
+  if (!ruleUID) {
+    return null;
+  }
+
+  const columns: Array<DynamicTableColumnProps<StateHistoryEntryDTO>> = [
+    { id: 'state', label: 'State', size: 'max-content', renderCell: renderStateCell },
+    { id: 'value', label: '', size: 'auto', renderCell: renderValueCell },
+    { id: 'timestamp', label: 'Time', size: 'max-content', renderCell: renderTimestampCell },
+  ];
+
+  return (
+    <DetailsField label="State history" horizontal={true}>
+      <MultiSelect
+        aria-label="state history instances"
+        className={styles.instanceSelect}
+        value={selectedInstances}
+        placeholder="All instances"
+        prefix={<Icon name="tag-alt" />}
+        onChange={(items) => setSelectedInstances(items.map(({ value }) => value as string))}
+        options={instanceOptions}
+        menuShouldPortal
+      />
+      {loading && <LoadingPlaceholder text="Loading history..." />}
+      {error && !loading && <Alert title="Failed to fetch alert state history">{error.message}</Alert>}
+      {!loading && !error && !instances.length && <span>No state changes recorded.</span>}
+      {!loading &&
+        instances.map((instance) => (
+          <div key={formatLabels(instance.labels)} className={styles.instance}>
+            <AlertLabels labels={instance.labels} />
+            <DynamicTable cols={columns} items={toRows(instance.history)} />
+          </div>
+        ))}
+    </DetailsField>
+  );
+}
+
+function renderStateCell(item: StateHistoryRow) {
+  return (
+    <div className={StateChangeStyle}>
+      <AlertStateTag state={item.data.previousState} />
+      <Icon name="arrow-right" />
+      <AlertStateTag state={item.data.state} />
+    </div>
+  );
+}
+
+function renderValueCell(item: StateHistoryRow) {
+  if (item.data.error) {
+    return item.data.error;
+  }
+  return Object.entries(item.data.values ?? {}).map(([refId, value]) => (
+    <AlertLabel key={refId} labelKey={refId} value={value} />
+  ));
+}
+
+function renderTimestampCell(item: StateHistoryRow) {
+  return dateTimeFormat(item.data.timestamp);
+}
+
+function formatLabels(labels: Labels): string {
+  const pairs = Object.entries(labels).map(([name, value]) => `${name}=${value}`);
+  return pairs.join(', ');
+}
+
+function toRows(history: StateHistoryEntryDTO[]): StateHistoryRow[] {
+  return history.map((entry, index) => ({ id: index, data: entry }));
+}
+
+const StateChangeStyle = css`
+  display: flex;
+  align-items: center;
+  gap: 4px;
+`;
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  instanceSelect: css`
+    margin-bottom: ${theme.spacing(1)};
+  `,
+  instance: css`
+    margin-bottom: ${theme.spacing(2)};
+  `,
+});
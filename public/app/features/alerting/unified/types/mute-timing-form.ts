@@ -11,4 +11,6 @@ export type MuteTimingIntervalFields = {
   days_of_month: string;
   months: string;
   years: string;
+  location: string;
+  date_ranges: string;
 };
@@ -23,6 +23,7 @@ export interface RuleFormValues {
   folder: { title: string; id: number } | null;
   evaluateEvery: string;
   evaluateFor: string;
+  keepFiringFor: string;
 
   // cortex / loki rules
   namespace: string;
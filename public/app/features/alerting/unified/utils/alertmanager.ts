@@ -5,6 +5,7 @@ import {
   Matcher,
   TimeInterval,
   TimeRange,
+  DateRange,
 } from 'app/plugins/datasource/alertmanager/types';
 import { Labels } from 'app/types/unified-alerting-dto';
 import { MatcherFieldValue } from '../types/silence-form';
@@ -205,20 +206,31 @@ export function getAlertmanagerByUid(uid?: string) {
 }
 
 export function timeIntervalToString(timeInterval: TimeInterval): string {
-  const { times, weekdays, days_of_month, months, years } = timeInterval;
-  const timeString = getTimeString(times);
+  const { times, weekdays, days_of_month, months, years, location, date_ranges } = timeInterval;
+  const timeString = getTimeString(times, location);
   const weekdayString = getWeekdayString(weekdays);
   const daysString = getDaysOfMonthString(days_of_month);
   const monthsString = getMonthsString(months);
   const yearsString = getYearsString(years);
+  const dateRangesString = getDateRangesString(date_ranges);
 
-  return [timeString, weekdayString, daysString, monthsString, yearsString].join(', ');
+  return [timeString, weekdayString, daysString, monthsString, yearsString, dateRangesString].join(', ');
 }
 
-export function getTimeString(times?: TimeRange[]): string {
+export function getTimeString(times?: TimeRange[], location?: string): string {
+  const zone = location || 'UTC';
   return (
     'Times: ' +
-    (times ? times?.map(({ start_time, end_time }) => `${start_time} - ${end_time} UTC`).join(' and ') : 'All')
+    (times ? times?.map(({ start_time, end_time }) => `${start_time} - ${end_time} ${zone}`).join(' and ') : 'All')
+  );
+}
+
+export function getDateRangesString(dateRanges?: DateRange[]): string {
+  return (
+    'Dates: ' +
+    (dateRanges
+      ?.map(({ start_date, end_date }) => (start_date === end_date ? start_date : `${start_date} - ${end_date}`))
+      .join(', ') ?? 'All')
   );
 }
 
@@ -1,4 +1,4 @@
-import { MuteTimeInterval, TimeInterval } from 'app/plugins/datasource/alertmanager/types';
+import { DateRange, MuteTimeInterval, TimeInterval } from 'app/plugins/datasource/alertmanager/types';
 import { omitBy, isUndefined } from 'lodash';
 import { MuteTimingFields, MuteTimingIntervalFields } from '../types/mute-timing-form';
 
@@ -25,6 +25,8 @@ export const defaultTimeInterval: MuteTimingIntervalFields = {
   days_of_month: '',
   months: '',
   years: '',
+  location: '',
+  date_ranges: '',
 };
 
 export const validateArrayField = (value: string, validateValue: (input: string) => boolean, invalidText: string) => {
@@ -44,15 +46,28 @@ const convertStringToArray = (str: string) => {
   return str ? str.split(',').map((s) => s.trim()) : undefined;
 };
 
+export const isValidDate = (date: string) => /^\d{4}-\d{2}-\d{2}$/.test(date) && !isNaN(Date.parse(date));
+
+// A date range is either a single date or an inclusive range of dates, such as 2022-12-24:2022-12-26
+const convertStringToDateRange = (str: string): DateRange => {
+  const [start_date, end_date = start_date] = str.split(':');
+  return { start_date, end_date };
+};
+
+export const dateRangeToString = ({ start_date, end_date }: DateRange) =>
+  start_date === end_date ? start_date : `${start_date}:${end_date}`;
+
 export const createMuteTiming = (fields: MuteTimingFields): MuteTimeInterval => {
   const timeIntervals: TimeInterval[] = fields.time_intervals.map(
-    ({ times, weekdays, days_of_month, months, years }) => {
+    ({ times, weekdays, days_of_month, months, years, location, date_ranges }) => {
       const interval = {
         times: times.filter(({ start_time, end_time }) => !!start_time && !!end_time),
         weekdays: convertStringToArray(weekdays)?.map((v) => v.toLowerCase()),
         days_of_month: convertStringToArray(days_of_month),
         months: convertStringToArray(months),
         years: convertStringToArray(years),
+        location: location ? location.trim() : undefined,
+        date_ranges: convertStringToArray(date_ranges)?.map(convertStringToDateRange),
       };
 
       return omitBy(interval, isUndefined);
@@ -52,6 +52,7 @@ export const getDefaultFormValues = (): RuleFormValues =>
     execErrState: GrafanaAlertStateDecision.Alerting,
     evaluateEvery: '1m',
     evaluateFor: '5m',
+    keepFiringFor: '',
 
     // cortex / loki
     group: '',
@@ -86,7 +87,7 @@ function listifyLabelsOrAnnotations(item: Labels | Annotations | undefined): Arr
 }
 
 export function formValuesToRulerGrafanaRuleDTO(values: RuleFormValues): PostableRuleGrafanaRuleDTO {
-  const { name, condition, noDataState, execErrState, evaluateFor, queries } = values;
+  const { name, condition, noDataState, execErrState, evaluateFor, keepFiringFor, queries } = values;
   if (condition) {
     return {
       grafana_alert: {
@@ -97,6 +98,7 @@ export function formValuesToRulerGrafanaRuleDTO(values: RuleFormValues): Postabl
         data: queries,
       },
       for: evaluateFor,
+      keep_firing_for: keepFiringFor || undefined,
       annotations: arrayToRecord(values.annotations || []),
       labels: arrayToRecord(values.labels || []),
     };
@@ -116,6 +118,7 @@ export function rulerRuleToFormValues(ruleWithLocation: RuleWithLocation): RuleF
         name: ga.title,
         type: RuleFormType.grafana,
         evaluateFor: rule.for || '0',
+        keepFiringFor: rule.keep_firing_for || '',
         evaluateEvery: group.interval || defaultFormValues.evaluateEvery,
         noDataState: ga.no_data_state,
         execErrState: ga.exec_err_state,
@@ -1,7 +1,19 @@
 import React, { FC } from 'react';
 import { SelectableValue } from '@grafana/data';
 import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
-import { ExpressionQuery, ExpressionQuerySettings, ReducerMode, reducerMode, reducerTypes } from '../types';
+import {
+  ExpressionQuery,
+  ExpressionQuerySettings,
+  getPercentile,
+  percentileReducer,
+  percentileReducerOf,
+  ReducerMode,
+  reducerMode,
+  reducerTypes,
+} from '../types';
+
+const reducerOptions = [...reducerTypes, percentileReducer];
+const defaultPercentile = 95;
 
 interface Props {
   labelWidth: number;
@@ -11,16 +23,26 @@ interface Props {
 }
 
 export const Reduce: FC<Props> = ({ labelWidth, onChange, refIds, query }) => {
-  const reducer = reducerTypes.find((o) => o.value === query.reducer);
+  const percentile = getPercentile(query.reducer);
+  const reducer = percentile !== undefined ? percentileReducer : reducerTypes.find((o) => o.value === query.reducer);
 
   const onRefIdChange = (value: SelectableValue<string>) => {
     onChange({ ...query, expression: value.value });
   };
 
   const onSelectReducer = (value: SelectableValue<string>) => {
+    if (value.value === percentileReducer.value) {
+      onChange({ ...query, reducer: percentileReducerOf(percentile ?? defaultPercentile) });
+      return;
+    }
     onChange({ ...query, reducer: value.value });
   };
 
+  const onPercentileChanged = (e: React.FormEvent<HTMLInputElement>) => {
+    const value = e.currentTarget.valueAsNumber;
+    onChange({ ...query, reducer: percentileReducerOf(isNaN(value) ? 0 : value) });
+  };
+
   const onSettingsChanged = (settings: ExpressionQuerySettings) => {
     onChange({ ...query, settings: settings });
   };
@@ -53,6 +75,17 @@ export const Reduce: FC<Props> = ({ labelWidth, onChange, refIds, query }) => {
 
   const mode = query.settings?.mode ?? ReducerMode.Strict;
 
+  const percentileField = () => {
+    if (percentile === undefined) {
+      return;
+    }
+    return (
+      <InlineField label="Percentile" labelWidth={labelWidth}>
+        <Input type="number" min={0} max={100} width={10} onChange={onPercentileChanged} value={percentile} />
+      </InlineField>
+    );
+  };
+
   const replaceWithNumber = () => {
     if (mode !== ReducerMode.ReplaceNonNumbers) {
       return;
@@ -67,8 +100,9 @@ export const Reduce: FC<Props> = ({ labelWidth, onChange, refIds, query }) => {
   return (
     <InlineFieldRow>
       <InlineField label="Function" labelWidth={labelWidth}>
-        <Select menuShouldPortal options={reducerTypes} value={reducer} onChange={onSelectReducer} width={25} />
+        <Select menuShouldPortal options={reducerOptions} value={reducer} onChange={onSelectReducer} width={25} />
       </InlineField>
+      {percentileField()}
       <InlineField label="Input" labelWidth={labelWidth}>
         <Select menuShouldPortal onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
       </InlineField>
@@ -22,8 +22,42 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: ReducerID.median, label: 'Median', description: 'Get the median value' },
+  { value: ReducerID.stdDev, label: 'Standard deviation', description: 'Get the standard deviation of all values' },
+  {
+    value: ReducerID.nonNullCount,
+    label: 'Count non-null',
+    description: 'Get the number of values that are not null or NaN',
+  },
+  { value: ReducerID.diff, label: 'Difference', description: 'Get the difference between the last and first values' },
+  {
+    value: ReducerID.delta,
+    label: 'Delta',
+    description: 'Get the cumulative increase, treating decreases as counter resets',
+  },
 ];
 
+/**
+ * The percentile reducer takes the percentile as a parameter, and is sent as `percentile(N)`.
+ */
+export const percentileReducer: SelectableValue<string> = {
+  value: 'percentile',
+  label: 'Percentile',
+  description: 'Get the N-th percentile of all values',
+};
+
+const percentileRegex = /^percentile\(\s*([0-9]*\.?[0-9]+)\s*\)$/i;
+
+export function getPercentile(reducer?: string): number | undefined {
+  const match = reducer?.match(percentileRegex);
+  return match ? parseFloat(match[1]) : undefined;
+}
+
+export function percentileReducerOf(percentile: number): string {
+  return `percentile(${percentile})`;
+}
+
 export enum ReducerMode {
   Strict = '', // backend API wants an empty string to support "strict" mode
   ReplaceNonNumbers = 'replaceNN',
@@ -278,16 +278,25 @@ export enum AlertManagerImplementation {
 }
 
 export interface TimeRange {
-  /** Times are in format `HH:MM` in UTC */
+  /** Times are in format `HH:MM` in the location of the time interval, UTC by default */
   start_time: string;
   end_time: string;
 }
+export interface DateRange {
+  /** Dates are in format `YYYY-MM-DD` and inclusive */
+  start_date: string;
+  end_date: string;
+}
 export interface TimeInterval {
   times?: TimeRange[];
   weekdays?: string[];
   days_of_month?: string[];
   months?: string[];
   years?: string[];
+  /** Time zone of the IANA Time Zone database, such as Europe/Berlin. Grafana Alertmanager only */
+  location?: string;
+  /** Grafana Alertmanager only */
+  date_ranges?: DateRange[];
 }
 
 export type MuteTimeInterval = {
@@ -94,10 +94,15 @@ export interface RulerAlertingRuleDTO extends RulerRuleBaseDTO {
   annotations?: Annotations;
 }
 
+// Values of the no_data_state and exec_err_state fields of Grafana managed rules. no_data_state accepts Alerting,
+// NoData, OK and KeepPreviousState, exec_err_state accepts Alerting, Error and KeepPreviousState.
 export enum GrafanaAlertStateDecision {
   Alerting = 'Alerting',
   NoData = 'NoData',
+  // not accepted by no_data_state nor exec_err_state, use KeepPreviousState
   KeepLastState = 'KeepLastState',
+  // keeps the instances in the state they were in before the query returned no data or the evaluation failed
+  KeepPreviousState = 'KeepLast',
   OK = 'OK',
   Error = 'Error',
 }
@@ -133,6 +138,7 @@ export interface GrafanaRuleDefinition extends PostableGrafanaRuleDefinition {
 export interface RulerGrafanaRuleDTO {
   grafana_alert: GrafanaRuleDefinition;
   for: string;
+  keep_firing_for?: string;
   annotations: Annotations;
   labels: Labels;
 }
@@ -140,6 +146,7 @@ export interface RulerGrafanaRuleDTO {
 export interface PostableRuleGrafanaRuleDTO {
   grafana_alert: PostableGrafanaRuleDefinition;
   for: string;
+  keep_firing_for?: string;
   annotations: Annotations;
   labels: Labels;
 }
@@ -157,3 +164,20 @@ export type RulerRuleGroupDTO<R = RulerRuleDTO> = {
 export type PostableRulerRuleGroupDTO = RulerRuleGroupDTO<PostableRuleDTO>;
 
 export type RulerRulesConfigDTO = { [namespace: string]: RulerRuleGroupDTO[] };
+
+export interface StateHistoryEntryDTO {
+  previousState: GrafanaAlertState;
+  state: GrafanaAlertState;
+  values?: Record<string, string>;
+  error?: string;
+  timestamp: string;
+}
+
+export interface InstanceStateHistoryDTO {
+  labels: Labels;
+  history: StateHistoryEntryDTO[];
+}
+
+export interface RuleStateHistoryDTO {
+  instances: InstanceStateHistoryDTO[];
+}